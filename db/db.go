@@ -0,0 +1,26 @@
+// Package db embeds the database migrations so that they can be applied by
+// the binary itself.
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations
+var migrations embed.FS
+
+// Migrations returns the migrations for the given database dialect.
+func Migrations(dialect string) fs.FS {
+	dir := "migrations"
+	if dialect == "sqlite" {
+		dir = "migrations/sqlite"
+	}
+
+	sub, err := fs.Sub(migrations, dir)
+	if err != nil {
+		panic(err)
+	}
+
+	return sub
+}
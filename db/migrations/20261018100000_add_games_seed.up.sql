@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN seed BIGINT NOT NULL DEFAULT 0
\ No newline at end of file
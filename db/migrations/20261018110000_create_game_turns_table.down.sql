@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS game_turns;
+
+ALTER TABLE games DROP COLUMN round;
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE rooms DROP COLUMN leaderboard_size;
+
+ALTER TABLE rooms DROP COLUMN fuse_timeout_seconds;
+
+ALTER TABLE rooms DROP COLUMN cook_cap;
+
+ALTER TABLE rooms DROP COLUMN steal_enabled;
+
+ALTER TABLE rooms DROP COLUMN explode_multiplier;
+
+ALTER TABLE rooms DROP COLUMN allowed_potato_kinds;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS seasons (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  number INT NOT NULL,
+  started_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  ended_at TIMESTAMPTZ,
+  PRIMARY KEY (namespace, room_id, number),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS season_standings (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  season INT NOT NULL,
+  user_id TEXT NOT NULL,
+  count INT NOT NULL,
+  PRIMARY KEY (namespace, room_id, season, user_id),
+  FOREIGN KEY (namespace, room_id, season) REFERENCES seasons (namespace, room_id, number) ON DELETE CASCADE
+);
+
+INSERT INTO seasons (namespace, room_id, number, started_at)
+SELECT namespace, id, 1, created_at FROM rooms
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS user_stats (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  season INT NOT NULL,
+  user_id TEXT NOT NULL,
+  games_played INT NOT NULL DEFAULT 0,
+  games_survived INT NOT NULL DEFAULT 0,
+  tosses INT NOT NULL DEFAULT 0,
+  steals INT NOT NULL DEFAULT 0,
+  cooks INT NOT NULL DEFAULT 0,
+  kills INT NOT NULL DEFAULT 0,
+  longest_hold_streak INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (namespace, room_id, season, user_id),
+  FOREIGN KEY (namespace, room_id, season) REFERENCES seasons (namespace, room_id, number) ON DELETE CASCADE
+)
\ No newline at end of file
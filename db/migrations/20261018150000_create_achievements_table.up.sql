@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS achievements (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  user_id TEXT NOT NULL,
+  achievement_id TEXT NOT NULL,
+  unlocked_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, room_id, user_id, achievement_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
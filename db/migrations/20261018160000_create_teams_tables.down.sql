@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS team_losses;
+
+DROP TABLE IF EXISTS game_teams;
\ No newline at end of file
@@ -0,0 +1,25 @@
+ALTER TABLE rooms DROP COLUMN potato_limit;
+
+DELETE FROM game_teams WHERE potato_id <> 1;
+
+ALTER TABLE game_teams DROP CONSTRAINT game_teams_pkey;
+
+ALTER TABLE game_teams DROP COLUMN potato_id;
+
+ALTER TABLE game_teams ADD PRIMARY KEY (namespace, channel_id, round, user_id);
+
+DELETE FROM game_turns WHERE potato_id <> 1;
+
+ALTER TABLE game_turns DROP CONSTRAINT game_turns_pkey;
+
+ALTER TABLE game_turns DROP COLUMN potato_id;
+
+ALTER TABLE game_turns ADD PRIMARY KEY (namespace, channel_id, round, turn);
+
+DELETE FROM games WHERE potato_id <> 1;
+
+ALTER TABLE games DROP CONSTRAINT games_pkey;
+
+ALTER TABLE games DROP COLUMN potato_id;
+
+ALTER TABLE games ADD PRIMARY KEY (namespace, room_id, channel_id)
\ No newline at end of file
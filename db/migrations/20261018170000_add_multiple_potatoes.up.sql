@@ -0,0 +1,19 @@
+ALTER TABLE games ADD COLUMN potato_id INT NOT NULL DEFAULT 1;
+
+ALTER TABLE games DROP CONSTRAINT games_pkey;
+
+ALTER TABLE games ADD PRIMARY KEY (namespace, room_id, channel_id, potato_id);
+
+ALTER TABLE game_turns ADD COLUMN potato_id INT NOT NULL DEFAULT 1;
+
+ALTER TABLE game_turns DROP CONSTRAINT game_turns_pkey;
+
+ALTER TABLE game_turns ADD PRIMARY KEY (namespace, channel_id, potato_id, round, turn);
+
+ALTER TABLE game_teams ADD COLUMN potato_id INT NOT NULL DEFAULT 1;
+
+ALTER TABLE game_teams DROP CONSTRAINT game_teams_pkey;
+
+ALTER TABLE game_teams ADD PRIMARY KEY (namespace, channel_id, potato_id, round, user_id);
+
+ALTER TABLE rooms ADD COLUMN potato_limit INT NOT NULL DEFAULT 1
\ No newline at end of file
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS tournaments (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  number INT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL DEFAULT 0,
+  winner_user_id TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ended_at TIMESTAMPTZ,
+  PRIMARY KEY (namespace, room_id, number),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tournament_players (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  tournament INT NOT NULL,
+  user_id TEXT NOT NULL,
+  registered_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, room_id, tournament, user_id),
+  FOREIGN KEY (namespace, room_id, tournament) REFERENCES tournaments (namespace, room_id, number) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tournament_heats (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  tournament INT NOT NULL,
+  round INT NOT NULL,
+  heat INT NOT NULL,
+  channel_id TEXT NOT NULL DEFAULT '',
+  loser_user_id TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (namespace, room_id, tournament, round, heat),
+  FOREIGN KEY (namespace, room_id, tournament) REFERENCES tournaments (namespace, room_id, number) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tournament_heat_players (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  tournament INT NOT NULL,
+  round INT NOT NULL,
+  heat INT NOT NULL,
+  user_id TEXT NOT NULL,
+  seat INT NOT NULL,
+  PRIMARY KEY (namespace, room_id, tournament, round, heat, user_id),
+  FOREIGN KEY (namespace, room_id, tournament, round, heat) REFERENCES tournament_heats (namespace, room_id, tournament, round, heat) ON DELETE CASCADE
+)
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS wins;
+
+DROP TABLE IF EXISTS game_players;
+
+DROP TABLE IF EXISTS game_lobbies;
\ No newline at end of file
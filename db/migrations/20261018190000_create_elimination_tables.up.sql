@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS game_lobbies (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  user_id TEXT NOT NULL,
+  joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, channel_id, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS game_players (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  potato_id INT NOT NULL,
+  round INT NOT NULL,
+  user_id TEXT NOT NULL,
+  PRIMARY KEY (namespace, channel_id, potato_id, round, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS wins (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  user_id TEXT NOT NULL,
+  count INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (namespace, room_id, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
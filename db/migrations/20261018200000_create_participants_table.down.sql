@@ -0,0 +1 @@
+DROP TABLE IF EXISTS participants
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS participants (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  user_id TEXT NOT NULL,
+  opted_in_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, room_id, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS channel_activity
\ No newline at end of file
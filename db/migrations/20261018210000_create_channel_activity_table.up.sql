@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS channel_activity (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  user_id TEXT NOT NULL,
+  active_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, channel_id, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
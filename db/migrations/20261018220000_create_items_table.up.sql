@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS items (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  user_id TEXT NOT NULL,
+  item_id TEXT NOT NULL,
+  count INT NOT NULL DEFAULT 0,
+  armed BOOLEAN NOT NULL DEFAULT false,
+  PRIMARY KEY (namespace, room_id, user_id, item_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
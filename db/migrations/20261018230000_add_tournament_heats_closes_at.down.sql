@@ -0,0 +1 @@
+ALTER TABLE tournament_heats DROP COLUMN closes_at
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS rooms (
+  namespace TEXT NOT NULL,
+  id TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, id)
+)
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS deaths;
\ No newline at end of file
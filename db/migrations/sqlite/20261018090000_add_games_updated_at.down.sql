@@ -0,0 +1 @@
+ALTER TABLE games DROP COLUMN updated_at
\ No newline at end of file
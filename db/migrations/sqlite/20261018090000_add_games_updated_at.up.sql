@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN updated_at TIMESTAMP
\ No newline at end of file
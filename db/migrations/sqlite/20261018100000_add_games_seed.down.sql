@@ -0,0 +1 @@
+ALTER TABLE games DROP COLUMN seed
\ No newline at end of file
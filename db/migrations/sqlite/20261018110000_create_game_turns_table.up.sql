@@ -0,0 +1,18 @@
+ALTER TABLE games ADD COLUMN round INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS game_turns (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL,
+  turn INT NOT NULL,
+  action TEXT NOT NULL,
+  actor_user_id TEXT NOT NULL,
+  target_user_id TEXT NOT NULL,
+  heat_level INT NOT NULL,
+  explode_chance INT NOT NULL,
+  exploded BOOLEAN NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, channel_id, round, turn),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
@@ -0,0 +1,11 @@
+ALTER TABLE rooms ADD COLUMN allowed_potato_kinds TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE rooms ADD COLUMN explode_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1;
+
+ALTER TABLE rooms ADD COLUMN steal_enabled BOOLEAN NOT NULL DEFAULT true;
+
+ALTER TABLE rooms ADD COLUMN cook_cap INT NOT NULL DEFAULT 0;
+
+ALTER TABLE rooms ADD COLUMN fuse_timeout_seconds INT NOT NULL DEFAULT 0;
+
+ALTER TABLE rooms ADD COLUMN leaderboard_size INT NOT NULL DEFAULT 10;
\ No newline at end of file
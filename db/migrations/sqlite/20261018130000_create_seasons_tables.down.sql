@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS season_standings;
+
+DROP TABLE IF EXISTS seasons;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS game_teams (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL,
+  user_id TEXT NOT NULL,
+  team TEXT NOT NULL,
+  PRIMARY KEY (namespace, channel_id, round, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_losses (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  team TEXT NOT NULL,
+  count INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (namespace, room_id, team),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+)
\ No newline at end of file
@@ -0,0 +1,67 @@
+ALTER TABLE rooms DROP COLUMN potato_limit;
+
+CREATE TABLE game_teams_old (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL,
+  user_id TEXT NOT NULL,
+  team TEXT NOT NULL,
+  PRIMARY KEY (namespace, channel_id, round, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+INSERT INTO game_teams_old (namespace, room_id, channel_id, round, user_id, team)
+SELECT namespace, room_id, channel_id, round, user_id, team FROM game_teams WHERE potato_id = 1;
+
+DROP TABLE game_teams;
+
+ALTER TABLE game_teams_old RENAME TO game_teams;
+
+CREATE TABLE game_turns_old (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL,
+  turn INT NOT NULL,
+  action TEXT NOT NULL,
+  actor_user_id TEXT NOT NULL,
+  target_user_id TEXT NOT NULL,
+  heat_level INT NOT NULL,
+  explode_chance INT NOT NULL,
+  exploded BOOLEAN NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (namespace, channel_id, round, turn),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+INSERT INTO game_turns_old (namespace, room_id, channel_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded, created_at)
+SELECT namespace, room_id, channel_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded, created_at FROM game_turns WHERE potato_id = 1;
+
+DROP TABLE game_turns;
+
+ALTER TABLE game_turns_old RENAME TO game_turns;
+
+CREATE TABLE games_old (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  potato_kind TEXT NOT NULL,
+  heat_level INT NOT NULL,
+  holder_user_id TEXT NOT NULL,
+  turns INT NOT NULL,
+  finished BOOLEAN NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP,
+  seed BIGINT NOT NULL DEFAULT 0,
+  round INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (namespace, room_id, channel_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+INSERT INTO games_old (namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round)
+SELECT namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round FROM games WHERE potato_id = 1;
+
+DROP TABLE games;
+
+ALTER TABLE games_old RENAME TO games
\ No newline at end of file
@@ -0,0 +1,70 @@
+CREATE TABLE games_new (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  potato_kind TEXT NOT NULL,
+  heat_level INT NOT NULL,
+  holder_user_id TEXT NOT NULL,
+  turns INT NOT NULL,
+  finished BOOLEAN NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP,
+  seed BIGINT NOT NULL DEFAULT 0,
+  round INT NOT NULL DEFAULT 0,
+  potato_id INT NOT NULL DEFAULT 1,
+  PRIMARY KEY (namespace, room_id, channel_id, potato_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+INSERT INTO games_new (namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round)
+SELECT namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round FROM games;
+
+DROP TABLE games;
+
+ALTER TABLE games_new RENAME TO games;
+
+CREATE TABLE game_turns_new (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL,
+  turn INT NOT NULL,
+  action TEXT NOT NULL,
+  actor_user_id TEXT NOT NULL,
+  target_user_id TEXT NOT NULL,
+  heat_level INT NOT NULL,
+  explode_chance INT NOT NULL,
+  exploded BOOLEAN NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  potato_id INT NOT NULL DEFAULT 1,
+  PRIMARY KEY (namespace, channel_id, potato_id, round, turn),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+INSERT INTO game_turns_new (namespace, room_id, channel_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded, created_at)
+SELECT namespace, room_id, channel_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded, created_at FROM game_turns;
+
+DROP TABLE game_turns;
+
+ALTER TABLE game_turns_new RENAME TO game_turns;
+
+CREATE TABLE game_teams_new (
+  namespace TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  round INT NOT NULL,
+  user_id TEXT NOT NULL,
+  team TEXT NOT NULL,
+  potato_id INT NOT NULL DEFAULT 1,
+  PRIMARY KEY (namespace, channel_id, potato_id, round, user_id),
+  FOREIGN KEY (namespace, room_id) REFERENCES rooms (namespace, id) ON DELETE CASCADE
+);
+
+INSERT INTO game_teams_new (namespace, room_id, channel_id, round, user_id, team)
+SELECT namespace, room_id, channel_id, round, user_id, team FROM game_teams;
+
+DROP TABLE game_teams;
+
+ALTER TABLE game_teams_new RENAME TO game_teams;
+
+ALTER TABLE rooms ADD COLUMN potato_limit INT NOT NULL DEFAULT 1
\ No newline at end of file
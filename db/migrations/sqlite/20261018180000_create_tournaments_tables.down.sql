@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS tournament_heat_players;
+
+DROP TABLE IF EXISTS tournament_heats;
+
+DROP TABLE IF EXISTS tournament_players;
+
+DROP TABLE IF EXISTS tournaments;
\ No newline at end of file
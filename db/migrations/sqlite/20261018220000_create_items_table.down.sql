@@ -0,0 +1 @@
+DROP TABLE IF EXISTS items
\ No newline at end of file
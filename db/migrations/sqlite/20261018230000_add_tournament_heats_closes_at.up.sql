@@ -0,0 +1 @@
+ALTER TABLE tournament_heats ADD COLUMN closes_at TIMESTAMP
\ No newline at end of file
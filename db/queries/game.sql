@@ -1,38 +1,153 @@
 -- name: GetGame :one
 SELECT * FROM games
-WHERE namespace = $1 AND channel_id = $2
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3
 LIMIT 1;
 
--- name: UpsertGame :one
+-- name: ListGames :many
+SELECT * FROM games
+WHERE namespace = $1 AND channel_id = $2
+ORDER BY potato_id;
+
+-- name: ListOngoingGames :many
+SELECT * FROM games
+WHERE finished = false;
+
+-- name: InsertGame :one
 INSERT INTO games (
-  namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished
+  namespace, room_id, channel_id, potato_id, potato_kind, heat_level, holder_user_id, turns, finished, seed, updated_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
-) ON CONFLICT (namespace, room_id, channel_id)
-  DO UPDATE SET potato_kind = $4, heat_level = $5, holder_user_id = $6, turns = $7, finished = $8
-  WHERE games.namespace = $1 AND games.room_id = $2 AND games.channel_id = $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP
+) ON CONFLICT (namespace, room_id, channel_id, potato_id) DO NOTHING
 RETURNING *;
 
--- name: UpdateHolder :one
+-- name: RestartGame :one
 UPDATE games
-SET holder_user_id = $3
-WHERE namespace = $1 AND channel_id = $2
+SET potato_kind = $5, heat_level = $6, holder_user_id = $7, turns = $8, finished = $9, seed = $10, round = round + 1, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
 RETURNING *;
 
--- name: IncrementTurns :one
+-- name: AdvanceTurn :one
 UPDATE games
-SET turns = turns + 1
-WHERE namespace = $1 AND channel_id = $2
+SET holder_user_id = sqlc.arg(holder_user_id), heat_level = heat_level + sqlc.arg(heat_increase), turns = turns + 1, finished = sqlc.arg(finished), updated_at = CURRENT_TIMESTAMP
+WHERE namespace = sqlc.arg(namespace) AND channel_id = sqlc.arg(channel_id) AND potato_id = sqlc.arg(potato_id) AND holder_user_id = sqlc.arg(expected_holder_user_id) AND turns = sqlc.arg(expected_turns) AND finished = false
 RETURNING *;
 
--- name: IncreaseHeatLevel :one
+-- name: EndGame :one
 UPDATE games
-SET heat_level = heat_level + sqlc.arg(amount)::int
-WHERE namespace = $1 AND channel_id = $2
+SET finished = true, turns = turns + 1, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND turns = $4 AND finished = false
 RETURNING *;
 
--- name: EndGame :one
+-- name: RetireGame :exec
 UPDATE games
-SET finished = true
+SET finished = true, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND finished = false;
+
+-- name: InsertTurn :exec
+INSERT INTO game_turns (
+  namespace, room_id, channel_id, potato_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+);
+
+-- name: ListTurns :many
+SELECT * FROM game_turns
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+ORDER BY turn;
+
+-- name: CountGamesPlayed :one
+SELECT COUNT(*) AS games FROM (
+  SELECT DISTINCT channel_id, potato_id, round FROM game_turns
+  WHERE namespace = sqlc.arg(namespace) AND room_id = sqlc.arg(room_id) AND (actor_user_id = sqlc.arg(user_id) OR target_user_id = sqlc.arg(user_id))
+) AS played;
+
+-- name: GetFavouriteTarget :one
+SELECT target_user_id, COUNT(*) AS tosses FROM game_turns
+WHERE namespace = $1 AND room_id = $2 AND actor_user_id = $3 AND action = 'toss' AND target_user_id <> actor_user_id
+GROUP BY target_user_id
+ORDER BY tosses DESC, target_user_id
+LIMIT 1;
+
+-- name: GetNemesis :one
+SELECT actor_user_id, COUNT(*) AS kills FROM game_turns
+WHERE namespace = $1 AND room_id = $2 AND target_user_id = $3 AND action = 'toss' AND exploded = true AND actor_user_id <> target_user_id
+GROUP BY actor_user_id
+ORDER BY kills DESC, actor_user_id
+LIMIT 1;
+
+-- name: InsertGameTeam :exec
+INSERT INTO game_teams (
+  namespace, room_id, channel_id, potato_id, round, user_id, team
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ListGameTeams :many
+SELECT * FROM game_teams
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+ORDER BY team, user_id;
+
+-- name: ListChannelGameTeams :many
+SELECT game_teams.namespace, game_teams.channel_id, game_teams.potato_id, game_teams.user_id, game_teams.team FROM game_teams
+JOIN games ON games.namespace = game_teams.namespace AND games.channel_id = game_teams.channel_id AND games.potato_id = game_teams.potato_id AND games.round = game_teams.round
+WHERE games.namespace = $1 AND games.channel_id = $2
+ORDER BY game_teams.team, game_teams.user_id;
+
+-- name: ListOngoingGameTeams :many
+SELECT game_teams.namespace, game_teams.channel_id, game_teams.potato_id, game_teams.user_id, game_teams.team FROM game_teams
+JOIN games ON games.namespace = game_teams.namespace AND games.channel_id = game_teams.channel_id AND games.potato_id = game_teams.potato_id AND games.round = game_teams.round
+WHERE games.finished = false
+ORDER BY game_teams.team, game_teams.user_id;
+
+-- name: InsertGamePlayer :exec
+INSERT INTO game_players (
+  namespace, room_id, channel_id, potato_id, round, user_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListGamePlayers :many
+SELECT * FROM game_players
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+ORDER BY user_id;
+
+-- name: ListChannelGamePlayers :many
+SELECT game_players.namespace, game_players.channel_id, game_players.potato_id, game_players.user_id FROM game_players
+JOIN games ON games.namespace = game_players.namespace AND games.channel_id = game_players.channel_id AND games.potato_id = game_players.potato_id AND games.round = game_players.round
+WHERE games.namespace = $1 AND games.channel_id = $2
+ORDER BY game_players.user_id;
+
+-- name: ListOngoingGamePlayers :many
+SELECT game_players.namespace, game_players.channel_id, game_players.potato_id, game_players.user_id FROM game_players
+JOIN games ON games.namespace = game_players.namespace AND games.channel_id = game_players.channel_id AND games.potato_id = game_players.potato_id AND games.round = game_players.round
+WHERE games.finished = false
+ORDER BY game_players.user_id;
+
+-- name: InsertLobbyPlayer :exec
+INSERT INTO game_lobbies (
+  namespace, room_id, channel_id, user_id
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (namespace, channel_id, user_id) DO NOTHING;
+
+-- name: ListLobbyPlayers :many
+SELECT * FROM game_lobbies
+WHERE namespace = $1 AND channel_id = $2
+ORDER BY joined_at, user_id;
+
+-- name: ClearLobby :exec
+DELETE FROM game_lobbies
+WHERE namespace = $1 AND channel_id = $2;
+
+-- name: UpsertChannelActivity :exec
+INSERT INTO channel_activity (
+  namespace, room_id, channel_id, user_id, active_at
+) VALUES (
+  $1, $2, $3, $4, CURRENT_TIMESTAMP
+) ON CONFLICT (namespace, channel_id, user_id)
+  DO UPDATE SET active_at = CURRENT_TIMESTAMP;
+
+-- name: ListChannelActivity :many
+SELECT * FROM channel_activity
 WHERE namespace = $1 AND channel_id = $2
-RETURNING *;
\ No newline at end of file
+ORDER BY active_at DESC, user_id;
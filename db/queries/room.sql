@@ -22,4 +22,239 @@ INSERT INTO deaths (
   $1, $2, $3, 1
 ) ON CONFLICT (namespace, room_id, user_id)
   DO UPDATE SET count = deaths.count + 1
-  WHERE deaths.namespace = $1 AND deaths.room_id = $2 AND deaths.user_id = $3;
\ No newline at end of file
+  WHERE deaths.namespace = $1 AND deaths.room_id = $2 AND deaths.user_id = $3;
+
+-- name: UpdateRoomSettings :one
+UPDATE rooms
+SET allowed_potato_kinds = $3, explode_multiplier = $4, steal_enabled = $5, cook_cap = $6, fuse_timeout_seconds = $7, leaderboard_size = $8, potato_limit = $9
+WHERE namespace = $1 AND id = $2
+RETURNING *;
+
+-- name: GetActiveSeason :one
+SELECT * FROM seasons
+WHERE namespace = $1 AND room_id = $2 AND ended_at IS NULL
+ORDER BY number DESC
+LIMIT 1;
+
+-- name: GetSeason :one
+SELECT * FROM seasons
+WHERE namespace = $1 AND room_id = $2 AND number = $3
+LIMIT 1;
+
+-- name: InsertSeason :exec
+INSERT INTO seasons (
+  namespace, room_id, number
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: EndSeason :execrows
+UPDATE seasons
+SET ended_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND room_id = $2 AND number = $3 AND ended_at IS NULL;
+
+-- name: InsertSeasonStanding :exec
+INSERT INTO season_standings (
+  namespace, room_id, season, user_id, count
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ListSeasonStandings :many
+SELECT * FROM season_standings
+WHERE namespace = $1 AND room_id = $2 AND season = $3;
+
+-- name: ResetDeathCount :exec
+DELETE FROM deaths
+WHERE namespace = $1 AND room_id = $2;
+
+-- name: RecordUserStats :exec
+INSERT INTO user_stats (
+  namespace, room_id, season, user_id, games_played, games_survived, tosses, steals, cooks, kills, longest_hold_streak
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) ON CONFLICT (namespace, room_id, season, user_id)
+  DO UPDATE SET
+    games_played = user_stats.games_played + excluded.games_played,
+    games_survived = user_stats.games_survived + excluded.games_survived,
+    tosses = user_stats.tosses + excluded.tosses,
+    steals = user_stats.steals + excluded.steals,
+    cooks = user_stats.cooks + excluded.cooks,
+    kills = user_stats.kills + excluded.kills,
+    longest_hold_streak = CASE
+      WHEN excluded.longest_hold_streak > user_stats.longest_hold_streak THEN excluded.longest_hold_streak
+      ELSE user_stats.longest_hold_streak
+    END;
+
+-- name: ListUserStats :many
+SELECT * FROM user_stats
+WHERE namespace = $1 AND room_id = $2 AND season = $3;
+
+-- name: UnlockAchievement :execrows
+INSERT INTO achievements (
+  namespace, room_id, user_id, achievement_id
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (namespace, room_id, user_id, achievement_id) DO NOTHING;
+
+-- name: ListAchievements :many
+SELECT * FROM achievements
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+ORDER BY unlocked_at;
+
+-- name: IncrementTeamLosses :exec
+INSERT INTO team_losses (
+  namespace, room_id, team, count
+) VALUES (
+  $1, $2, $3, 1
+) ON CONFLICT (namespace, room_id, team)
+  DO UPDATE SET count = team_losses.count + 1;
+
+-- name: ListTeamLosses :many
+SELECT * FROM team_losses
+WHERE namespace = $1 AND room_id = $2;
+
+-- name: GetActiveTournament :one
+SELECT * FROM tournaments
+WHERE namespace = $1 AND room_id = $2 AND ended_at IS NULL
+ORDER BY number DESC
+LIMIT 1;
+
+-- name: GetTournament :one
+SELECT * FROM tournaments
+WHERE namespace = $1 AND room_id = $2 AND number = $3
+LIMIT 1;
+
+-- name: CountTournaments :one
+SELECT COUNT(*) AS count FROM tournaments
+WHERE namespace = $1 AND room_id = $2;
+
+-- name: InsertTournament :one
+INSERT INTO tournaments (
+  namespace, room_id, number, channel_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: EndTournament :execrows
+UPDATE tournaments
+SET winner_user_id = $4, ended_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND room_id = $2 AND number = $3 AND ended_at IS NULL;
+
+-- name: AdvanceTournamentRound :execrows
+UPDATE tournaments
+SET round = $4
+WHERE namespace = $1 AND room_id = $2 AND number = $3 AND round = sqlc.arg(expected_round) AND ended_at IS NULL;
+
+-- name: InsertTournamentPlayer :exec
+INSERT INTO tournament_players (
+  namespace, room_id, tournament, user_id
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (namespace, room_id, tournament, user_id) DO NOTHING;
+
+-- name: ListTournamentPlayers :many
+SELECT * FROM tournament_players
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3
+ORDER BY registered_at, user_id;
+
+-- name: InsertTournamentHeat :exec
+INSERT INTO tournament_heats (
+  namespace, room_id, tournament, round, heat
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: InsertTournamentHeatPlayer :exec
+INSERT INTO tournament_heat_players (
+  namespace, room_id, tournament, round, heat, user_id, seat
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ListTournamentHeats :many
+SELECT * FROM tournament_heats
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3
+ORDER BY round, heat;
+
+-- name: ListTournamentHeatPlayers :many
+SELECT * FROM tournament_heat_players
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3
+ORDER BY round, heat, seat;
+
+-- name: OpenTournamentHeat :execrows
+UPDATE tournament_heats
+SET channel_id = $6
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3 AND round = $4 AND heat = $5 AND channel_id = '';
+
+-- name: FinishTournamentHeat :execrows
+UPDATE tournament_heats
+SET loser_user_id = $6, closes_at = $7
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3 AND round = $4 AND heat = $5 AND loser_user_id = '';
+
+-- name: ListClosingTournamentHeats :many
+SELECT * FROM tournament_heats
+WHERE closes_at IS NOT NULL
+ORDER BY closes_at;
+
+-- name: CloseTournamentHeat :exec
+UPDATE tournament_heats
+SET closes_at = NULL
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3 AND round = $4 AND heat = $5;
+
+-- name: IncrementWins :exec
+INSERT INTO wins (
+  namespace, room_id, user_id, count
+) VALUES (
+  $1, $2, $3, 1
+) ON CONFLICT (namespace, room_id, user_id)
+  DO UPDATE SET count = wins.count + 1;
+
+-- name: ListWins :many
+SELECT * FROM wins
+WHERE namespace = $1 AND room_id = $2;
+
+-- name: InsertParticipant :execrows
+INSERT INTO participants (
+  namespace, room_id, user_id
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (namespace, room_id, user_id) DO NOTHING;
+
+-- name: DeleteParticipant :execrows
+DELETE FROM participants
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3;
+
+-- name: GetParticipant :one
+SELECT * FROM participants
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+LIMIT 1;
+
+-- name: ListParticipants :many
+SELECT * FROM participants
+WHERE namespace = $1 AND room_id = $2
+ORDER BY user_id;
+
+-- name: AddItem :exec
+INSERT INTO items (
+  namespace, room_id, user_id, item_id, count
+) VALUES (
+  $1, $2, $3, $4, 1
+) ON CONFLICT (namespace, room_id, user_id, item_id)
+  DO UPDATE SET count = items.count + 1;
+
+-- name: ArmItem :execrows
+UPDATE items
+SET count = count - 1, armed = true
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3 AND item_id = $4 AND count > 0 AND armed = false;
+
+-- name: DisarmItem :execrows
+UPDATE items
+SET armed = false
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3 AND item_id = $4 AND armed = true;
+
+-- name: ListItems :many
+SELECT * FROM items
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+ORDER BY item_id;
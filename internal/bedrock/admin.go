@@ -17,6 +17,7 @@ type Admin struct {
 	logger log.Logger
 	server *http.Server
 	health healthcheck.Handler
+	schema SchemaVersioner
 }
 
 func NewAdmin(logger log.Logger, port int) (*Admin, error) {
@@ -40,6 +41,7 @@ func (a *Admin) router() http.Handler {
 	admin.Handle("/metrics", promhttp.Handler())
 	admin.HandleFunc("/live", a.health.LiveEndpoint)
 	admin.HandleFunc("/ready", a.health.ReadyEndpoint)
+	admin.HandleFunc("/schema", a.schemaVersionEndpoint)
 
 	return router
 }
@@ -0,0 +1,42 @@
+package bedrock
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+)
+
+type SchemaVersioner interface {
+	Version(ctx context.Context) (uint64, bool, error)
+}
+
+type schemaVersionResponse struct {
+	Version uint64 `json:"version"`
+	Dirty   bool   `json:"dirty"`
+}
+
+func (a *Admin) RegisterSchemaVersioner(versioner SchemaVersioner) {
+	a.schema = versioner
+}
+
+func (a *Admin) schemaVersionEndpoint(w http.ResponseWriter, r *http.Request) {
+	if a.schema == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	version, dirty, err := a.schema.Version(r.Context())
+	if err != nil {
+		level.Error(a.logger).Log("event", "schema.version.failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&schemaVersionResponse{
+		Version: version,
+		Dirty:   dirty,
+	})
+}
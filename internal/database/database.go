@@ -0,0 +1,80 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+// Open opens a connection to the database at the given URL, choosing the driver
+// from the URL scheme. SQLite databases are given as sqlite://<path>.
+func Open(databaseURL string) (*sql.DB, Dialect, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid database URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		db, err := sql.Open("postgres", databaseURL)
+		if err != nil {
+			return nil, "", err
+		}
+		return db, DialectPostgres, nil
+
+	case "sqlite":
+		path := strings.TrimPrefix(strings.TrimPrefix(databaseURL, "sqlite:"), "//")
+		if i := strings.Index(path, "?"); i >= 0 {
+			path = path[:i]
+		}
+		if path == "" {
+			return nil, "", errors.New("missing sqlite database path")
+		}
+
+		query := u.Query()
+		query.Add("_pragma", "foreign_keys(1)")
+		query.Add("_pragma", "busy_timeout(5000)")
+
+		db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?%s", path, query.Encode()))
+		if err != nil {
+			return nil, "", err
+		}
+
+		// SQLite only allows a single writer at a time.
+		db.SetMaxOpenConns(1)
+
+		return db, DialectSQLite, nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported database URL scheme '%s'", u.Scheme)
+	}
+}
+
+// IsUniqueViolation reports whether the error was caused by a row violating a
+// unique or primary key constraint, regardless of the database driver.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	var sqliteErr *sqlite.Error
+
+	switch {
+	case errors.As(err, &pqErr):
+		return pqErr.Code.Name() == "unique_violation"
+	case errors.As(err, &sqliteErr):
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	default:
+		return false
+	}
+}
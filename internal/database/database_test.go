@@ -0,0 +1,91 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		url     string
+		dialect Dialect
+		invalid bool
+	}{
+		{name: "sqlite", url: "sqlite://" + filepath.Join(dir, "a.db"), dialect: DialectSQLite},
+		{name: "sqlite with options", url: "sqlite://" + filepath.Join(dir, "b.db") + "?_pragma=journal_mode(WAL)", dialect: DialectSQLite},
+		{name: "sqlite without path", url: "sqlite://", invalid: true},
+		{name: "postgres", url: "postgres://hotpotato@localhost:5432/hotpotato", dialect: DialectPostgres},
+		{name: "postgresql", url: "postgresql://hotpotato@localhost:5432/hotpotato", dialect: DialectPostgres},
+		{name: "unsupported scheme", url: "mysql://localhost/hotpotato", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dialect, err := Open(tt.url)
+			if tt.invalid {
+				if err == nil {
+					db.Close()
+					t.Fatalf("opening %q succeeded, want error", tt.url)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to open database: %v", err)
+			}
+			defer db.Close()
+
+			if dialect != tt.dialect {
+				t.Errorf("got dialect %s, want %s", dialect, tt.dialect)
+			}
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	db := openSQLite(t)
+
+	_, err := db.Exec(`
+		CREATE TABLE parents (id INTEGER PRIMARY KEY, name TEXT NOT NULL UNIQUE);
+		CREATE TABLE children (id INTEGER PRIMARY KEY, parent_id INTEGER NOT NULL REFERENCES parents (id));
+		INSERT INTO parents (id, name) VALUES (1, 'potato');
+	`)
+	if err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	exec := func(query string) error {
+		_, err := db.Exec(query)
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		unique bool
+	}{
+		{name: "sqlite primary key", err: exec("INSERT INTO parents (id, name) VALUES (1, 'other')"), unique: true},
+		{name: "sqlite unique", err: exec("INSERT INTO parents (id, name) VALUES (2, 'potato')"), unique: true},
+		{name: "sqlite wrapped", err: fmt.Errorf("error inserting: %w", exec("INSERT INTO parents (id, name) VALUES (1, 'potato')")), unique: true},
+		{name: "sqlite foreign key", err: exec("INSERT INTO children (id, parent_id) VALUES (1, 2)")},
+		{name: "sqlite not null", err: exec("INSERT INTO parents (id) VALUES (3)")},
+		{name: "postgres unique", err: &pq.Error{Code: "23505"}, unique: true},
+		{name: "postgres foreign key", err: &pq.Error{Code: "23503"}},
+		{name: "other", err: errors.New("something went wrong")},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUniqueViolation(tt.err); got != tt.unique {
+				t.Errorf("IsUniqueViolation(%v) = %t, want %t", tt.err, got, tt.unique)
+			}
+		})
+	}
+}
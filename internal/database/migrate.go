@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// migrationsLockID is the key of the Postgres advisory lock held while
+// applying migrations, so that only one instance migrates at a time.
+const migrationsLockID = 3170254977
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.up\.sql$`)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	SQL     string
+}
+
+// Migrator applies migrations to a database, tracking the schema version in
+// the same schema_migrations table used by golang-migrate. Instances migrating
+// a Postgres database at the same time take turns, while a SQLite database must
+// only be used by a single instance.
+type Migrator struct {
+	logger     log.Logger
+	db         *sql.DB
+	dialect    Dialect
+	migrations fs.FS
+}
+
+func NewMigrator(logger log.Logger, db *sql.DB, dialect Dialect, migrations fs.FS) *Migrator {
+	return &Migrator{
+		logger:     logger,
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}
+}
+
+// Up applies all pending migrations and returns the resulting schema version.
+func (m *Migrator) Up(ctx context.Context) (uint64, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return 0, fmt.Errorf("error loading migrations: %w", err)
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return 0, fmt.Errorf("error acquiring migrations lock: %w", err)
+	}
+	defer unlock()
+
+	_, err = conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	if err != nil {
+		return 0, fmt.Errorf("error creating migrations table: %w", err)
+	}
+
+	version, dirty, err := m.version(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, fmt.Errorf("database is dirty at version %d", version)
+	}
+
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+
+		if err := m.apply(ctx, conn, migration); err != nil {
+			return version, fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		version = migration.Version
+
+		level.Info(m.logger).Log("event", "migration.applied", "version", migration.Version, "name", migration.Name)
+	}
+
+	return version, nil
+}
+
+// Version returns the current schema version and whether the last migration
+// failed partway through. The version of a database that has never been
+// migrated is 0.
+func (m *Migrator) Version(ctx context.Context) (uint64, bool, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	query := "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'"
+	if m.dialect == DialectPostgres {
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations'"
+	}
+
+	var tables int
+	if err := conn.QueryRowContext(ctx, query).Scan(&tables); err != nil {
+		return 0, false, fmt.Errorf("error finding migrations table: %w", err)
+	}
+	if tables == 0 {
+		return 0, false, nil
+	}
+
+	return m.version(ctx, conn)
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (uint64, bool, error) {
+	var version uint64
+	var dirty bool
+
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	switch {
+	case err == nil:
+		return version, dirty, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	default:
+		return 0, false, fmt.Errorf("error getting schema version: %w", err)
+	}
+}
+
+// apply applies a single migration. The version is marked dirty before the
+// migration runs and only cleared once it has been committed, so that one that
+// failed partway through is left for an operator to resolve.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	if err := m.markDirty(ctx, conn, migration.Version); err != nil {
+		return fmt.Errorf("error marking version dirty: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return err
+	}
+
+	if err := setVersion(ctx, tx, migration.Version, false); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) markDirty(ctx context.Context, conn *sql.Conn, version uint64) error {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setVersion(ctx, tx, version, true); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setVersion(ctx context.Context, tx *sql.Tx, version uint64, dirty bool) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", version, dirty)
+	return err
+}
+
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	switch m.dialect {
+	case DialectPostgres:
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+			return nil, err
+		}
+
+		return func() {
+			conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID)
+		}, nil
+
+	default:
+		// SQLite has no advisory locks, and a database file is only supported
+		// for use by a single instance. Within that instance, the pool is
+		// limited to the one connection held here while migrating.
+		return func() {}, nil
+	}
+}
+
+func (m *Migrator) load() ([]*Migration, error) {
+	entries, err := fs.ReadDir(m.migrations, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*Migration
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version '%s': %w", match[1], err)
+		}
+
+		data, err := fs.ReadFile(m.migrations, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, &Migration{
+			Version: version,
+			Name:    match[2],
+			SQL:     string(data),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
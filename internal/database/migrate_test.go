@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-kit/kit/log"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	db, dialect, err := Open("sqlite://" + filepath.Join(t.TempDir(), "hotpotato.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if dialect != DialectSQLite {
+		t.Fatalf("opened %s database, want sqlite", dialect)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func migrations(files map[string]string) fstest.MapFS {
+	fsys := make(fstest.MapFS, len(files))
+	for name, sql := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(sql)}
+	}
+	return fsys
+}
+
+func TestMigratorUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		version uint64
+		tables  []string
+	}{
+		{
+			name:  "none",
+			files: map[string]string{},
+		},
+		{
+			name: "in version order",
+			files: map[string]string{
+				"10_add_potatoes_heat.up.sql":      "ALTER TABLE potatoes ADD COLUMN heat INTEGER",
+				"2_create_potatoes_table.up.sql":   "CREATE TABLE potatoes (id INTEGER PRIMARY KEY)",
+				"2_create_potatoes_table.down.sql": "DROP TABLE potatoes",
+				"README.md":                        "not a migration",
+			},
+			version: 10,
+			tables:  []string{"potatoes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openSQLite(t)
+			ctx := context.Background()
+
+			migrator := NewMigrator(log.NewNopLogger(), db, DialectSQLite, migrations(tt.files))
+			for i := 0; i < 2; i++ {
+				version, err := migrator.Up(ctx)
+				if err != nil {
+					t.Fatalf("failed to apply migrations: %v", err)
+				}
+				if version != tt.version {
+					t.Fatalf("migrated to version %d, want %d", version, tt.version)
+				}
+			}
+
+			version, dirty, err := migrator.Version(ctx)
+			if err != nil {
+				t.Fatalf("failed to get version: %v", err)
+			}
+			if version != tt.version || dirty {
+				t.Errorf("got version %d dirty %t, want version %d clean", version, dirty, tt.version)
+			}
+
+			for _, table := range tt.tables {
+				if _, err := db.Exec("SELECT * FROM " + table); err != nil {
+					t.Errorf("table %s not created: %v", table, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMigratorDirty(t *testing.T) {
+	files := map[string]string{
+		"1_create_potatoes_table.up.sql": "CREATE TABLE potatoes (id INTEGER PRIMARY KEY)",
+		"2_add_potatoes_heat.up.sql":     "ALTER TABLE potatos ADD COLUMN heat INTEGER",
+		"3_add_potatoes_owner.up.sql":    "ALTER TABLE potatoes ADD COLUMN owner TEXT",
+	}
+
+	db := openSQLite(t)
+	ctx := context.Background()
+	migrator := NewMigrator(log.NewNopLogger(), db, DialectSQLite, migrations(files))
+
+	version, err := migrator.Up(ctx)
+	if err == nil {
+		t.Fatal("applying a broken migration succeeded")
+	}
+	if version != 1 {
+		t.Errorf("migrated to version %d before failing, want 1", version)
+	}
+
+	version, dirty, err := migrator.Version(ctx)
+	if err != nil {
+		t.Fatalf("failed to get version: %v", err)
+	}
+	if version != 2 || !dirty {
+		t.Fatalf("got version %d dirty %t, want version 2 dirty", version, dirty)
+	}
+
+	files["2_add_potatoes_heat.up.sql"] = "ALTER TABLE potatoes ADD COLUMN heat INTEGER"
+	migrator = NewMigrator(log.NewNopLogger(), db, DialectSQLite, migrations(files))
+	if _, err := migrator.Up(ctx); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Fatalf("migrating a dirty database returned %v, want dirty error", err)
+	}
+
+	if _, err := db.Exec("SELECT owner FROM potatoes"); err == nil {
+		t.Error("migration applied over a dirty database")
+	}
+}
+
+func TestMigratorVersionWithoutMigrating(t *testing.T) {
+	db := openSQLite(t)
+
+	// The version is reported for databases left unmigrated with --skip-migrations.
+	migrator := NewMigrator(log.NewNopLogger(), db, DialectSQLite, migrations(map[string]string{
+		"1_create_potatoes_table.up.sql": "CREATE TABLE potatoes (id INTEGER PRIMARY KEY)",
+	}))
+
+	version, dirty, err := migrator.Version(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get version: %v", err)
+	}
+	if version != 0 || dirty {
+		t.Errorf("got version %d dirty %t, want version 0 clean", version, dirty)
+	}
+}
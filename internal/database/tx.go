@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+type txKey struct{}
+
+// Transactor runs a function as a single unit of work, so that the writes made
+// by the repositories it calls are either all committed or all rolled back.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// SQLTransactor runs functions in a database transaction that is carried on the
+// context handed to them. Repositories given that context join the transaction
+// instead of starting their own.
+type SQLTransactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) *SQLTransactor {
+	return &SQLTransactor{db: db}
+}
+
+func (t *SQLTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := BeginTx(ctx, t.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx.Tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Snapshotter is storage without transactions of its own, which can be
+// restored to an earlier state instead.
+type Snapshotter interface {
+	Snapshot() (restore func())
+}
+
+// MutexTransactor runs functions one at a time against storage without
+// transactions of its own, such as the in-memory repositories. Functions run
+// from within another one join it, and the storage is restored to its
+// snapshot from before a function that fails.
+type MutexTransactor struct {
+	mu     sync.Mutex
+	stores []Snapshotter
+}
+
+type mutexKey struct{}
+
+func NewMutexTransactor(stores ...Snapshotter) *MutexTransactor {
+	return &MutexTransactor{stores: stores}
+}
+
+func (t *MutexTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(mutexKey{}) == t {
+		return fn(ctx)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	restores := make([]func(), len(t.stores))
+	for i, store := range t.stores {
+		restores[i] = store.Snapshot()
+	}
+
+	if err := fn(context.WithValue(ctx, mutexKey{}, t)); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Tx is a database transaction that may have joined one already running on the
+// context, in which case committing or rolling it back is left to whoever
+// started the transaction.
+type Tx struct {
+	*sql.Tx
+	joined bool
+}
+
+// BeginTx joins the transaction running on the context, or starts a new one.
+func BeginTx(ctx context.Context, db *sql.DB) (*Tx, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return &Tx{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx}, nil
+}
+
+func (tx *Tx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+func (tx *Tx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
+
+// TxFromContext returns the transaction running on the context, if any.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
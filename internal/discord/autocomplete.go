@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+)
+
+// HotPotatoPotatoAutocompleter suggests the potatoes in play in the channel for
+// the potato option of the subcommands: the ones held by the user for tosses
+// and cooks, the ones held by others for steals, and all of them otherwise.
+func (b *Bot) HotPotatoPotatoAutocompleter() func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		command := i.ApplicationCommandData()
+		if len(command.Options) == 0 {
+			return
+		}
+
+		subcommand := command.Options[0]
+		logger := log.WithSuffix(b.logger, "subcommand", subcommand.Name, "guild", i.GuildID, "channel", i.ChannelID, "interaction", i.Interaction.ID)
+
+		var typed string
+		for _, option := range subcommand.Options {
+			if option.Focused && option.Name == "potato" {
+				typed = fmt.Sprint(option.Value)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		choices, err := b.potatoChoices(ctx, s, i, subcommand.Name, typed)
+		if err != nil {
+			level.Error(logger).Log("event", "autocomplete.handle.failure", "err", err)
+		}
+
+		err = b.respond(s, i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{
+				Choices: choices,
+			},
+		})
+		if err != nil {
+			level.Error(logger).Log("event", "autocomplete.respond.failure", "err", err)
+			return
+		}
+
+		level.Info(logger).Log("event", "autocomplete.handle.success", "choices", len(choices))
+	}
+}
+
+func (b *Bot) potatoChoices(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, subcommand, typed string) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	rsp, err := b.hotpotato.GetHolder(ctx, &hotpotato.GetHolderRequest{
+		Namespace: namespace,
+		RoomID:    i.GuildID,
+		ChannelID: i.ChannelID,
+	})
+	if err != nil {
+		if errors.Is(err, hotpotato.ErrNoOngoingGame) {
+			return []*discordgo.ApplicationCommandOptionChoice{}, nil
+		}
+		return []*discordgo.ApplicationCommandOptionChoice{}, fmt.Errorf("failed to handle where request: %w", err)
+	}
+
+	actorUserID := i.Interaction.Member.User.ID
+
+	var potatoes []*hotpotato.LivePotato
+	for _, live := range rsp.Potatoes {
+		held := live.HolderUserID == actorUserID
+		switch {
+		case (subcommand == "toss" || subcommand == "cook") && !held:
+			continue
+		case subcommand == "steal" && held:
+			continue
+		case !strings.HasPrefix(strconv.Itoa(live.ID), typed):
+			continue
+		}
+		potatoes = append(potatoes, live)
+	}
+
+	userIDs := make([]string, len(potatoes))
+	for i, live := range potatoes {
+		userIDs[i] = live.HolderUserID
+	}
+	names := b.displayNames(s, i.GuildID, userIDs...)
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(potatoes))
+	for i, live := range potatoes {
+		holder := names[live.HolderUserID]
+		if holder == "" {
+			holder = live.HolderUserID
+		}
+
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("#%d %s (held by %s)", live.ID, live.Potato, holder),
+			Value: live.ID,
+		}
+	}
+
+	return choices, nil
+}
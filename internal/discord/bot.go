@@ -2,10 +2,14 @@ package discord
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/go-kit/kit/log"
@@ -31,17 +35,60 @@ func init() {
 	prometheus.MustRegister(commandHandlerPanics)
 }
 
+type BotConfig struct {
+	Port         int
+	DiscordToken string
+
+	// PublicKey is the hex-encoded public key of the Discord application, used
+	// to verify interactions received over HTTP. The /interactions endpoint is
+	// only served when it is set.
+	PublicKey string
+
+	// Gateway controls whether the bot connects to the Discord gateway to
+	// receive interactions, rather than relying on the /interactions endpoint.
+	Gateway bool
+
+	// GuildID registers commands in a single guild rather than globally, which
+	// takes effect immediately and is useful for development.
+	GuildID string
+
+	// CleanupCommands removes the registered commands when the bot stops.
+	CleanupCommands bool
+}
+
 type Bot struct {
-	logger  log.Logger
-	server  *http.Server
-	discord *discordgo.Session
-	command *discordgo.ApplicationCommand
+	logger    log.Logger
+	server    *http.Server
+	discord   *discordgo.Session
+	commands  []*discordgo.ApplicationCommand
+	gateway   bool
+	publicKey ed25519.PublicKey
+	guildID   string
+	cleanup   bool
+
+	interactions func(*discordgo.Session, *discordgo.InteractionCreate)
+	pending      sync.Map
+	deferTimeout time.Duration
 
 	hotpotato hotpotato.Service
+	random    hotpotato.Randomizer
 }
 
-func NewBot(logger log.Logger, hotpotato hotpotato.Service, discordToken string, port int) (*Bot, error) {
-	session, err := discordgo.New(fmt.Sprintf("Bot %s", discordToken))
+func NewBot(logger log.Logger, hotpotato hotpotato.Service, random hotpotato.Randomizer, cfg *BotConfig) (*Bot, error) {
+	var publicKey ed25519.PublicKey
+	if cfg.PublicKey != "" {
+		key, err := hex.DecodeString(cfg.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid discord public key")
+		}
+		publicKey = key
+	}
+
+	if !cfg.Gateway && publicKey == nil {
+		return nil, errors.New("discord public key is required when the gateway is disabled")
+	}
+
+	session, err := discordgo.New(fmt.Sprintf("Bot %s", cfg.DiscordToken))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discord session: %w", err)
 	}
@@ -50,18 +97,33 @@ func NewBot(logger log.Logger, hotpotato hotpotato.Service, discordToken string,
 		level.Info(logger).Log("event", "discord.ready", "session", r.SessionID, "guilds", len(r.Guilds))
 	})
 
-	if err := session.Open(); err != nil {
-		return nil, fmt.Errorf("failed to connect to discord: %w", err)
+	if cfg.Gateway {
+		if err := session.Open(); err != nil {
+			return nil, fmt.Errorf("failed to connect to discord: %w", err)
+		}
+	} else {
+		user, err := session.User("@me")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get discord user: %w", err)
+		}
+		session.State.User = user
 	}
 
 	bot := &Bot{
 		logger:    logger,
 		discord:   session,
+		gateway:   cfg.Gateway,
+		publicKey: publicKey,
+		guildID:   cfg.GuildID,
+		cleanup:   cfg.CleanupCommands,
 		hotpotato: hotpotato,
+		random:    random,
+
+		deferTimeout: interactionDeferTimeout,
 	}
 
 	bot.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
+		Addr:    fmt.Sprintf(":%d", cfg.Port),
 		Handler: bot.router(),
 	}
 
@@ -75,6 +137,10 @@ func (b *Bot) router() http.Handler {
 		rw.WriteHeader(http.StatusOK)
 	})
 
+	if b.publicKey != nil {
+		router.HandleFunc("/interactions", b.interactionsEndpoint).Methods(http.MethodPost)
+	}
+
 	return router
 }
 
@@ -83,6 +149,12 @@ func (b *Bot) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start discord handlers: %w", err)
 	}
 
+	if err := b.sweepHeats(ctx, b.discord); err != nil {
+		return fmt.Errorf("failed to sweep heat channels: %w", err)
+	}
+
+	go b.announceDetonations(ctx)
+
 	level.Info(b.logger).Log("event", "server.started", "name", "bot", "addr", b.server.Addr)
 	defer level.Info(b.logger).Log("event", "server.stopped")
 
@@ -95,14 +167,16 @@ func (b *Bot) Start(ctx context.Context) error {
 
 func (b *Bot) handleDiscord() error {
 	rootCmd, rootHandler := b.HotPotatoRootCommand()
+	componentRouter := b.HotPotatoComponentRouter()
+	potatoAutocompleter := b.HotPotatoPotatoAutocompleter()
 
-	cmd, err := b.discord.ApplicationCommandCreate(b.discord.State.User.ID, "", rootCmd)
+	commands, err := b.syncCommands([]*discordgo.ApplicationCommand{rootCmd})
 	if err != nil {
 		return err
 	}
-	b.command = cmd
+	b.commands = commands
 
-	b.discord.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b.interactions = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		defer func() {
 			if r := recover(); r != nil {
 				commandHandlerPanics.Inc()
@@ -110,13 +184,49 @@ func (b *Bot) handleDiscord() error {
 			}
 		}()
 
-		rootHandler(s, i)
-	})
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			rootHandler(s, i)
+		case discordgo.InteractionMessageComponent:
+			componentRouter(s, i)
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			potatoAutocompleter(s, i)
+		}
+	}
+	b.discord.AddHandler(b.interactions)
 
 	return nil
 }
 
+func (b *Bot) announceDetonations(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-b.hotpotato.Detonations():
+			if d.Namespace != namespace {
+				continue
+			}
+
+			logger := log.WithSuffix(b.logger, "guild", d.RoomID, "channel", d.ChannelID)
+			if err := b.send(b.discord, d.ChannelID, FuseDetonatedReply(b.random, d)); err != nil {
+				level.Error(logger).Log("event", "detonation.announce.failure", "err", err)
+			} else {
+				level.Info(logger).Log("event", "detonation.announce.success")
+			}
+
+			b.announceTournament(ctx, b.discord, d.RoomID, d.Tournament)
+		}
+	}
+}
+
 func (b *Bot) Stop(ctx context.Context) error {
+	if b.cleanup {
+		if err := b.removeCommands(b.commands); err != nil {
+			level.Error(b.logger).Log("event", "commands.remove.failure", "err", err)
+		}
+	}
+
 	if err := b.discord.Close(); err != nil {
 		return fmt.Errorf("failed to diconnect from discord: %w", err)
 	}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/log/level"
 
 	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
 )
 
 const namespace = "discord"
 
+// ApplicationCommandOptionNumber is the type of options taking any double
+// between -2^53 and 2^53, which is not yet defined by discordgo.
+const ApplicationCommandOptionNumber discordgo.ApplicationCommandOptionType = 10
+
 type SubCommandEntry func() (*discordgo.ApplicationCommandOption, SubCommandHandler)
 type SubCommandHandler = func(context.Context, *discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption) error
 
@@ -29,7 +36,18 @@ func (b *Bot) HotPotatoRootCommand() (*discordgo.ApplicationCommand, func(s *dis
 		b.HotPotatoStealSubCommand,
 		b.HotPotatoCookSubCommand,
 		b.HotPotatoWhereSubCommand,
+		b.HotPotatoHistorySubCommand,
 		b.HotPotatoLeaderboardSubCommand,
+		b.HotPotatoStatsSubCommand,
+		b.HotPotatoAchievementsSubCommand,
+		b.HotPotatoOptInSubCommand,
+		b.HotPotatoOptOutSubCommand,
+		b.HotPotatoUseSubCommand,
+		b.HotPotatoTeamsSubCommandGroup,
+		b.HotPotatoJoinSubCommand,
+		b.HotPotatoEliminationSubCommand,
+		b.HotPotatoTournamentSubCommandGroup,
+		b.HotPotatoConfigSubCommandGroup,
 	}
 
 	handlers := make(map[string]SubCommandHandler)
@@ -40,10 +58,6 @@ func (b *Bot) HotPotatoRootCommand() (*discordgo.ApplicationCommand, func(s *dis
 	}
 
 	return cmd, func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type != discordgo.InteractionApplicationCommand {
-			return
-		}
-
 		command := i.ApplicationCommandData()
 		if command.Name != cmd.Name {
 			return
@@ -83,41 +97,43 @@ func (b *Bot) HotPotatoTossSubCommand() (*discordgo.ApplicationCommandOption, Su
 				Type:        discordgo.ApplicationCommandOptionUser,
 				Name:        "user",
 				Description: "User to toss hot potato to",
-				Required:    true,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "random",
+				Description: "Toss hot potato to someone picked at random instead",
+			},
+			potatoCommandOption("Potato to toss, or the first one you're holding if not given"),
 		},
 	}
 
 	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
-		if data.Options[0].Type != discordgo.ApplicationCommandOptionUser || data.Options[0].Name != opt.Options[0].Name {
-			return nil
+		actorUser := i.Interaction.Member.User
+
+		var targetUser *discordgo.User
+		var random bool
+		for _, option := range data.Options {
+			switch option.Name {
+			case "user":
+				targetUser = option.UserValue(s)
+			case "random":
+				random = option.BoolValue()
+			}
 		}
 
-		actorUser := i.Interaction.Member.User
-		targetUser := data.Options[0].UserValue(s)
+		if (targetUser == nil) == !random {
+			return b.reply(s, i, TossTargetRequiredReply())
+		}
 
-		if targetUser.Bot {
-			return b.reply(s, i, TossInvalidTargetReply(targetUser.ID))
+		if random {
+			return b.toss(ctx, s, i, actorUser.ID, "", potatoOptionValue(data.Options))
 		}
 
-		rsp, err := b.hotpotato.Toss(ctx, &hotpotato.TossRequest{
-			Namespace:    namespace,
-			RoomID:       i.GuildID,
-			ChannelID:    i.ChannelID,
-			ActorUserID:  actorUser.ID,
-			TargetUserID: targetUser.ID,
-		})
-		if err != nil {
-			var e *hotpotato.NotHolderError
-			switch {
-			case errors.As(err, &e):
-				return b.reply(s, i, TossNotHolderReply(e.HolderUserID))
-			default:
-				return fmt.Errorf("failed to handle toss request: %w", err)
-			}
+		if targetUser.Bot {
+			return b.reply(s, i, TossInvalidTargetReply(targetUser.ID))
 		}
 
-		return b.reply(s, i, TossSuccessReply(actorUser.ID, targetUser.ID, rsp))
+		return b.toss(ctx, s, i, actorUser.ID, targetUser.ID, potatoOptionValue(data.Options))
 	}
 }
 
@@ -133,17 +149,22 @@ func (b *Bot) HotPotatoStealSubCommand() (*discordgo.ApplicationCommandOption, S
 				Description: "User to steal hot potato from",
 				Required:    true,
 			},
+			potatoCommandOption("Potato to steal, or the first one they're holding if not given"),
 		},
 	}
 
 	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
-		if data.Options[0].Type != opt.Options[0].Type || data.Options[0].Name != opt.Options[0].Name {
+		var targetUser *discordgo.User
+		for _, option := range data.Options {
+			if option.Name == "user" {
+				targetUser = option.UserValue(s)
+			}
+		}
+		if targetUser == nil {
 			return nil
 		}
 
 		actorUser := i.Interaction.Member.User
-		targetUser := data.Options[0].UserValue(s)
-
 		if targetUser.Bot {
 			return b.reply(s, i, StealInvalidTargetReply(targetUser.ID))
 		}
@@ -154,12 +175,29 @@ func (b *Bot) HotPotatoStealSubCommand() (*discordgo.ApplicationCommandOption, S
 			ChannelID:    i.ChannelID,
 			ActorUserID:  actorUser.ID,
 			TargetUserID: targetUser.ID,
+			PotatoID:     potatoOptionValue(data.Options),
 		})
 		if err != nil {
 			var e *hotpotato.NotHolderError
+			var c *hotpotato.CooldownError
+			var t *hotpotato.NotOnTeamError
+			var h *hotpotato.NotInHeatError
+			var p *hotpotato.NotPlayingError
 			switch {
+			case errors.As(err, &c):
+				return b.reply(s, i, CooldownReply(c.RetryAfter))
 			case errors.Is(err, hotpotato.ErrNoOngoingGame):
 				return b.reply(s, i, NoOngoingGameReply())
+			case errors.Is(err, hotpotato.ErrPotatoNotFound):
+				return b.reply(s, i, PotatoNotFoundReply())
+			case errors.As(err, &t):
+				return b.reply(s, i, NotOnTeamReply(t.UserID))
+			case errors.As(err, &h):
+				return b.reply(s, i, NotInHeatReply(h.UserID))
+			case errors.As(err, &p):
+				return b.reply(s, i, NotPlayingReply(p.UserID))
+			case errors.Is(err, hotpotato.ErrStealDisabled):
+				return b.reply(s, i, StealDisabledReply())
 			case errors.Is(err, hotpotato.ErrSelfStealUnallowed):
 				return b.reply(s, i, StealInvalidTargetReply(targetUser.ID))
 			case errors.As(err, &e):
@@ -169,7 +207,12 @@ func (b *Bot) HotPotatoStealSubCommand() (*discordgo.ApplicationCommandOption, S
 			}
 		}
 
-		return b.reply(s, i, StealSuccessReply(actorUser.ID, targetUser.ID, rsp))
+		if err := b.reply(s, i, StealSuccessReply(b.random, actorUser.ID, targetUser.ID, rsp)); err != nil {
+			return err
+		}
+
+		b.announceTournament(ctx, s, i.GuildID, rsp.Tournament)
+		return nil
 	}
 }
 
@@ -178,56 +221,70 @@ func (b *Bot) HotPotatoCookSubCommand() (*discordgo.ApplicationCommandOption, Su
 		Type:        discordgo.ApplicationCommandOptionSubCommand,
 		Name:        "cook",
 		Description: "Cook a hot potato to make it hotter!",
+		Options: []*discordgo.ApplicationCommandOption{
+			potatoCommandOption("Potato to cook, or the first one you're holding if not given"),
+		},
 	}
 
 	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
 		actorUser := i.Interaction.Member.User
+		return b.cook(ctx, s, i, actorUser.ID, potatoOptionValue(data.Options))
+	}
+}
 
-		rsp, err := b.hotpotato.Cook(ctx, &hotpotato.CookRequest{
-			Namespace:   namespace,
-			RoomID:      i.GuildID,
-			ChannelID:   i.ChannelID,
-			ActorUserID: actorUser.ID,
+func (b *Bot) HotPotatoWhereSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "where",
+		Description: "Check who currently holds the hot potatoes!",
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		rsp, err := b.hotpotato.GetHolder(ctx, &hotpotato.GetHolderRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			ChannelID: i.ChannelID,
 		})
 		if err != nil {
-			var e *hotpotato.NotHolderError
 			switch {
 			case errors.Is(err, hotpotato.ErrNoOngoingGame):
 				return b.reply(s, i, NoOngoingGameReply())
-			case errors.As(err, &e):
-				return b.reply(s, i, CookNotHolderReply(e.HolderUserID))
 			default:
-				return fmt.Errorf("failed to handle cook request: %w", err)
+				return fmt.Errorf("failed to handle where request: %w", err)
 			}
 		}
 
-		return b.reply(s, i, CookSuccessReply(actorUser.ID, rsp))
+		return b.reply(s, i, WhereSuccessReply(rsp))
 	}
 }
 
-func (b *Bot) HotPotatoWhereSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+func (b *Bot) HotPotatoHistorySubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
 	opt := &discordgo.ApplicationCommandOption{
 		Type:        discordgo.ApplicationCommandOptionSubCommand,
-		Name:        "where",
-		Description: "Check who currently holds the hot potato!",
+		Name:        "history",
+		Description: "See whose hands the hot potato has passed through!",
+		Options: []*discordgo.ApplicationCommandOption{
+			potatoCommandOption("Potato to see the history of, or the last one played if not given"),
+		},
 	}
 
 	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
-		rsp, err := b.hotpotato.GetHolder(ctx, &hotpotato.GetHolderRequest{
+		rsp, err := b.hotpotato.GetHistory(ctx, &hotpotato.GetHistoryRequest{
 			Namespace: namespace,
 			RoomID:    i.GuildID,
 			ChannelID: i.ChannelID,
+			PotatoID:  potatoOptionValue(data.Options),
 		})
 		if err != nil {
 			switch {
-			case errors.Is(err, hotpotato.ErrNoOngoingGame):
-				return b.reply(s, i, NoOngoingGameReply())
+			case errors.Is(err, hotpotato.ErrNoGameHistory):
+				return b.reply(s, i, NoGameHistoryReply())
 			default:
-				return fmt.Errorf("failed to handle where request: %w", err)
+				return fmt.Errorf("failed to handle history request: %w", err)
 			}
 		}
 
-		return b.reply(s, i, WhereSuccessReply(rsp))
+		return b.reply(s, i, HistorySuccessReply(rsp))
 	}
 }
 
@@ -236,18 +293,816 @@ func (b *Bot) HotPotatoLeaderboardSubCommand() (*discordgo.ApplicationCommandOpt
 		Type:        discordgo.ApplicationCommandOptionSubCommand,
 		Name:        "leaderboard",
 		Description: "View the leaderboard for the most number of deaths by hot potato!",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "season",
+				Description: "Season to view the final standings for, or the current season if not given",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "metric",
+				Description: "Stat to rank users by, or deaths if not given",
+				Choices:     metricChoices(),
+			},
+		},
 	}
 
 	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
-		rsp, err := b.hotpotato.GetLeaderboard(ctx, &hotpotato.GetLeaderboardRequest{
+		var season int
+		var metric hotpotato.Metric
+		for _, option := range data.Options {
+			switch option.Name {
+			case "season":
+				season = int(option.IntValue())
+			case "metric":
+				metric = hotpotato.Metric(option.StringValue())
+			}
+		}
+
+		return b.leaderboard(ctx, s, i, season, metric, 0, false)
+	}
+}
+
+func (b *Bot) HotPotatoStatsSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "stats",
+		Description: "View someone's hot potato record!",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "User to view the record of, or yourself if not given",
+			},
+		},
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		user := i.Interaction.Member.User
+		for _, option := range data.Options {
+			if option.Name == "user" {
+				user = option.UserValue(s)
+			}
+		}
+
+		rsp, err := b.hotpotato.GetProfile(ctx, &hotpotato.GetProfileRequest{
 			Namespace: namespace,
 			RoomID:    i.GuildID,
-			Top:       10,
+			UserID:    user.ID,
 		})
 		if err != nil {
+			return fmt.Errorf("failed to handle profile request: %w", err)
+		}
+
+		names := b.displayNames(s, i.GuildID, user.ID, rsp.FavouriteTargetUserID, rsp.NemesisUserID)
+
+		return b.reply(s, i, ProfileSuccessReply(user.ID, rsp, names))
+	}
+}
+
+func (b *Bot) HotPotatoAchievementsSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "achievements",
+		Description: "View someone's hot potato achievements!",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "User to view the achievements of, or yourself if not given",
+			},
+		},
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		user := i.Interaction.Member.User
+		for _, option := range data.Options {
+			if option.Name == "user" {
+				user = option.UserValue(s)
+			}
+		}
+
+		rsp, err := b.hotpotato.ListAchievements(ctx, &hotpotato.ListAchievementsRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to handle achievements request: %w", err)
+		}
+
+		names := b.displayNames(s, i.GuildID, user.ID)
+
+		return b.reply(s, i, AchievementsSuccessReply(user.ID, rsp, names))
+	}
+}
+
+func (b *Bot) HotPotatoTeamsSubCommandGroup() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "teams",
+		Description: "Play Hot Potato in teams",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Start a team game, splitting the last game's players into teams unless roles are given",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "team-1",
+						Description: "Role whose members make up the first team",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "team-2",
+						Description: "Role whose members make up the second team",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "leaderboard",
+				Description: "View the teams that have lost the most team games",
+			},
+		},
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		action := data.Options[0]
+		if action.Name == "leaderboard" {
+			rsp, err := b.hotpotato.GetTeamLeaderboard(ctx, &hotpotato.GetTeamLeaderboardRequest{
+				Namespace: namespace,
+				RoomID:    i.GuildID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to handle team leaderboard request: %w", err)
+			}
+
+			return b.reply(s, i, TeamLeaderboardSuccessReply(rsp))
+		}
+
+		var roles []*discordgo.Role
+		for _, option := range action.Options {
+			if option.Type == discordgo.ApplicationCommandOptionRole {
+				roles = append(roles, option.RoleValue(s, i.GuildID))
+			}
+		}
+
+		var teams map[string][]string
+		if len(roles) > 0 {
+			roleIDs := make([]string, 0, len(roles))
+			for _, role := range roles {
+				roleIDs = append(roleIDs, role.ID)
+			}
+
+			members, err := b.roleMembers(s, i.GuildID, roleIDs...)
+			if err != nil {
+				return fmt.Errorf("failed to list role members: %w", err)
+			}
+
+			teams = make(map[string][]string, len(roles))
+			for _, role := range roles {
+				name := role.Name
+				if name == "" {
+					name = role.ID
+				}
+				teams[name] = members[role.ID]
+			}
+		}
+
+		rsp, err := b.hotpotato.StartTeamGame(ctx, &hotpotato.StartTeamGameRequest{
+			Namespace:   namespace,
+			RoomID:      i.GuildID,
+			ChannelID:   i.ChannelID,
+			ActorUserID: i.Interaction.Member.User.ID,
+			Teams:       teams,
+		})
+		if err != nil {
+			var e *hotpotato.NotOnTeamError
+			switch {
+			case errors.Is(err, hotpotato.ErrGameInProgress):
+				return b.reply(s, i, TeamGameInProgressReply())
+			case errors.Is(err, hotpotato.ErrNotEnoughPlayers):
+				return b.reply(s, i, NotEnoughPlayersReply())
+			case errors.As(err, &e):
+				return b.reply(s, i, NotOnTeamReply(e.UserID))
+			default:
+				return fmt.Errorf("failed to handle start team game request: %w", err)
+			}
+		}
+
+		return b.reply(s, i, TeamGameStartedReply(rsp))
+	}
+}
+
+func (b *Bot) HotPotatoOptInSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "optin",
+		Description: "Opt in to having hot potatoes tossed to you!",
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		user := i.Interaction.Member.User
+
+		rsp, err := b.hotpotato.OptIn(ctx, &hotpotato.OptInRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to handle opt in request: %w", err)
+		}
+
+		return b.reply(s, i, OptedInReply(user.ID, rsp))
+	}
+}
+
+func (b *Bot) HotPotatoOptOutSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "optout",
+		Description: "Opt out of having hot potatoes tossed to you",
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		user := i.Interaction.Member.User
+
+		rsp, err := b.hotpotato.OptOut(ctx, &hotpotato.OptOutRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to handle opt out request: %w", err)
+		}
+
+		return b.reply(s, i, OptedOutReply(user.ID, rsp))
+	}
+}
+
+func (b *Bot) HotPotatoUseSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "use",
+		Description: "Ready an item from your inventory for the next turn it comes in handy",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Item to ready",
+				Required:    true,
+				Choices:     itemChoices(),
+			},
+		},
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		rsp, err := b.hotpotato.UseItem(ctx, &hotpotato.UseItemRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			UserID:    i.Interaction.Member.User.ID,
+			ItemID:    data.Options[0].StringValue(),
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, hotpotato.ErrItemNotHeld):
+				return b.reply(s, i, ItemNotHeldReply())
+			case errors.Is(err, hotpotato.ErrItemAlreadyArmed):
+				return b.reply(s, i, ItemAlreadyArmedReply())
+			default:
+				return fmt.Errorf("failed to handle use item request: %w", err)
+			}
+		}
+
+		return b.reply(s, i, ItemArmedReply(rsp))
+	}
+}
+
+func (b *Bot) HotPotatoJoinSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "join",
+		Description: "Join the lobby for the next elimination game in this channel!",
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		user := i.Interaction.Member.User
+
+		rsp, err := b.hotpotato.JoinLobby(ctx, &hotpotato.JoinLobbyRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			ChannelID: i.ChannelID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to handle join lobby request: %w", err)
+		}
+
+		return b.reply(s, i, LobbyJoinedReply(user.ID, rsp))
+	}
+}
+
+func (b *Bot) HotPotatoEliminationSubCommand() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "elimination",
+		Description: "Start a last-one-standing game with the players in the lobby!",
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		rsp, err := b.hotpotato.StartElimination(ctx, &hotpotato.StartEliminationRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			ChannelID: i.ChannelID,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, hotpotato.ErrGameInProgress):
+				return b.reply(s, i, EliminationInProgressReply())
+			case errors.Is(err, hotpotato.ErrLobbyTooSmall):
+				return b.reply(s, i, LobbyTooSmallReply())
+			default:
+				return fmt.Errorf("failed to handle start elimination request: %w", err)
+			}
+		}
+
+		return b.reply(s, i, EliminationStartedReply(rsp))
+	}
+}
+
+func (b *Bot) HotPotatoTournamentSubCommandGroup() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "tournament",
+		Description: "Play a single-elimination Hot Potato tournament",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "register",
+				Description: "Register players for the next tournament, setting it up in this channel",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "User to register",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "Role whose members to register",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Draw the first round of heats and start the tournament",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "bracket",
+				Description: "View the bracket of the current tournament",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "cancel",
+				Description: "Cancel the current tournament",
+			},
+		},
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		action := data.Options[0]
+		if action.Name == "bracket" {
+			rsp, err := b.hotpotato.GetTournament(ctx, &hotpotato.GetTournamentRequest{
+				Namespace: namespace,
+				RoomID:    i.GuildID,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, hotpotato.ErrNoTournament):
+					return b.reply(s, i, NoTournamentReply())
+				default:
+					return fmt.Errorf("failed to handle get tournament request: %w", err)
+				}
+			}
+
+			return b.reply(s, i, TournamentBracketReply(rsp.Tournament))
+		}
+
+		if i.Member == nil || i.Member.Permissions&(discordgo.PermissionManageServer|discordgo.PermissionAdministrator) == 0 {
+			return b.reply(s, i, TournamentForbiddenReply())
+		}
+
+		switch action.Name {
+		case "register":
+			var userIDs []string
+			for _, option := range action.Options {
+				switch option.Type {
+				case discordgo.ApplicationCommandOptionUser:
+					if user := option.UserValue(s); !user.Bot {
+						userIDs = append(userIDs, user.ID)
+					}
+				case discordgo.ApplicationCommandOptionRole:
+					roleID := option.RoleValue(s, i.GuildID).ID
+					members, err := b.roleMembers(s, i.GuildID, roleID)
+					if err != nil {
+						return fmt.Errorf("failed to list role members: %w", err)
+					}
+					userIDs = append(userIDs, members[roleID]...)
+				}
+			}
+
+			if len(userIDs) == 0 {
+				return b.reply(s, i, TournamentNoEntrantsReply())
+			}
+
+			rsp, err := b.hotpotato.RegisterTournamentPlayers(ctx, &hotpotato.RegisterTournamentPlayersRequest{
+				Namespace: namespace,
+				RoomID:    i.GuildID,
+				ChannelID: i.ChannelID,
+				UserIDs:   userIDs,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, hotpotato.ErrTournamentStarted):
+					return b.reply(s, i, TournamentAlreadyStartedReply())
+				default:
+					return fmt.Errorf("failed to handle register tournament players request: %w", err)
+				}
+			}
+
+			return b.reply(s, i, TournamentRegisteredReply(rsp))
+
+		case "start":
+			rsp, err := b.hotpotato.StartTournament(ctx, &hotpotato.StartTournamentRequest{
+				Namespace: namespace,
+				RoomID:    i.GuildID,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, hotpotato.ErrNoTournament):
+					return b.reply(s, i, NoTournamentReply())
+				case errors.Is(err, hotpotato.ErrTournamentStarted):
+					return b.reply(s, i, TournamentAlreadyStartedReply())
+				case errors.Is(err, hotpotato.ErrNotEnoughEntrants):
+					return b.reply(s, i, NotEnoughEntrantsReply())
+				default:
+					return fmt.Errorf("failed to handle start tournament request: %w", err)
+				}
+			}
+
+			if err := b.reply(s, i, TournamentBracketReply(rsp.Update.Tournament)); err != nil {
+				return err
+			}
+
+			b.openHeats(ctx, s, i.GuildID, rsp.Update.Tournament, rsp.Update.Heats)
+			return nil
+
+		case "cancel":
+			rsp, err := b.hotpotato.CancelTournament(ctx, &hotpotato.CancelTournamentRequest{
+				Namespace: namespace,
+				RoomID:    i.GuildID,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, hotpotato.ErrNoTournament):
+					return b.reply(s, i, NoTournamentReply())
+				default:
+					return fmt.Errorf("failed to handle cancel tournament request: %w", err)
+				}
+			}
+
+			return b.reply(s, i, TournamentCancelledReply(rsp.Tournament))
+		}
+
+		return nil
+	}
+}
+
+func (b *Bot) HotPotatoConfigSubCommandGroup() (*discordgo.ApplicationCommandOption, SubCommandHandler) {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "config",
+		Description: "Configure how Hot Potato is played in this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "View the current game settings",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Change the game settings",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "potatoes",
+						Description: "Comma-separated kinds of potatoes allowed in new games, or 'all'",
+					},
+					{
+						Type:        ApplicationCommandOptionNumber,
+						Name:        "explode-multiplier",
+						Description: "Multiplier for the chance of a potato exploding each turn",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "steal",
+						Description: "Whether potatoes can be stolen",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "cook-cap",
+						Description: "Heat level beyond which a potato can't be cooked, or 0 for no cap",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "fuse-timeout",
+						Description: "Seconds a potato can be held before it explodes, or 0 for the default",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "leaderboard-size",
+						Description: "Number of users shown on each page of the leaderboard",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "potato-limit",
+						Description: "Number of potatoes that can be in play in a channel at once",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Reset the game settings to their defaults",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "end-season",
+				Description: "End the current season, archiving its leaderboard and starting a new one",
+			},
+		},
+	}
+
+	return opt, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.ApplicationCommandInteractionDataOption) error {
+		if i.Member == nil || i.Member.Permissions&(discordgo.PermissionManageServer|discordgo.PermissionAdministrator) == 0 {
+			return b.reply(s, i, ConfigForbiddenReply())
+		}
+
+		action := data.Options[0]
+		if action.Name == "end-season" {
+			rsp, err := b.hotpotato.EndSeason(ctx, &hotpotato.EndSeasonRequest{
+				Namespace: namespace,
+				RoomID:    i.GuildID,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, hotpotato.ErrSeasonAlreadyEnded):
+					return b.reply(s, i, SeasonAlreadyEndedReply())
+				default:
+					return fmt.Errorf("failed to handle end season request: %w", err)
+				}
+			}
+
+			return b.reply(s, i, SeasonEndedReply(rsp))
+		}
+
+		rsp, err := b.hotpotato.GetSettings(ctx, &hotpotato.GetSettingsRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to handle get settings request: %w", err)
+		}
+
+		settings := rsp.Settings
+
+		switch action.Name {
+		case "view":
+			return b.reply(s, i, ConfigSuccessReply(settings))
+		case "reset":
+			settings = room.DefaultSettings()
+		case "set":
+			for _, option := range action.Options {
+				switch option.Name {
+				case "potatoes":
+					settings.AllowedPotatoKinds = parsePotatoKinds(option.StringValue())
+				case "explode-multiplier":
+					settings.ExplodeMultiplier = option.FloatValue()
+				case "steal":
+					settings.StealEnabled = option.BoolValue()
+				case "cook-cap":
+					settings.CookCap = int(option.IntValue())
+				case "fuse-timeout":
+					settings.FuseTimeout = time.Duration(option.IntValue()) * time.Second
+				case "leaderboard-size":
+					settings.LeaderboardSize = int(option.IntValue())
+				case "potato-limit":
+					settings.PotatoLimit = int(option.IntValue())
+				}
+			}
+		default:
+			return nil
+		}
+
+		updated, err := b.hotpotato.UpdateSettings(ctx, &hotpotato.UpdateSettingsRequest{
+			Namespace: namespace,
+			RoomID:    i.GuildID,
+			Settings:  settings,
+		})
+		if err != nil {
+			var e *hotpotato.InvalidSettingsError
+			switch {
+			case errors.Is(err, hotpotato.ErrInvalidPotatoKind):
+				return b.reply(s, i, ConfigInvalidPotatoKindReply())
+			case errors.As(err, &e):
+				return b.reply(s, i, ConfigInvalidReply(e.Err.Error()))
+			default:
+				return fmt.Errorf("failed to handle update settings request: %w", err)
+			}
+		}
+
+		return b.reply(s, i, ConfigSuccessReply(updated.Settings))
+	}
+}
+
+func itemChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(hotpotato.DefaultItems))
+	for i, item := range hotpotato.DefaultItems {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  item.String(),
+			Value: item.ID,
+		}
+	}
+
+	return choices
+}
+
+func metricChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(hotpotato.Metrics))
+	for i, metric := range hotpotato.Metrics {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  MetricLabels[metric],
+			Value: string(metric),
+		}
+	}
+
+	return choices
+}
+
+// potatoCommandOption is the option used to pick one of the potatoes in play in
+// the channel, which is autocompleted from the potatoes in play.
+func potatoCommandOption(description string) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:         discordgo.ApplicationCommandOptionInteger,
+		Name:         "potato",
+		Description:  description,
+		Autocomplete: true,
+	}
+}
+
+// potatoOptionValue returns the ID of the potato picked in the given options,
+// or 0 if none was picked.
+func potatoOptionValue(options []*discordgo.ApplicationCommandInteractionDataOption) int {
+	for _, option := range options {
+		if option.Name == "potato" {
+			return int(option.IntValue())
+		}
+	}
+
+	return 0
+}
+
+func parsePotatoKinds(value string) []string {
+	var kinds []string
+	for _, kind := range strings.Split(value, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" || kind == "all" {
+			continue
+		}
+		kinds = append(kinds, kind)
+	}
+
+	return kinds
+}
+
+func (b *Bot) leaderboard(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, season int, metric hotpotato.Metric, page int, update bool) error {
+	rsp, err := b.hotpotato.GetLeaderboard(ctx, &hotpotato.GetLeaderboardRequest{
+		Namespace: namespace,
+		RoomID:    i.GuildID,
+		Season:    season,
+		Metric:    metric,
+		Page:      page,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, hotpotato.ErrSeasonNotFound):
+			return b.reply(s, i, SeasonNotFoundReply(season))
+		default:
 			return fmt.Errorf("failed to handle leaderboard request: %w", err)
 		}
+	}
 
-		return b.reply(s, i, LeaderboardSuccessReply(rsp))
+	userIDs := make([]string, len(rsp.Leaderboard))
+	for i, entry := range rsp.Leaderboard {
+		userIDs[i] = entry.UserID
 	}
+
+	reply := LeaderboardSuccessReply(rsp, b.displayNames(s, i.GuildID, userIDs...))
+	reply.Update = update
+
+	return b.reply(s, i, reply)
+}
+
+// toss tosses the potato to the given target, or to one picked at random if
+// the target is empty.
+func (b *Bot) toss(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, actorUserID, targetUserID string, potatoID int) error {
+	rsp, err := b.hotpotato.Toss(ctx, &hotpotato.TossRequest{
+		Namespace:    namespace,
+		RoomID:       i.GuildID,
+		ChannelID:    i.ChannelID,
+		ActorUserID:  actorUserID,
+		TargetUserID: targetUserID,
+		Random:       targetUserID == "",
+		PotatoID:     potatoID,
+	})
+	if err != nil {
+		var e *hotpotato.NotHolderError
+		var c *hotpotato.CooldownError
+		var t *hotpotato.NotOnTeamError
+		var h *hotpotato.NotInHeatError
+		var p *hotpotato.NotPlayingError
+		var o *hotpotato.NotOptedInError
+		switch {
+		case errors.As(err, &c):
+			return b.reply(s, i, CooldownReply(c.RetryAfter))
+		case errors.Is(err, hotpotato.ErrNoOngoingGame):
+			return b.reply(s, i, NoOngoingGameReply())
+		case errors.Is(err, hotpotato.ErrPotatoNotFound):
+			return b.reply(s, i, PotatoNotFoundReply())
+		case errors.Is(err, hotpotato.ErrNoTossTarget):
+			return b.reply(s, i, TossRandomNoTargetReply())
+		case errors.As(err, &o):
+			return b.reply(s, i, NotOptedInReply(o.UserID))
+		case errors.Is(err, hotpotato.ErrTeammateToss):
+			return b.reply(s, i, TossTeammateReply(targetUserID))
+		case errors.As(err, &t):
+			return b.reply(s, i, NotOnTeamReply(t.UserID))
+		case errors.As(err, &h):
+			return b.reply(s, i, NotInHeatReply(h.UserID))
+		case errors.As(err, &p):
+			return b.reply(s, i, NotPlayingReply(p.UserID))
+		case errors.As(err, &e):
+			return b.reply(s, i, TossNotHolderReply(e.HolderUserID))
+		default:
+			return fmt.Errorf("failed to handle toss request: %w", err)
+		}
+	}
+
+	if err := b.reply(s, i, TossSuccessReply(b.random, actorUserID, rsp.TargetUserID, rsp)); err != nil {
+		return err
+	}
+
+	b.announceTournament(ctx, s, i.GuildID, rsp.Tournament)
+	return nil
+}
+
+func (b *Bot) cook(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, actorUserID string, potatoID int) error {
+	rsp, err := b.hotpotato.Cook(ctx, &hotpotato.CookRequest{
+		Namespace:   namespace,
+		RoomID:      i.GuildID,
+		ChannelID:   i.ChannelID,
+		ActorUserID: actorUserID,
+		PotatoID:    potatoID,
+	})
+	if err != nil {
+		var e *hotpotato.NotHolderError
+		var c *hotpotato.CooldownError
+		switch {
+		case errors.As(err, &c):
+			return b.reply(s, i, CooldownReply(c.RetryAfter))
+		case errors.Is(err, hotpotato.ErrNoOngoingGame):
+			return b.reply(s, i, NoOngoingGameReply())
+		case errors.Is(err, hotpotato.ErrPotatoNotFound):
+			return b.reply(s, i, PotatoNotFoundReply())
+		case errors.Is(err, hotpotato.ErrCookCapReached):
+			return b.reply(s, i, CookCapReachedReply())
+		case errors.As(err, &e):
+			return b.reply(s, i, CookNotHolderReply(e.HolderUserID))
+		default:
+			return fmt.Errorf("failed to handle cook request: %w", err)
+		}
+	}
+
+	if err := b.reply(s, i, CookSuccessReply(b.random, actorUserID, rsp)); err != nil {
+		return err
+	}
+
+	b.announceTournament(ctx, s, i.GuildID, rsp.Tournament)
+	return nil
 }
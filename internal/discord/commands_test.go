@@ -0,0 +1,61 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+)
+
+// stealService is a hotpotato.Service that only steals potatoes, recording the
+// requests that it was given.
+type stealService struct {
+	hotpotato.Service
+	requests []*hotpotato.StealRequest
+}
+
+func (s *stealService) Steal(ctx context.Context, req *hotpotato.StealRequest) (*hotpotato.StealResponse, error) {
+	s.requests = append(s.requests, req)
+	return &hotpotato.StealResponse{
+		PotatoID:     req.PotatoID,
+		Potato:       hotpotato.DefaultPotatoRegistry().Random(hotpotato.NewRandomizer(1)),
+		HolderUserID: req.ActorUserID,
+	}, nil
+}
+
+func TestStealSubCommandOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  string
+		potatoID int
+	}{
+		{name: "user only", options: `[{"name":"user","type":6,"value":"holder"}]`},
+		{name: "user first", options: `[{"name":"user","type":6,"value":"holder"},{"name":"potato","type":4,"value":2}]`, potatoID: 2},
+		{name: "potato first", options: `[{"name":"potato","type":4,"value":2},{"name":"user","type":6,"value":"holder"}]`, potatoID: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stealService{}
+			b, privateKey := newTestBot(t, service)
+
+			body := `{"type":2,"id":"command","guild_id":"guild","channel_id":"channel","member":{"user":{"id":"thief"}},"data":{"id":"hotpotato","name":"hotpotato","options":[{"name":"steal","type":1,"options":` + tt.options + `}]}}`
+
+			w := httptest.NewRecorder()
+			b.router().ServeHTTP(w, signedRequest(privateKey, body))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+			}
+
+			if len(service.requests) != 1 {
+				t.Fatalf("steal subcommand handled %d requests, want 1", len(service.requests))
+			}
+			if req := service.requests[0]; req.ActorUserID != "thief" || req.TargetUserID != "holder" || req.PotatoID != tt.potatoID {
+				t.Errorf("unexpected steal request: %+v", req)
+			}
+		})
+	}
+}
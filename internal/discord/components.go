@@ -0,0 +1,140 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+)
+
+const (
+	componentIDPrefix    = "hotpotato"
+	componentIDSeparator = ":"
+)
+
+type ComponentEntry func() (string, ComponentHandler)
+type ComponentHandler = func(context.Context, *discordgo.Session, *discordgo.InteractionCreate, []string) error
+
+// ComponentID builds the custom ID of a message component routed to the named
+// component handler, carrying the given arguments.
+func ComponentID(name string, args ...string) string {
+	return strings.Join(append([]string{componentIDPrefix, name}, args...), componentIDSeparator)
+}
+
+func (b *Bot) HotPotatoComponentRouter() func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	components := []ComponentEntry{
+		b.HotPotatoTossBackComponent,
+		b.HotPotatoCookComponent,
+		b.HotPotatoTossRandomComponent,
+		b.HotPotatoLeaderboardPageComponent,
+	}
+
+	handlers := make(map[string]ComponentHandler)
+	for _, entry := range components {
+		name, handler := entry()
+		handlers[name] = handler
+	}
+
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		parts := strings.Split(i.MessageComponentData().CustomID, componentIDSeparator)
+		if len(parts) < 2 || parts[0] != componentIDPrefix {
+			return
+		}
+
+		name, args := parts[1], parts[2:]
+		logger := log.WithSuffix(b.logger, "component", name, "guild", i.GuildID, "channel", i.ChannelID, "interaction", i.Interaction.ID)
+
+		handle, ok := handlers[name]
+		if !ok {
+			level.Error(logger).Log("event", "component.unknown")
+			return
+		}
+
+		level.Info(logger).Log("event", "component.received")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		if err := handle(ctx, s, i, args); err != nil {
+			logger := log.With(logger, "source", log.Caller(2))
+			level.Error(logger).Log("event", "component.handle.failure", "err", err)
+			b.reply(s, i, UnexpectedErrorReply())
+			return
+		}
+
+		level.Info(logger).Log("event", "component.handle.success")
+	}
+}
+
+func (b *Bot) HotPotatoTossBackComponent() (string, ComponentHandler) {
+	return "toss", func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("invalid toss component arguments: %v", args)
+		}
+
+		potatoID, err := componentPotatoID(args, 1)
+		if err != nil {
+			return fmt.Errorf("invalid toss component potato: %w", err)
+		}
+
+		return b.toss(ctx, s, i, i.Interaction.Member.User.ID, args[0], potatoID)
+	}
+}
+
+func (b *Bot) HotPotatoCookComponent() (string, ComponentHandler) {
+	return "cook", func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+		potatoID, err := componentPotatoID(args, 0)
+		if err != nil {
+			return fmt.Errorf("invalid cook component potato: %w", err)
+		}
+
+		return b.cook(ctx, s, i, i.Interaction.Member.User.ID, potatoID)
+	}
+}
+
+func (b *Bot) HotPotatoTossRandomComponent() (string, ComponentHandler) {
+	return "toss-random", func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+		potatoID, err := componentPotatoID(args, 0)
+		if err != nil {
+			return fmt.Errorf("invalid toss random component potato: %w", err)
+		}
+
+		return b.toss(ctx, s, i, i.Interaction.Member.User.ID, "", potatoID)
+	}
+}
+
+func (b *Bot) HotPotatoLeaderboardPageComponent() (string, ComponentHandler) {
+	return "leaderboard", func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+		if len(args) != 3 {
+			return fmt.Errorf("invalid leaderboard component arguments: %v", args)
+		}
+
+		season, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid leaderboard component season: %w", err)
+		}
+
+		page, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid leaderboard component page: %w", err)
+		}
+
+		return b.leaderboard(ctx, s, i, season, hotpotato.Metric(args[1]), page, true)
+	}
+}
+
+// componentPotatoID parses the potato ID carried at the given index of the
+// component arguments, or 0 for components sent before potatoes had IDs.
+func componentPotatoID(args []string, index int) (int, error) {
+	if len(args) <= index {
+		return 0, nil
+	}
+
+	return strconv.Atoi(args[index])
+}
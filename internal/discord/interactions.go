@@ -0,0 +1,153 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// interactionDeferTimeout is how long a handler is given to respond to an
+// interaction received over HTTP before the response is deferred, leaving time
+// for the deferral to reach Discord within its 3 second deadline.
+const interactionDeferTimeout = 2500 * time.Millisecond
+
+func (b *Bot) interactionsEndpoint(w http.ResponseWriter, r *http.Request) {
+	if !discordgo.VerifyInteraction(r, b.publicKey) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var i discordgo.InteractionCreate
+	if err := json.NewDecoder(r.Body).Decode(&i); err != nil || i.Interaction == nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	if i.Type == discordgo.InteractionPing {
+		writeInteractionResponse(w, &discordgo.InteractionResponse{Type: discordgo.InteractionResponsePong})
+		return
+	}
+
+	responses := make(chan *discordgo.InteractionResponse, 1)
+	b.pending.Store(i.ID, responses)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.interactions(b.discord, &i)
+	}()
+
+	// Autocomplete interactions can't be deferred, so they are left to Discord
+	// to time out.
+	var deadline <-chan time.Time
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		deadline = time.After(b.deferTimeout)
+	}
+
+	select {
+	case ir := <-responses:
+		b.pending.Delete(i.ID)
+		writeInteractionResponse(w, ir)
+	case <-done:
+		b.pending.Delete(i.ID)
+		if ir, ok := awaitResponse(responses, done); ok {
+			writeInteractionResponse(w, ir)
+			return
+		}
+		level.Error(b.logger).Log("event", "interaction.respond.missing", "interaction", i.ID)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	case <-deadline:
+		writeInteractionResponse(w, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+		go b.followUp(&i, responses, done)
+	}
+}
+
+// followUp waits for the response to an interaction that was deferred, and
+// delivers it through the interaction's webhook in place of the deferral.
+func (b *Bot) followUp(i *discordgo.InteractionCreate, responses <-chan *discordgo.InteractionResponse, done <-chan struct{}) {
+	defer b.pending.Delete(i.ID)
+
+	logger := log.WithSuffix(b.logger, "interaction", i.ID)
+
+	ir, ok := awaitResponse(responses, done)
+	if !ok || ir.Data == nil {
+		level.Error(logger).Log("event", "interaction.respond.missing")
+		return
+	}
+
+	if err := b.respondDeferred(b.discord, i, ir.Data); err != nil {
+		level.Error(logger).Log("event", "interaction.followup.failure", "err", err)
+		return
+	}
+	level.Info(logger).Log("event", "interaction.followup.success")
+}
+
+// respondDeferred replaces the deferral of an interaction with its response.
+// Whether a deferred response is ephemeral is fixed when it is deferred, so
+// ephemeral responses are sent as a follow-up once the deferral is removed.
+func (b *Bot) respondDeferred(s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.InteractionResponseData) error {
+	appID := s.State.User.ID
+
+	if data.Flags&MessageFlagEphemeral == 0 {
+		_, err := s.InteractionResponseEdit(appID, i.Interaction, &discordgo.WebhookEdit{
+			Content:         data.Content,
+			Components:      data.Components,
+			Embeds:          data.Embeds,
+			AllowedMentions: data.AllowedMentions,
+		})
+		return err
+	}
+
+	if err := s.InteractionResponseDelete(appID, i.Interaction); err != nil {
+		return err
+	}
+
+	_, err := s.FollowupMessageCreate(appID, i.Interaction, false, &discordgo.WebhookParams{
+		Content:         data.Content,
+		Components:      data.Components,
+		Embeds:          data.Embeds,
+		AllowedMentions: data.AllowedMentions,
+		Flags:           data.Flags,
+	})
+	return err
+}
+
+// awaitResponse waits for the handler of an interaction to respond to it,
+// reporting false if it finished without responding.
+func awaitResponse(responses <-chan *discordgo.InteractionResponse, done <-chan struct{}) (*discordgo.InteractionResponse, bool) {
+	select {
+	case ir := <-responses:
+		return ir, true
+	case <-done:
+	}
+
+	select {
+	case ir := <-responses:
+		return ir, true
+	default:
+		return nil, false
+	}
+}
+
+// respond responds to an interaction, either over the HTTP request it was
+// received on or through the Discord API if it came over the gateway.
+func (b *Bot) respond(s *discordgo.Session, i *discordgo.InteractionCreate, ir *discordgo.InteractionResponse) error {
+	if responses, ok := b.pending.Load(i.ID); ok {
+		select {
+		case responses.(chan *discordgo.InteractionResponse) <- ir:
+		default:
+		}
+		return nil
+	}
+
+	return s.InteractionRespond(i.Interaction, ir)
+}
+
+func writeInteractionResponse(w http.ResponseWriter, ir *discordgo.InteractionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ir)
+}
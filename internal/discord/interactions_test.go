@@ -0,0 +1,285 @@
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/kit/log"
+
+	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+)
+
+// holderService is a hotpotato.Service that only answers who holds the potato,
+// recording the requests that it was given.
+type holderService struct {
+	hotpotato.Service
+	requests []*hotpotato.GetHolderRequest
+}
+
+func (s *holderService) GetHolder(ctx context.Context, req *hotpotato.GetHolderRequest) (*hotpotato.GetHolderResponse, error) {
+	s.requests = append(s.requests, req)
+	return &hotpotato.GetHolderResponse{
+		Potatoes: []*hotpotato.LivePotato{
+			{ID: 1, Potato: hotpotato.DefaultPotatoRegistry().Random(hotpotato.NewRandomizer(1)), HolderUserID: "holder"},
+		},
+	}, nil
+}
+
+func newTestBot(t *testing.T, service hotpotato.Service) (*Bot, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	b := &Bot{
+		logger:    log.NewNopLogger(),
+		publicKey: publicKey,
+		hotpotato: service,
+
+		deferTimeout: interactionDeferTimeout,
+	}
+
+	_, rootHandler := b.HotPotatoRootCommand()
+	b.interactions = func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Type == discordgo.InteractionApplicationCommand {
+			rootHandler(s, i)
+		}
+	}
+
+	return b, privateKey
+}
+
+func signedRequest(privateKey ed25519.PrivateKey, body string) *http.Request {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := ed25519.Sign(privateKey, []byte(timestamp+body))
+
+	r := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(body))
+	r.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	r.Header.Set("X-Signature-Timestamp", timestamp)
+	return r
+}
+
+func TestInteractionsEndpointPing(t *testing.T) {
+	b, privateKey := newTestBot(t, &holderService{})
+
+	w := httptest.NewRecorder()
+	b.router().ServeHTTP(w, signedRequest(privateKey, `{"type":1,"id":"ping"}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var ir discordgo.InteractionResponse
+	if err := json.NewDecoder(w.Body).Decode(&ir); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if ir.Type != discordgo.InteractionResponsePong {
+		t.Errorf("got response type %d, want %d", ir.Type, discordgo.InteractionResponsePong)
+	}
+}
+
+func TestInteractionsEndpointSignature(t *testing.T) {
+	const body = `{"type":1,"id":"ping"}`
+
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		request func(privateKey ed25519.PrivateKey) *http.Request
+	}{
+		{
+			name: "missing signature",
+			request: func(privateKey ed25519.PrivateKey) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(body))
+			},
+		},
+		{
+			name: "tampered body",
+			request: func(privateKey ed25519.PrivateKey) *http.Request {
+				r := signedRequest(privateKey, body)
+				r.Body = io.NopCloser(strings.NewReader(`{"type":1,"id":"tampered"}`))
+				return r
+			},
+		},
+		{
+			name: "tampered timestamp",
+			request: func(privateKey ed25519.PrivateKey) *http.Request {
+				r := signedRequest(privateKey, body)
+				r.Header.Set("X-Signature-Timestamp", "0")
+				return r
+			},
+		},
+		{
+			name: "signed by another key",
+			request: func(privateKey ed25519.PrivateKey) *http.Request {
+				return signedRequest(otherKey, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &holderService{}
+			b, privateKey := newTestBot(t, service)
+
+			w := httptest.NewRecorder()
+			b.router().ServeHTTP(w, tt.request(privateKey))
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("got status %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestInteractionsEndpointCommand(t *testing.T) {
+	service := &holderService{}
+	b, privateKey := newTestBot(t, service)
+
+	body := `{"type":2,"id":"command","guild_id":"guild","channel_id":"channel","data":{"id":"hotpotato","name":"hotpotato","options":[{"name":"where","type":1}]}}`
+
+	w := httptest.NewRecorder()
+	b.router().ServeHTTP(w, signedRequest(privateKey, body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if len(service.requests) != 1 {
+		t.Fatalf("where subcommand handled %d requests, want 1", len(service.requests))
+	}
+	if req := service.requests[0]; req.RoomID != "guild" || req.ChannelID != "channel" {
+		t.Errorf("unexpected holder request: %+v", req)
+	}
+
+	var ir discordgo.InteractionResponse
+	if err := json.NewDecoder(w.Body).Decode(&ir); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if ir.Type != discordgo.InteractionResponseChannelMessageWithSource || ir.Data == nil || !strings.Contains(ir.Data.Content, "<@!holder>") {
+		t.Errorf("unexpected response: %+v", ir)
+	}
+}
+
+// slowService is a hotpotato.Service that takes until it is released to answer
+// who holds the potato.
+type slowService struct {
+	hotpotato.Service
+	release chan struct{}
+	err     error
+}
+
+func (s *slowService) GetHolder(ctx context.Context, req *hotpotato.GetHolderRequest) (*hotpotato.GetHolderResponse, error) {
+	<-s.release
+	if s.err != nil {
+		return nil, s.err
+	}
+	return (&holderService{}).GetHolder(ctx, req)
+}
+
+// webhookRequest is a request made to an interaction's webhook.
+type webhookRequest struct {
+	method string
+	path   string
+	params discordgo.WebhookParams
+}
+
+// redirectTransport sends requests meant for Discord to a test server instead.
+type redirectTransport struct {
+	host string
+}
+
+func (rt *redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = "http", rt.host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestInteractionsEndpointDeferred(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		requests  []string
+		ephemeral bool
+	}{
+		{
+			name:     "edited",
+			requests: []string{http.MethodPatch + " /webhooks/app/token/messages/@original"},
+		},
+		{
+			name:      "followed up",
+			err:       hotpotato.ErrNoOngoingGame,
+			requests:  []string{http.MethodDelete + " /webhooks/app/token/messages/@original", http.MethodPost + " /webhooks/app/token"},
+			ephemeral: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := make(chan webhookRequest, len(tt.requests))
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req := webhookRequest{method: r.Method, path: strings.TrimPrefix(r.URL.Path, "/api/v"+discordgo.APIVersion)}
+				json.NewDecoder(r.Body).Decode(&req.params)
+				webhooks <- req
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			service := &slowService{release: make(chan struct{}), err: tt.err}
+			b, privateKey := newTestBot(t, service)
+			b.deferTimeout = 10 * time.Millisecond
+
+			session, err := discordgo.New("Bot token")
+			if err != nil {
+				t.Fatalf("failed to create session: %v", err)
+			}
+			session.Client = &http.Client{Transport: &redirectTransport{host: strings.TrimPrefix(server.URL, "http://")}}
+			session.State.User = &discordgo.User{ID: "app"}
+			b.discord = session
+
+			body := `{"type":2,"id":"command","token":"token","guild_id":"guild","channel_id":"channel","data":{"id":"hotpotato","name":"hotpotato","options":[{"name":"where","type":1}]}}`
+
+			w := httptest.NewRecorder()
+			b.router().ServeHTTP(w, signedRequest(privateKey, body))
+
+			var ir discordgo.InteractionResponse
+			if err := json.NewDecoder(w.Body).Decode(&ir); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if ir.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+				t.Fatalf("got response type %d, want %d", ir.Type, discordgo.InteractionResponseDeferredChannelMessageWithSource)
+			}
+
+			close(service.release)
+
+			for _, want := range tt.requests {
+				select {
+				case req := <-webhooks:
+					if got := req.method + " " + req.path; got != want {
+						t.Fatalf("got webhook request %q, want %q", got, want)
+					}
+					if req.method == http.MethodDelete {
+						continue
+					}
+					if req.params.Content == "" || (req.params.Flags&MessageFlagEphemeral != 0) != tt.ephemeral {
+						t.Errorf("unexpected webhook request: %+v", req.params)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("webhook request %q never made", want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/log/level"
+)
+
+// displayNames resolves the names that the given users go by in the guild,
+// looking them up in the state cache before asking Discord. Users that can't
+// be resolved, such as those who have left the guild, are left out.
+func (b *Bot) displayNames(s *discordgo.Session, guildID string, userIDs ...string) map[string]string {
+	names := make(map[string]string, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := names[userID]; ok || userID == "" {
+			continue
+		}
+
+		member, err := s.State.Member(guildID, userID)
+		if err != nil {
+			member, err = s.GuildMember(guildID, userID)
+			if err != nil {
+				level.Warn(b.logger).Log("event", "member.resolve.failure", "guild", guildID, "user", userID, "err", err)
+				continue
+			}
+		}
+
+		switch {
+		case member.Nick != "":
+			names[userID] = member.Nick
+		case member.User != nil:
+			names[userID] = member.User.Username
+		}
+	}
+
+	return names
+}
+
+// membersLimit is the most members that Discord returns in a single request
+// for listing the members of a guild.
+const membersLimit = 1000
+
+// roleMembers lists the users that have each of the given roles in the guild,
+// leaving out bots. Users with more than one of the roles are only listed
+// under the first of them.
+func (b *Bot) roleMembers(s *discordgo.Session, guildID string, roleIDs ...string) (map[string][]string, error) {
+	members := make(map[string][]string, len(roleIDs))
+
+	var after string
+	for {
+		page, err := s.GuildMembers(guildID, after, membersLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range page {
+			if member.User == nil || member.User.Bot {
+				continue
+			}
+
+			for _, roleID := range roleIDs {
+				if hasRole(member, roleID) {
+					members[roleID] = append(members[roleID], member.User.ID)
+					break
+				}
+			}
+		}
+
+		if len(page) < membersLimit {
+			return members, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
+}
+
+func hasRole(member *discordgo.Member, roleID string) bool {
+	for _, id := range member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
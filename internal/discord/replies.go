@@ -2,23 +2,39 @@ package discord
 
 import (
 	"fmt"
-	"math/rand"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
+	"github.com/jace-ys/hot-potato-discord/internal/game"
 	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
 )
 
 const (
 	MessageFlagEphemeral = 1 << 6
+
+	historyMaxTurns = 20
+
+	leaderboardColor = 0xE67E22
+	tournamentColor  = 0x9B59B6
 )
 
+var rankMedals = []string{"🥇", "🥈", "🥉"}
+
 type Reply struct {
-	Message   string
-	Embed     *discordgo.MessageEmbed
-	GIF       *GIF
-	Ephemeral bool
+	Message    string
+	Embed      *discordgo.MessageEmbed
+	GIF        *GIF
+	Components []discordgo.MessageComponent
+	Ephemeral  bool
+
+	// Update edits the message that the interaction's component is attached to,
+	// instead of replying with a new message.
+	Update bool
 }
 
 type GIF struct {
@@ -35,12 +51,12 @@ var ExplodeGIFs = []*GIF{
 	{Name: "explode-6.gif", URL: "https://media.giphy.com/media/eFifJQ2SUYxO0/giphy.gif"},
 }
 
-func RandomExplodeGIF() *GIF {
-	i := rand.Intn(len(ExplodeGIFs))
+func RandomExplodeGIF(random hotpotato.Randomizer) *GIF {
+	i := random.Intn(len(ExplodeGIFs))
 	return ExplodeGIFs[i]
 }
 
-func TossSuccessReply(actorUserID, targetUserID string, rsp *hotpotato.TossResponse) *Reply {
+func TossSuccessReply(random hotpotato.Randomizer, actorUserID, targetUserID string, rsp *hotpotato.TossResponse) *Reply {
 	reply := &Reply{}
 
 	var sb strings.Builder
@@ -58,15 +74,75 @@ func TossSuccessReply(actorUserID, targetUserID string, rsp *hotpotato.TossRespo
 		}
 	}
 
+	// The potato is tossed back to whoever tossed it last, which is the target
+	// if they dodged it.
+	sender := actorUserID
+	if rsp.Dodged {
+		sb.WriteString(fmt.Sprintf("\n💨 <@!%s> dodged it and the **%s** bounced straight back to <@!%s>!", targetUserID, rsp.Potato, actorUserID))
+		sender = targetUserID
+	}
+
 	if rsp.Exploded {
-		sb.WriteString(fmt.Sprintf("\nOh no, the **%s** exploded in <@!%s>'s face! 🤢", rsp.Potato, targetUserID))
-		reply.GIF = RandomExplodeGIF()
+		sb.WriteString(fmt.Sprintf("\nOh no, the **%s** exploded in <@!%s>'s face! 🤢", rsp.Potato, rsp.HolderUserID))
+		reply.GIF = RandomExplodeGIF(random)
+	} else {
+		reply.Components = TossComponents(sender, rsp.PotatoID)
 	}
 
+	writeTeamLoss(&sb, rsp.LosingTeam)
+	writeKnockout(&sb, rsp.Tournament)
+	writeElimination(&sb, rsp.Elimination)
+	writeItems(&sb, rsp.Items)
+	writeUnlocks(&sb, rsp.Achievements)
+
 	reply.Message = sb.String()
 	return reply
 }
 
+func TossComponents(actorUserID string, potatoID int) []discordgo.MessageComponent {
+	tossBack := discordgo.Button{
+		Label:    "Toss back",
+		Style:    discordgo.PrimaryButton,
+		Emoji:    discordgo.ComponentEmoji{Name: "🥔"},
+		CustomID: ComponentID("toss", actorUserID, strconv.Itoa(potatoID)),
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: append([]discordgo.MessageComponent{tossBack}, potatoButtons(potatoID)...),
+		},
+	}
+}
+
+// StartComponents are the buttons on a freshly started potato, which has not
+// been tossed by anyone to toss it back to.
+func StartComponents(potatoID int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: potatoButtons(potatoID),
+		},
+	}
+}
+
+func potatoButtons(potatoID int) []discordgo.MessageComponent {
+	potato := strconv.Itoa(potatoID)
+
+	return []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Cook it",
+			Style:    discordgo.DangerButton,
+			Emoji:    discordgo.ComponentEmoji{Name: "🔥"},
+			CustomID: ComponentID("cook", potato),
+		},
+		discordgo.Button{
+			Label:    "Toss to random",
+			Style:    discordgo.SecondaryButton,
+			Emoji:    discordgo.ComponentEmoji{Name: "🎲"},
+			CustomID: ComponentID("toss-random", potato),
+		},
+	}
+}
+
 func TossInvalidTargetReply(targetUserID string) *Reply {
 	return &Reply{
 		Message:   fmt.Sprintf("You can't toss a potato to <@!%s>. Try someone else!", targetUserID),
@@ -74,6 +150,20 @@ func TossInvalidTargetReply(targetUserID string) *Reply {
 	}
 }
 
+func TossRandomNoTargetReply() *Reply {
+	return &Reply{
+		Message:   "There is no one else around to toss the potato to. Try tossing it to someone directly!",
+		Ephemeral: true,
+	}
+}
+
+func TossTargetRequiredReply() *Reply {
+	return &Reply{
+		Message:   "Pick either a user to toss the potato to or `random` to toss it to someone at random, but not both!",
+		Ephemeral: true,
+	}
+}
+
 func TossNotHolderReply(holderUserID string) *Reply {
 	return &Reply{
 		Message:   fmt.Sprintf("You can't toss the potato as <@!%s> is currently holding it!", holderUserID),
@@ -81,7 +171,7 @@ func TossNotHolderReply(holderUserID string) *Reply {
 	}
 }
 
-func StealSuccessReply(actorUserID, targetUserID string, rsp *hotpotato.StealResponse) *Reply {
+func StealSuccessReply(random hotpotato.Randomizer, actorUserID, targetUserID string, rsp *hotpotato.StealResponse) *Reply {
 	reply := &Reply{}
 
 	var sb strings.Builder
@@ -89,9 +179,15 @@ func StealSuccessReply(actorUserID, targetUserID string, rsp *hotpotato.StealRes
 
 	if rsp.Exploded {
 		sb.WriteString(fmt.Sprintf("\nOh no, the **%s** exploded in <@!%s>'s face! 🤢", rsp.Potato, actorUserID))
-		reply.GIF = RandomExplodeGIF()
+		reply.GIF = RandomExplodeGIF(random)
 	}
 
+	writeTeamLoss(&sb, rsp.LosingTeam)
+	writeKnockout(&sb, rsp.Tournament)
+	writeElimination(&sb, rsp.Elimination)
+	writeItems(&sb, rsp.Items)
+	writeUnlocks(&sb, rsp.Achievements)
+
 	reply.Message = sb.String()
 	return reply
 }
@@ -103,6 +199,13 @@ func StealInvalidTargetReply(targetUserID string) *Reply {
 	}
 }
 
+func StealDisabledReply() *Reply {
+	return &Reply{
+		Message:   "Stealing potatoes has been disabled in this server. Wait for the potato to be tossed to you!",
+		Ephemeral: true,
+	}
+}
+
 func StealNotHolderReply(targetUserID, holderUserID string) *Reply {
 	return &Reply{
 		Message:   fmt.Sprintf("You can't steal the potato from <@!%s> as <@!%s> is currently holding it!", targetUserID, holderUserID),
@@ -110,7 +213,7 @@ func StealNotHolderReply(targetUserID, holderUserID string) *Reply {
 	}
 }
 
-func CookSuccessReply(actorUserID string, rsp *hotpotato.CookResponse) *Reply {
+func CookSuccessReply(random hotpotato.Randomizer, actorUserID string, rsp *hotpotato.CookResponse) *Reply {
 	reply := &Reply{}
 
 	var sb strings.Builder
@@ -118,13 +221,26 @@ func CookSuccessReply(actorUserID string, rsp *hotpotato.CookResponse) *Reply {
 
 	if rsp.Exploded {
 		sb.WriteString(fmt.Sprintf("\nOh no, the **%s** exploded in <@!%s>'s face! 🤢", rsp.Potato, actorUserID))
-		reply.GIF = RandomExplodeGIF()
+		reply.GIF = RandomExplodeGIF(random)
 	}
 
+	writeTeamLoss(&sb, rsp.LosingTeam)
+	writeKnockout(&sb, rsp.Tournament)
+	writeElimination(&sb, rsp.Elimination)
+	writeItems(&sb, rsp.Items)
+	writeUnlocks(&sb, rsp.Achievements)
+
 	reply.Message = sb.String()
 	return reply
 }
 
+func CookCapReachedReply() *Reply {
+	return &Reply{
+		Message:   "The potato is already as hot as it can get in this server. Time to toss it!",
+		Ephemeral: true,
+	}
+}
+
 func CookNotHolderReply(holderUserID string) *Reply {
 	return &Reply{
 		Message:   fmt.Sprintf("You can't cook the potato as <@!%s> is currently holding it!", holderUserID),
@@ -133,58 +249,791 @@ func CookNotHolderReply(holderUserID string) *Reply {
 }
 
 func WhereSuccessReply(rsp *hotpotato.GetHolderResponse) *Reply {
+	if len(rsp.Potatoes) == 1 {
+		live := rsp.Potatoes[0]
+		return &Reply{
+			Message: fmt.Sprintf("The **%s** is currently being held by <@!%s>", live.Potato, live.HolderUserID),
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("There are %d potatoes in play:", len(rsp.Potatoes)))
+	for _, live := range rsp.Potatoes {
+		sb.WriteString(fmt.Sprintf("\n`#%d` The **%s** is currently being held by <@!%s>", live.ID, live.Potato, live.HolderUserID))
+	}
+
 	return &Reply{
-		Message: fmt.Sprintf("The **%s** is currently being held by <@!%s>", rsp.Potato, rsp.HolderUserID),
+		Message: sb.String(),
 	}
 }
 
-func LeaderboardSuccessReply(rsp *hotpotato.GetLeaderboardResponse) *Reply {
+func HistorySuccessReply(rsp *hotpotato.GetHistoryResponse) *Reply {
 	var sb strings.Builder
-	sb.WriteString("**🥁 __Deaths by Hot 🔥 Potato 🥔 Leaderboard__ 🥁**")
-	sb.WriteString("\nHere are the top 10 losers who have had the most hot potatoes explode in their faces 🤢")
+	if rsp.Finished {
+		sb.WriteString(fmt.Sprintf("**📜 __History of the last %s__ 📜**", rsp.Potato))
+	} else {
+		sb.WriteString(fmt.Sprintf("**📜 __History of the current %s__ 📜**", rsp.Potato))
+	}
 	sb.WriteString("\n")
 
+	turns := rsp.Turns
+	if len(turns) > historyMaxTurns {
+		sb.WriteString(fmt.Sprintf("\n*...and %d earlier turns*", len(turns)-historyMaxTurns))
+		turns = turns[len(turns)-historyMaxTurns:]
+	}
+
+	for _, turn := range turns {
+		var line string
+		switch turn.Action {
+		case game.ActionToss:
+			line = fmt.Sprintf("<@!%s> tossed it to <@!%s>", turn.ActorUserID, turn.TargetUserID)
+		case game.ActionSteal:
+			line = fmt.Sprintf("<@!%s> stole it from <@!%s>", turn.ActorUserID, turn.TargetUserID)
+		case game.ActionCook:
+			line = fmt.Sprintf("<@!%s> cooked it", turn.ActorUserID)
+		case game.ActionExplode:
+			line = fmt.Sprintf("<@!%s> held on to it for too long", turn.ActorUserID)
+		}
+
+		sb.WriteString(fmt.Sprintf("\n`%d.` %s (heat %d, %d%% chance)", turn.Turn, line, turn.HeatLevel, turn.ExplodeChance))
+		if turn.Exploded {
+			sb.WriteString(" 💥")
+		}
+	}
+
+	exploded := len(rsp.Turns) > 0 && rsp.Turns[len(rsp.Turns)-1].Exploded
+	switch {
+	case !rsp.Finished:
+		sb.WriteString(fmt.Sprintf("\nThe **%s** is currently being held by <@!%s>", rsp.Potato, rsp.HolderUserID))
+	case !exploded:
+		sb.WriteString(fmt.Sprintf("\nThe **%s** was retired from play while <@!%s> was holding it", rsp.Potato, rsp.HolderUserID))
+	}
+
+	return &Reply{
+		Message: sb.String(),
+	}
+}
+
+func NoGameHistoryReply() *Reply {
+	return &Reply{
+		Message:   "No potatoes have been tossed in this channel yet. Start a game by tossing a potato!",
+		Ephemeral: true,
+	}
+}
+
+// MetricLabels are the human-readable names of the leaderboard metrics.
+var MetricLabels = map[hotpotato.Metric]string{
+	hotpotato.MetricDeaths:            "deaths",
+	hotpotato.MetricGamesPlayed:       "games played",
+	hotpotato.MetricTosses:            "tosses",
+	hotpotato.MetricSteals:            "steals",
+	hotpotato.MetricCooks:             "cooks",
+	hotpotato.MetricSurvivalRate:      "survival rate",
+	hotpotato.MetricLongestHoldStreak: "longest hold streak",
+	hotpotato.MetricKills:             "kills",
+}
+
+func LeaderboardSuccessReply(rsp *hotpotato.GetLeaderboardResponse, names map[string]string) *Reply {
+	embed := &discordgo.MessageEmbed{
+		Title:  "🥁 Hot 🔥 Potato 🥔 Leaderboard 🥁",
+		Color:  leaderboardColor,
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Season %d • Page %d of %d", rsp.Season, rsp.Page+1, rsp.Pages)},
+	}
+
+	switch {
+	case rsp.Metric == hotpotato.MetricDeaths && rsp.Ended:
+		embed.Title = "🥁 Deaths by Hot 🔥 Potato 🥔 Leaderboard 🥁"
+		embed.Description = fmt.Sprintf("Here are the final standings for season %d, with the top losers who had the most hot potatoes explode in their faces 🤢", rsp.Season)
+	case rsp.Metric == hotpotato.MetricDeaths:
+		embed.Title = "🥁 Deaths by Hot 🔥 Potato 🥔 Leaderboard 🥁"
+		embed.Description = fmt.Sprintf("Here are the top losers of season %d who have had the most hot potatoes explode in their faces 🤢", rsp.Season)
+	case rsp.Ended:
+		embed.Description = fmt.Sprintf("Here are the final standings for season %d, ranked by %s 🏆", rsp.Season, MetricLabels[rsp.Metric])
+	default:
+		embed.Description = fmt.Sprintf("Here are the top players of season %d, ranked by %s 🏆", rsp.Season, MetricLabels[rsp.Metric])
+	}
+
 	if len(rsp.Leaderboard) == 0 {
-		sb.WriteString("\n*😇 It seems like no one has died yet, time to start tossing some potatoes! 🔥🥔*")
+		if rsp.Metric == hotpotato.MetricDeaths {
+			embed.Description += "\n\n*😇 It seems like no one has died yet, time to start tossing some potatoes! 🔥🥔*"
+		} else {
+			embed.Description += "\n\n*😴 It seems like no games have finished yet, time to start tossing some potatoes! 🔥🥔*"
+		}
 		return &Reply{
-			Message: sb.String(),
+			Embed: embed,
 		}
 	}
 
 	for i, entry := range rsp.Leaderboard {
-		ranking := i + 1
-
-		var prefix string
-		switch ranking {
-		case 1:
-			prefix = "🥇"
-		case 2:
-			prefix = "🥈"
-		case 3:
-			prefix = "🥉"
-		case 4:
-			prefix = "4️⃣"
-		case 5:
-			prefix = "5️⃣"
-		case 6:
-			prefix = "6️⃣"
-		case 7:
-			prefix = "7️⃣"
-		case 8:
-			prefix = "8️⃣"
-		case 9:
-			prefix = "9️⃣"
-		case 10:
-			prefix = "🔟"
+		name := rankPrefix(rsp.Offset + i + 1)
+		if displayName, ok := names[entry.UserID]; ok {
+			name = fmt.Sprintf("%s %s", name, displayName)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: fmt.Sprintf("<@!%s> - %s", entry.UserID, formatScore(rsp.Metric, entry.Value)),
+		})
+	}
+
+	reply := &Reply{
+		Embed: embed,
+	}
+
+	if rsp.Pages > 1 {
+		reply.Components = LeaderboardComponents(rsp)
+	}
+
+	return reply
+}
+
+func LeaderboardComponents(rsp *hotpotato.GetLeaderboardResponse) []discordgo.MessageComponent {
+	page := func(page int) string {
+		return ComponentID("leaderboard", strconv.Itoa(rsp.Season), string(rsp.Metric), strconv.Itoa(page))
+	}
+
+	previous, next := rsp.Page-1, rsp.Page+1
+	if previous < 0 {
+		previous = 0
+	}
+	if next >= rsp.Pages {
+		next = rsp.Pages - 1
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					Emoji:    discordgo.ComponentEmoji{Name: "⬅️"},
+					CustomID: page(previous),
+					Disabled: rsp.Page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					Emoji:    discordgo.ComponentEmoji{Name: "➡️"},
+					CustomID: page(next),
+					Disabled: rsp.Page == rsp.Pages-1,
+				},
+			},
+		},
+	}
+}
+
+func ProfileSuccessReply(userID string, rsp *hotpotato.GetProfileResponse, names map[string]string) *Reply {
+	title := "🥔 Hot Potato Record 🥔"
+	if name, ok := names[userID]; ok {
+		title = fmt.Sprintf("🥔 Hot Potato Record of %s 🥔", name)
+	}
+
+	rank := "unranked"
+	if rsp.Rank > 0 {
+		rank = fmt.Sprintf("#%d", rsp.Rank)
+	}
+
+	favouriteTarget := "no one yet"
+	if rsp.FavouriteTargetUserID != "" {
+		favouriteTarget = fmt.Sprintf("<@!%s> (%d tosses)", rsp.FavouriteTargetUserID, rsp.FavouriteTargetTosses)
+	}
+
+	nemesis := "no one yet"
+	if rsp.NemesisUserID != "" {
+		nemesis = fmt.Sprintf("<@!%s> (%d kills)", rsp.NemesisUserID, rsp.NemesisKills)
+	}
+
+	items := "none"
+	if len(rsp.Items) > 0 {
+		held := make([]string, len(rsp.Items))
+		for i, h := range rsp.Items {
+			held[i] = fmt.Sprintf("%s x%d", h.Item, h.Count)
+		}
+		items = strings.Join(held, ", ")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("Here's how <@!%s> has fared with hot potatoes in this server 🔥", userID),
+		Color:       leaderboardColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: fmt.Sprintf("Deaths in season %d", rsp.Season), Value: strconv.Itoa(rsp.Deaths), Inline: true},
+			{Name: "Rank", Value: rank, Inline: true},
+			{Name: "Games played", Value: strconv.Itoa(rsp.GamesPlayed), Inline: true},
+			{Name: "Favourite target", Value: favouriteTarget, Inline: true},
+			{Name: "Nemesis", Value: nemesis, Inline: true},
+			{Name: "Elimination wins", Value: strconv.Itoa(rsp.Wins), Inline: true},
+			{Name: "Items", Value: items, Inline: true},
+		},
+	}
+
+	return &Reply{
+		Embed: embed,
+	}
+}
+
+func AchievementsSuccessReply(userID string, rsp *hotpotato.ListAchievementsResponse, names map[string]string) *Reply {
+	title := "🏅 Hot Potato Achievements 🏅"
+	if name, ok := names[userID]; ok {
+		title = fmt.Sprintf("🏅 Hot Potato Achievements of %s 🏅", name)
+	}
+
+	var unlocked int
+	fields := make([]*discordgo.MessageEmbedField, len(rsp.Achievements))
+	for i, progress := range rsp.Achievements {
+		status := "🔒 Locked"
+		if progress.Unlocked {
+			unlocked++
+			status = fmt.Sprintf("✅ Unlocked <t:%d:R>", progress.UnlockedAt.Unix())
+		}
+
+		fields[i] = &discordgo.MessageEmbedField{
+			Name:  progress.Achievement.Name,
+			Value: fmt.Sprintf("%s\n%s", progress.Achievement.Description, status),
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("<@!%s> has unlocked %d of %d achievements in this server", userID, unlocked, len(rsp.Achievements)),
+		Color:       leaderboardColor,
+		Fields:      fields,
+	}
+
+	return &Reply{
+		Embed: embed,
+	}
+}
+
+func TeamGameStartedReply(rsp *hotpotato.StartTeamGameResponse) *Reply {
+	teams := make([]string, 0, len(rsp.Teams))
+	for team := range rsp.Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	var sb strings.Builder
+	sb.WriteString("**⚔️ __A team game has started!__ ⚔️**")
+	sb.WriteString("\n")
+	for _, team := range teams {
+		players := make([]string, len(rsp.Teams[team]))
+		for i, userID := range rsp.Teams[team] {
+			players[i] = fmt.Sprintf("<@!%s>", userID)
+		}
+		sb.WriteString(fmt.Sprintf("\n**Team %s:** %s", team, strings.Join(players, ", ")))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("\n<@!%s> grabbed a **%s** fresh out of the oven. Toss it to the other team, the team it explodes on loses! 🔥", rsp.HolderUserID, rsp.Potato))
+
+	return &Reply{
+		Message:    sb.String(),
+		Components: StartComponents(rsp.PotatoID),
+	}
+}
+
+func TeamGameInProgressReply() *Reply {
+	return &Reply{
+		Message:   "There's already a potato being tossed around in this channel. Wait for it to explode before starting a team game!",
+		Ephemeral: true,
+	}
+}
+
+func NotEnoughPlayersReply() *Reply {
+	return &Reply{
+		Message:   "There aren't enough players to form teams. Play a game first or pick roles that have members!",
+		Ephemeral: true,
+	}
+}
+
+func NotOnTeamReply(userID string) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("<@!%s> isn't on a team in this game. Only players on a team can hold the potato!", userID),
+		Ephemeral: true,
+	}
+}
+
+func TossTeammateReply(targetUserID string) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("You can't toss the potato to <@!%s>, they're on your team. Toss it to the other team!", targetUserID),
+		Ephemeral: true,
+	}
+}
+
+func OptedInReply(userID string, rsp *hotpotato.OptInResponse) *Reply {
+	if !rsp.Added {
+		return &Reply{
+			Message:   "You've already opted in. Potatoes can be tossed your way!",
+			Ephemeral: true,
+		}
+	}
+
+	return &Reply{
+		Message: fmt.Sprintf("<@!%s> has opted in to play! Toss them a potato 🥔", userID),
+	}
+}
+
+func OptedOutReply(userID string, rsp *hotpotato.OptOutResponse) *Reply {
+	if !rsp.Removed {
+		return &Reply{
+			Message:   "You haven't opted in, so no potatoes can be tossed your way.",
+			Ephemeral: true,
+		}
+	}
+
+	return &Reply{
+		Message: fmt.Sprintf("<@!%s> has opted out and won't be tossed any more potatoes 👋", userID),
+	}
+}
+
+func NotOptedInReply(userID string) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("<@!%s> hasn't opted in to play. Only players who have used `/hotpotato optin` can be tossed the potato!", userID),
+		Ephemeral: true,
+	}
+}
+
+func ItemArmedReply(rsp *hotpotato.UseItemResponse) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("You've readied your **%s**. %s! You have %d left.", rsp.Item, rsp.Item.Description, rsp.Remaining),
+		Ephemeral: true,
+	}
+}
+
+func ItemNotHeldReply() *Reply {
+	return &Reply{
+		Message:   "You don't have one of those. Survive more turns to find items!",
+		Ephemeral: true,
+	}
+}
+
+func ItemAlreadyArmedReply() *Reply {
+	return &Reply{
+		Message:   "You've already readied one of those. Wait for it to be used up before readying another!",
+		Ephemeral: true,
+	}
+}
+
+func LobbyJoinedReply(userID string, rsp *hotpotato.JoinLobbyResponse) *Reply {
+	players := make([]string, len(rsp.Players))
+	for i, player := range rsp.Players {
+		players[i] = fmt.Sprintf("<@!%s>", player)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<@!%s> is in for the next elimination game! 🙋", userID))
+	sb.WriteString(fmt.Sprintf("\n**Lobby (%d):** %s", len(players), strings.Join(players, ", ")))
+	sb.WriteString("\nStart the game with `/hotpotato elimination` once everyone has joined.")
+
+	return &Reply{
+		Message: sb.String(),
+	}
+}
+
+func EliminationStartedReply(rsp *hotpotato.StartEliminationResponse) *Reply {
+	players := make([]string, len(rsp.Players))
+	for i, userID := range rsp.Players {
+		players[i] = fmt.Sprintf("<@!%s>", userID)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**💀 __An elimination game has started!__ 💀**")
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("\n**Players:** %s", strings.Join(players, ", ")))
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("\n<@!%s> grabbed a **%s** fresh out of the oven. Whoever it explodes on is out, and the last one standing wins! 🔥", rsp.HolderUserID, rsp.Potato))
+
+	return &Reply{
+		Message: sb.String(),
+	}
+}
+
+func EliminationInProgressReply() *Reply {
+	return &Reply{
+		Message:   "There's already a potato being tossed around in this channel. Wait for it to explode before starting an elimination game!",
+		Ephemeral: true,
+	}
+}
+
+func LobbyTooSmallReply() *Reply {
+	return &Reply{
+		Message:   "There aren't enough players in the lobby yet. Get at least two people to `/hotpotato join` first!",
+		Ephemeral: true,
+	}
+}
+
+func NotPlayingReply(userID string) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("<@!%s> isn't left standing in this elimination game. Only the players still in it can hold the potato!", userID),
+		Ephemeral: true,
+	}
+}
+
+func TeamLeaderboardSuccessReply(rsp *hotpotato.GetTeamLeaderboardResponse) *Reply {
+	embed := &discordgo.MessageEmbed{
+		Title:       "⚔️ Team Hot 🔥 Potato 🥔 Leaderboard ⚔️",
+		Description: "Here are the teams that have had the most hot potatoes explode on them 🤢",
+		Color:       leaderboardColor,
+	}
+
+	if len(rsp.Leaderboard) == 0 {
+		embed.Description += "\n\n*😇 It seems like no team has lost yet, time to start a team game! 🔥🥔*"
+	}
+
+	for i, entry := range rsp.Leaderboard {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s Team %s", rankPrefix(i+1), entry.Team),
+			Value: fmt.Sprintf("%d losses", entry.Count),
+		})
+	}
+
+	return &Reply{
+		Embed: embed,
+	}
+}
+
+func TournamentRegisteredReply(rsp *hotpotato.RegisterTournamentPlayersResponse) *Reply {
+	return &Reply{
+		Message: fmt.Sprintf("Registered %d new players for tournament #%d, which has %d players so far. Start it with `/hotpotato tournament start` once everyone's in! 🏆", rsp.Registered, rsp.Tournament.Number, len(rsp.Tournament.Players)),
+	}
+}
+
+func TournamentBracketReply(t *room.Tournament) *Reply {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🏆 Hot 🔥 Potato 🥔 Tournament #%d 🏆", t.Number),
+		Color: tournamentColor,
+	}
+
+	switch {
+	case t.WinnerUserID != "":
+		embed.Description = fmt.Sprintf("<@!%s> survived every heat and won the tournament! 🎉", t.WinnerUserID)
+	case t.Ended():
+		embed.Description = "This tournament was cancelled before it could be won."
+	case !t.Started():
+		players := make([]string, len(t.Players))
+		for i, userID := range t.Players {
+			players[i] = fmt.Sprintf("<@!%s>", userID)
+		}
+		embed.Description = fmt.Sprintf("Registration is open, %d players are in: %s", len(t.Players), strings.Join(players, ", "))
+	default:
+		embed.Description = fmt.Sprintf("Round %d is being played. The player the potato explodes on in each heat is knocked out! 💥", t.Round)
+	}
+
+	for round := 1; round <= t.Round; round++ {
+		var sb strings.Builder
+		for _, heat := range t.RoundHeats(round) {
+			players := make([]string, len(heat.Players))
+			for i, userID := range heat.Players {
+				players[i] = fmt.Sprintf("<@!%s>", userID)
+			}
+			sb.WriteString(fmt.Sprintf("\n**Heat %d:** %s", heat.Number, strings.Join(players, " vs ")))
+
+			switch {
+			case heat.Finished():
+				sb.WriteString(fmt.Sprintf(" - <@!%s> knocked out 💀", heat.LoserUserID))
+			case heat.ChannelID != "":
+				sb.WriteString(fmt.Sprintf(" - playing in <#%s>", heat.ChannelID))
+			}
+		}
+
+		for _, userID := range t.Byes(round) {
+			sb.WriteString(fmt.Sprintf("\n**Bye:** <@!%s>", userID))
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("Round %d", round),
+			Value: strings.TrimPrefix(sb.String(), "\n"),
+		})
+	}
+
+	return &Reply{
+		Embed: embed,
+	}
+}
+
+func TournamentUpdateReply(update *hotpotato.TournamentUpdate) *Reply {
+	var sb strings.Builder
+	if update.KnockedOutUserID != "" {
+		sb.WriteString(fmt.Sprintf("💥 <@!%s> has been knocked out of tournament #%d!", update.KnockedOutUserID, update.Tournament.Number))
+	}
+
+	switch {
+	case update.Tournament.WinnerUserID != "":
+		sb.WriteString(fmt.Sprintf("\n🏆 <@!%s> is the last one standing and wins tournament #%d! 🎉", update.Tournament.WinnerUserID, update.Tournament.Number))
+	case len(update.Heats) > 0:
+		sb.WriteString(fmt.Sprintf("\nRound %d is starting, good luck to everyone still in! 🔥", update.Tournament.Round))
+	}
+
+	reply := TournamentBracketReply(update.Tournament)
+	reply.Message = strings.TrimPrefix(sb.String(), "\n")
+	return reply
+}
+
+func HeatStartedReply(t *room.Tournament, rsp *hotpotato.OpenHeatResponse) *Reply {
+	players := make([]string, len(rsp.Heat.Players))
+	for i, userID := range rsp.Heat.Players {
+		players[i] = fmt.Sprintf("<@!%s>", userID)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**🏆 __Tournament #%d, round %d, heat %d__ 🏆**", t.Number, rsp.Heat.Round, rsp.Heat.Number))
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("\n%s, only you can play in this heat.", strings.Join(players, ", ")))
+	sb.WriteString(fmt.Sprintf("\n<@!%s> grabbed a **%s** fresh out of the oven. Whoever it explodes on is knocked out! 🔥", rsp.HolderUserID, rsp.Potato))
+
+	return &Reply{
+		Message:    sb.String(),
+		Components: StartComponents(rsp.PotatoID),
+	}
+}
+
+func HeatClosingReply(t *room.Tournament, heat *room.Heat, retention time.Duration) *Reply {
+	return &Reply{
+		Message: fmt.Sprintf("Heat %d of round %d is over! This channel will be deleted in %s, head back to <#%s> to follow the rest of tournament #%d.", heat.Number, heat.Round, retention, t.ChannelID, t.Number),
+	}
+}
+
+func TournamentCancelledReply(t *room.Tournament) *Reply {
+	return &Reply{
+		Message: fmt.Sprintf("Tournament #%d has been cancelled. Any potatoes still in play in its heats can be finished for fun!", t.Number),
+	}
+}
+
+func TournamentForbiddenReply() *Reply {
+	return &Reply{
+		Message:   "You need the Manage Server permission to run Hot Potato tournaments.",
+		Ephemeral: true,
+	}
+}
+
+func TournamentNoEntrantsReply() *Reply {
+	return &Reply{
+		Message:   "There's no one to register. Pick a user or a role that has members!",
+		Ephemeral: true,
+	}
+}
+
+func NoTournamentReply() *Reply {
+	return &Reply{
+		Message:   "There isn't a tournament in this server. Set one up by registering players with `/hotpotato tournament register`!",
+		Ephemeral: true,
+	}
+}
+
+func TournamentAlreadyStartedReply() *Reply {
+	return &Reply{
+		Message:   "The tournament has already started. Wait for it to end before setting up a new one!",
+		Ephemeral: true,
+	}
+}
+
+func NotEnoughEntrantsReply() *Reply {
+	return &Reply{
+		Message:   "There aren't enough players registered to start the tournament. Register at least two!",
+		Ephemeral: true,
+	}
+}
+
+func NotInHeatReply(userID string) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("<@!%s> isn't playing in this tournament heat. Only the players drawn into it can hold the potato!", userID),
+		Ephemeral: true,
+	}
+}
+
+func SeasonEndedReply(rsp *hotpotato.EndSeasonResponse) *Reply {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**🏁 __Season %d has ended!__ 🏁**", rsp.Season))
+
+	if len(rsp.Leaderboard) == 0 {
+		sb.WriteString("\nNo one died this season, what a peaceful time it was 😇")
+	} else {
+		sb.WriteString("\nHere are the final standings of the unluckiest potato holders 🤢")
+		sb.WriteString("\n")
+		writeStandings(&sb, hotpotato.MetricDeaths, rsp.Leaderboard)
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("\nSeason %d starts now, everyone's deaths have been reset. Time to start tossing some potatoes! 🔥🥔", rsp.NextSeason))
+
+	return &Reply{
+		Message: sb.String(),
+	}
+}
+
+func SeasonNotFoundReply(season int) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("Season %d doesn't exist in this server yet.", season),
+		Ephemeral: true,
+	}
+}
+
+func SeasonAlreadyEndedReply() *Reply {
+	return &Reply{
+		Message:   "That season has already ended. Try again to end the new one!",
+		Ephemeral: true,
+	}
+}
+
+func writeTeamLoss(sb *strings.Builder, team string) {
+	if team != "" {
+		sb.WriteString(fmt.Sprintf("\nTeam **%s** loses this round! 💀", team))
+	}
+}
+
+func writeKnockout(sb *strings.Builder, update *hotpotato.TournamentUpdate) {
+	if update != nil && update.KnockedOutUserID != "" {
+		sb.WriteString(fmt.Sprintf("\n<@!%s> is knocked out of the tournament! 🏳️", update.KnockedOutUserID))
+	}
+}
+
+func writeElimination(sb *strings.Builder, update *hotpotato.EliminationUpdate) {
+	if update == nil {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("\n💀 <@!%s> has been eliminated!", update.EliminatedUserID))
+	switch {
+	case update.WinnerUserID != "":
+		sb.WriteString(fmt.Sprintf("\n👑 <@!%s> is the last one standing and wins the game! 🎉", update.WinnerUserID))
+	case update.HolderUserID != "":
+		sb.WriteString(fmt.Sprintf("\n%d players are left standing. <@!%s> grabbed a fresh potato out of the oven, keep it moving! 🔥", len(update.Players), update.HolderUserID))
+	}
+}
+
+func writeItems(sb *strings.Builder, update *hotpotato.ItemUpdate) {
+	if update == nil {
+		return
+	}
+
+	for _, item := range update.Used {
+		switch item.ID {
+		case hotpotato.ItemShield:
+			sb.WriteString(fmt.Sprintf("\n%s <@!%s>'s shield absorbed the explosion!", item.Emoji, update.UserID))
+		case hotpotato.ItemOvenMitt:
+			sb.WriteString(fmt.Sprintf("\n%s <@!%s> caught the potato with an oven mitt, halving its odds of exploding!", item.Emoji, update.UserID))
 		default:
-			prefix = "❗️"
+			sb.WriteString(fmt.Sprintf("\n<@!%s> used up their **%s**!", update.UserID, item))
 		}
+	}
+
+	if update.Earned != nil {
+		sb.WriteString(fmt.Sprintf("\n🎁 <@!%s> found a **%s**! Use it with `/hotpotato use`.", update.UserID, update.Earned))
+	}
+}
+
+func writeUnlocks(sb *strings.Builder, unlocks []hotpotato.Unlock) {
+	for _, unlock := range unlocks {
+		sb.WriteString(fmt.Sprintf("\n🏅 <@!%s> unlocked **%s**: %s!", unlock.UserID, unlock.Achievement.Name, unlock.Achievement.Description))
+	}
+}
+
+func writeStandings(sb *strings.Builder, metric hotpotato.Metric, leaderboard hotpotato.Scoreboard) {
+	for i, entry := range leaderboard {
+		sb.WriteString(fmt.Sprintf("\n%s <@!%s> - %s", rankPrefix(i+1), entry.UserID, formatScore(metric, entry.Value)))
+	}
+}
+
+func rankPrefix(rank int) string {
+	if rank <= len(rankMedals) {
+		return rankMedals[rank-1]
+	}
+	return fmt.Sprintf("#%d", rank)
+}
+
+func formatScore(metric hotpotato.Metric, value float64) string {
+	switch metric {
+	case hotpotato.MetricSurvivalRate:
+		return fmt.Sprintf("%.0f%% survival rate", value)
+	case hotpotato.MetricLongestHoldStreak:
+		return fmt.Sprintf("%.0f turns held in a row", value)
+	default:
+		return fmt.Sprintf("%.0f %s", value, MetricLabels[metric])
+	}
+}
+
+func ConfigSuccessReply(settings *room.Settings) *Reply {
+	potatoes := "all"
+	if len(settings.AllowedPotatoKinds) > 0 {
+		potatoes = strings.Join(settings.AllowedPotatoKinds, ", ")
+	}
+
+	steal := "enabled"
+	if !settings.StealEnabled {
+		steal = "disabled"
+	}
+
+	cookCap := "none"
+	if settings.CookCap > 0 {
+		cookCap = fmt.Sprintf("heat %d", settings.CookCap)
+	}
+
+	fuseTimeout := "default"
+	if settings.FuseTimeout > 0 {
+		fuseTimeout = settings.FuseTimeout.String()
+	}
 
-		sb.WriteString(fmt.Sprintf("\n%s <@!%s> - %d deaths", prefix, entry.UserID, entry.Count))
+	var sb strings.Builder
+	sb.WriteString("**⚙️ __Hot Potato Settings__ ⚙️**")
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("\n**Potatoes:** %s", potatoes))
+	sb.WriteString(fmt.Sprintf("\n**Explode multiplier:** %gx", settings.ExplodeMultiplier))
+	sb.WriteString(fmt.Sprintf("\n**Stealing:** %s", steal))
+	sb.WriteString(fmt.Sprintf("\n**Cook cap:** %s", cookCap))
+	sb.WriteString(fmt.Sprintf("\n**Fuse timeout:** %s", fuseTimeout))
+	sb.WriteString(fmt.Sprintf("\n**Leaderboard size:** %d", settings.LeaderboardSize))
+	sb.WriteString(fmt.Sprintf("\n**Potato limit:** %d", settings.PotatoLimit))
+
+	return &Reply{
+		Message:   sb.String(),
+		Ephemeral: true,
+	}
+}
+
+func ConfigForbiddenReply() *Reply {
+	return &Reply{
+		Message:   "You need the Manage Server permission to configure Hot Potato Bot.",
+		Ephemeral: true,
+	}
+}
+
+func ConfigInvalidReply(reason string) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("Those settings aren't valid: %s.", reason),
+		Ephemeral: true,
 	}
+}
+
+func ConfigInvalidPotatoKindReply() *Reply {
+	return &Reply{
+		Message:   "One of the potato kinds you gave isn't recognised. Try again with kinds that exist!",
+		Ephemeral: true,
+	}
+}
+
+func FuseDetonatedReply(random hotpotato.Randomizer, d *hotpotato.Detonation) *Reply {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<@!%s> held on to the **%s** for too long and it exploded in their face! 🤢", d.HolderUserID, d.Potato))
+	writeTeamLoss(&sb, d.LosingTeam)
+	writeKnockout(&sb, d.Tournament)
+	writeElimination(&sb, d.Elimination)
 
 	return &Reply{
 		Message: sb.String(),
+		GIF:     RandomExplodeGIF(random),
+	}
+}
+
+func CooldownReply(retryAfter time.Duration) *Reply {
+	return &Reply{
+		Message:   fmt.Sprintf("Slow down! You can try that again in %s ⏳", (retryAfter + time.Second - 1).Truncate(time.Second)),
+		Ephemeral: true,
+	}
+}
+
+func PotatoNotFoundReply() *Reply {
+	return &Reply{
+		Message:   "That potato isn't in play in this channel. Check which potatoes are with `/hotpotato where`!",
+		Ephemeral: true,
 	}
 }
 
@@ -210,19 +1059,57 @@ func (b *Bot) reply(s *discordgo.Session, i *discordgo.InteractionCreate, reply
 		},
 	}
 
+	if reply.Update {
+		ir.Type = discordgo.InteractionResponseUpdateMessage
+	}
+
+	if reply.Embed != nil {
+		ir.Data.Embeds = append(ir.Data.Embeds, reply.Embed)
+	}
+
 	if reply.GIF != nil {
 		ir.Data.Embeds = append(ir.Data.Embeds, &discordgo.MessageEmbed{
 			Image: &discordgo.MessageEmbedImage{URL: reply.GIF.URL},
 		})
 	}
 
+	if len(reply.Components) > 0 {
+		ir.Data.Components = reply.Components
+	}
+
 	if reply.Ephemeral {
 		ir.Data.Flags = MessageFlagEphemeral
 	}
 
-	if err := s.InteractionRespond(i.Interaction, ir); err != nil {
+	if err := b.respond(s, i, ir); err != nil {
 		return fmt.Errorf("error responding to interaction: %w", err)
 	}
 
 	return nil
 }
+
+func (b *Bot) send(s *discordgo.Session, channelID string, reply *Reply) error {
+	msg := &discordgo.MessageSend{
+		Content: reply.Message,
+	}
+
+	if reply.Embed != nil {
+		msg.Embeds = append(msg.Embeds, reply.Embed)
+	}
+
+	if reply.GIF != nil {
+		msg.Embeds = append(msg.Embeds, &discordgo.MessageEmbed{
+			Image: &discordgo.MessageEmbedImage{URL: reply.GIF.URL},
+		})
+	}
+
+	if len(reply.Components) > 0 {
+		msg.Components = reply.Components
+	}
+
+	if _, err := s.ChannelMessageSendComplex(channelID, msg); err != nil {
+		return fmt.Errorf("error sending message: %w", err)
+	}
+
+	return nil
+}
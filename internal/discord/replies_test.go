@@ -0,0 +1,102 @@
+package discord
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+func TestHistorySuccessReply(t *testing.T) {
+	potato, err := hotpotato.DefaultPotatoRegistry().Get("hot")
+	if err != nil {
+		t.Fatalf("failed to get potato: %v", err)
+	}
+
+	tossed := &game.Turn{Turn: 1, Action: game.ActionToss, ActorUserID: "a", TargetUserID: "b", HeatLevel: 1, ExplodeChance: 5}
+	exploded := &game.Turn{Turn: 2, Action: game.ActionExplode, ActorUserID: "b", HeatLevel: 1, ExplodeChance: 100, Exploded: true}
+
+	tests := []struct {
+		name     string
+		rsp      *hotpotato.GetHistoryResponse
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "ongoing",
+			rsp:      &hotpotato.GetHistoryResponse{Potato: potato, HolderUserID: "b", Turns: []*game.Turn{tossed}},
+			contains: []string{"current", "<@!a> tossed it to <@!b>", "currently being held by <@!b>"},
+			excludes: []string{"💥", "retired"},
+		},
+		{
+			name:     "exploded",
+			rsp:      &hotpotato.GetHistoryResponse{Potato: potato, HolderUserID: "b", Finished: true, Turns: []*game.Turn{tossed, exploded}},
+			contains: []string{"last", "<@!b> held on to it for too long (heat 1, 100% chance) 💥"},
+			excludes: []string{"currently being held", "retired"},
+		},
+		{
+			name:     "retired",
+			rsp:      &hotpotato.GetHistoryResponse{Potato: potato, HolderUserID: "b", Finished: true, Turns: []*game.Turn{tossed}},
+			contains: []string{"last", "retired from play while <@!b> was holding it"},
+			excludes: []string{"💥", "currently being held"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := HistorySuccessReply(tt.rsp).Message
+			for _, s := range tt.contains {
+				if !strings.Contains(message, s) {
+					t.Errorf("reply does not contain %q:\n%s", s, message)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(message, s) {
+					t.Errorf("reply contains %q:\n%s", s, message)
+				}
+			}
+		})
+	}
+}
+
+func TestStartedReplyComponents(t *testing.T) {
+	potato, err := hotpotato.DefaultPotatoRegistry().Get("hot")
+	if err != nil {
+		t.Fatalf("failed to get potato: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		reply *Reply
+	}{
+		{
+			name:  "team game",
+			reply: TeamGameStartedReply(&hotpotato.StartTeamGameResponse{PotatoID: 3, Potato: potato, HolderUserID: "holder", Teams: map[string][]string{"red": {"holder"}, "blue": {"other"}}}),
+		},
+		{
+			name:  "heat",
+			reply: HeatStartedReply(&room.Tournament{Number: 1}, &hotpotato.OpenHeatResponse{Heat: &room.Heat{Round: 1, Number: 1, Players: []string{"holder", "other"}}, PotatoID: 3, Potato: potato, HolderUserID: "holder"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, row := range tt.reply.Components {
+				for _, c := range row.(discordgo.ActionsRow).Components {
+					ids = append(ids, c.(discordgo.Button).CustomID)
+				}
+			}
+
+			want := []string{ComponentID("cook", "3"), ComponentID("toss-random", "3")}
+			if !reflect.DeepEqual(ids, want) {
+				t.Errorf("got buttons %v, want %v", ids, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/log/level"
+)
+
+// syncCommands registers the desired application commands with Discord, either
+// in the configured guild or globally, overwriting the commands already
+// registered only when they differ.
+func (b *Bot) syncCommands(desired []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error) {
+	appID := b.discord.State.User.ID
+
+	existing, err := b.discord.ApplicationCommands(appID, b.guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing application commands: %w", err)
+	}
+
+	if commandsEqual(desired, existing) {
+		level.Info(b.logger).Log("event", "commands.sync.skipped", "guild", b.guildID, "commands", len(existing))
+		return existing, nil
+	}
+
+	registered, err := b.discord.ApplicationCommandBulkOverwrite(appID, b.guildID, desired)
+	if err != nil {
+		return nil, fmt.Errorf("error overwriting application commands: %w", err)
+	}
+	level.Info(b.logger).Log("event", "commands.sync.overwritten", "guild", b.guildID, "commands", len(registered))
+
+	return registered, nil
+}
+
+// removeCommands deletes the application commands registered by the bot.
+func (b *Bot) removeCommands(commands []*discordgo.ApplicationCommand) error {
+	appID := b.discord.State.User.ID
+
+	for _, cmd := range commands {
+		if err := b.discord.ApplicationCommandDelete(appID, b.guildID, cmd.ID); err != nil {
+			return fmt.Errorf("error deleting application command %s: %w", cmd.Name, err)
+		}
+	}
+	level.Info(b.logger).Log("event", "commands.removed", "guild", b.guildID, "commands", len(commands))
+
+	return nil
+}
+
+// commandsEqual reports whether the desired commands match the ones already
+// registered, comparing everything that is sent to Discord when they are
+// overwritten.
+func commandsEqual(desired, existing []*discordgo.ApplicationCommand) bool {
+	if len(desired) != len(existing) {
+		return false
+	}
+
+	byName := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		byName[cmd.Name] = cmd
+	}
+
+	for _, want := range desired {
+		got, ok := byName[want.Name]
+		if !ok {
+			return false
+		}
+
+		wantJSON, err := commandJSON(want)
+		if err != nil {
+			return false
+		}
+
+		gotJSON, err := commandJSON(got)
+		if err != nil || !bytes.Equal(wantJSON, gotJSON) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// commandJSON marshals the command as it is sent to Discord, leaving out the
+// fields that Discord assigns once it has been registered and the defaults
+// that it fills in.
+func commandJSON(cmd *discordgo.ApplicationCommand) ([]byte, error) {
+	sent := *cmd
+	sent.ID = ""
+	sent.ApplicationID = ""
+	sent.Version = ""
+	if sent.Type == 0 {
+		sent.Type = discordgo.ChatApplicationCommand
+	}
+	sent.Options = normalizeOptions(cmd.Options)
+
+	return json.Marshal(&sent)
+}
+
+func normalizeOptions(options []*discordgo.ApplicationCommandOption) []*discordgo.ApplicationCommandOption {
+	if len(options) == 0 {
+		return nil
+	}
+
+	normalized := make([]*discordgo.ApplicationCommandOption, len(options))
+	for i, option := range options {
+		opt := *option
+		if len(opt.ChannelTypes) == 0 {
+			opt.ChannelTypes = nil
+		}
+		if len(opt.Choices) == 0 {
+			opt.Choices = nil
+		}
+		opt.Options = normalizeOptions(option.Options)
+		normalized[i] = &opt
+	}
+
+	return normalized
+}
@@ -0,0 +1,100 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/kit/log"
+)
+
+// registered returns the command as Discord hands it back once registered,
+// decoded from JSON with the fields that Discord assigns filled in.
+func registered(t *testing.T, cmd *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		t.Fatalf("failed to marshal command: %v", err)
+	}
+
+	var got discordgo.ApplicationCommand
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal command: %v", err)
+	}
+	got.ID = "1234"
+	got.ApplicationID = "5678"
+	got.Version = "1"
+
+	return &got
+}
+
+func TestCommandsEqual(t *testing.T) {
+	b := &Bot{logger: log.NewNopLogger()}
+
+	tests := []struct {
+		name   string
+		modify func(cmd *discordgo.ApplicationCommand)
+		equal  bool
+	}{
+		{
+			name:   "unchanged",
+			modify: func(cmd *discordgo.ApplicationCommand) {},
+			equal:  true,
+		},
+		{
+			name: "command description",
+			modify: func(cmd *discordgo.ApplicationCommand) {
+				cmd.Description = "Something else"
+			},
+		},
+		{
+			name: "subcommand removed",
+			modify: func(cmd *discordgo.ApplicationCommand) {
+				cmd.Options = cmd.Options[1:]
+			},
+		},
+		{
+			name: "option description",
+			modify: func(cmd *discordgo.ApplicationCommand) {
+				cmd.Options[0].Options[0].Description = "Something else"
+			},
+		},
+		{
+			name: "option required",
+			modify: func(cmd *discordgo.ApplicationCommand) {
+				cmd.Options[0].Options[0].Required = !cmd.Options[0].Options[0].Required
+			},
+		},
+		{
+			name: "option autocomplete",
+			modify: func(cmd *discordgo.ApplicationCommand) {
+				for _, opt := range cmd.Options[0].Options {
+					opt.Autocomplete = !opt.Autocomplete
+				}
+			},
+		},
+		{
+			name: "choice value",
+			modify: func(cmd *discordgo.ApplicationCommand) {
+				for _, sub := range cmd.Options {
+					if sub.Name == "use" {
+						sub.Options[0].Choices[0].Value = "something-else"
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired, _ := b.HotPotatoRootCommand()
+			existing := registered(t, desired)
+
+			desired, _ = b.HotPotatoRootCommand()
+			tt.modify(desired)
+
+			if got := commandsEqual([]*discordgo.ApplicationCommand{desired}, []*discordgo.ApplicationCommand{existing}); got != tt.equal {
+				t.Errorf("commandsEqual() = %t, want %t", got, tt.equal)
+			}
+		})
+	}
+}
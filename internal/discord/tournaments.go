@@ -0,0 +1,149 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// announceTournament posts the progress made in a tournament to the channel it
+// was set up in, closes the channel of the heat that ended and opens channels
+// for the heats of its next round. Failures are only logged, as the interaction
+// that made the progress has already been replied to.
+func (b *Bot) announceTournament(ctx context.Context, s *discordgo.Session, guildID string, update *hotpotato.TournamentUpdate) {
+	if update == nil {
+		return
+	}
+
+	logger := log.WithSuffix(b.logger, "guild", guildID, "channel", update.Tournament.ChannelID, "tournament", update.Tournament.Number)
+	if err := b.send(s, update.Tournament.ChannelID, TournamentUpdateReply(update)); err != nil {
+		level.Error(logger).Log("event", "tournament.announce.failure", "err", err)
+	} else {
+		level.Info(logger).Log("event", "tournament.announce.success")
+	}
+
+	b.closeHeat(s, guildID, update.Tournament, update.Heat)
+	b.openHeats(ctx, s, guildID, update.Tournament, update.Heats)
+}
+
+// closeHeat lets the players of a finished heat know that its channel is about
+// to be deleted, and schedules the deletion.
+func (b *Bot) closeHeat(s *discordgo.Session, guildID string, t *room.Tournament, heat *room.Heat) {
+	if heat == nil || heat.ClosesAt.IsZero() {
+		return
+	}
+
+	logger := log.WithSuffix(b.logger, "guild", guildID, "tournament", t.Number, "round", heat.Round, "heat", heat.Number, "channel", heat.ChannelID)
+	if err := b.send(s, heat.ChannelID, HeatClosingReply(t, heat, hotpotato.HeatChannelRetention)); err != nil {
+		level.Error(logger).Log("event", "heat.close.announce.failure", "err", err)
+	}
+
+	b.scheduleHeatClose(s, &room.ClosingHeat{Namespace: namespace, RoomID: guildID, Tournament: t.Number, Heat: heat})
+}
+
+// sweepHeats schedules the deletion of the channels of finished heats that were
+// still due to be closed when the bot was last stopped.
+func (b *Bot) sweepHeats(ctx context.Context, s *discordgo.Session) error {
+	rsp, err := b.hotpotato.ListClosingHeats(ctx, &hotpotato.ListClosingHeatsRequest{})
+	if err != nil {
+		return err
+	}
+
+	var swept int
+	for _, closing := range rsp.Heats {
+		if closing.Namespace != namespace {
+			continue
+		}
+		b.scheduleHeatClose(s, closing)
+		swept++
+	}
+	level.Info(b.logger).Log("event", "heat.channels.swept", "heats", swept)
+
+	return nil
+}
+
+// scheduleHeatClose deletes the channel of a finished heat once it is due to be
+// closed, and marks the heat as closed so that it isn't swept up again.
+func (b *Bot) scheduleHeatClose(s *discordgo.Session, closing *room.ClosingHeat) {
+	heat := closing.Heat
+	logger := log.WithSuffix(b.logger, "guild", closing.RoomID, "tournament", closing.Tournament, "round", heat.Round, "heat", heat.Number, "channel", heat.ChannelID)
+
+	time.AfterFunc(time.Until(heat.ClosesAt), func() {
+		if _, err := s.ChannelDelete(heat.ChannelID); err != nil && !isNotFound(err) {
+			level.Error(logger).Log("event", "heat.channel.delete.failure", "err", err)
+			return
+		}
+		level.Info(logger).Log("event", "heat.channel.delete.success")
+
+		_, err := b.hotpotato.CloseHeat(context.Background(), &hotpotato.CloseHeatRequest{
+			Namespace:  closing.Namespace,
+			RoomID:     closing.RoomID,
+			Tournament: closing.Tournament,
+			Round:      heat.Round,
+			Heat:       heat.Number,
+		})
+		if err != nil {
+			level.Error(logger).Log("event", "heat.close.failure", "err", err)
+		}
+	})
+}
+
+// isNotFound reports whether a request to Discord failed because what it was
+// made for no longer exists.
+func isNotFound(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
+
+// openHeats creates a channel for each of the given heats alongside the channel
+// that the tournament was set up in, and hands the first player of each heat a
+// potato in it.
+func (b *Bot) openHeats(ctx context.Context, s *discordgo.Session, guildID string, t *room.Tournament, heats []*room.Heat) {
+	var parentID string
+	if host, err := s.Channel(t.ChannelID); err == nil {
+		parentID = host.ParentID
+	}
+
+	for _, heat := range heats {
+		logger := log.WithSuffix(b.logger, "guild", guildID, "tournament", t.Number, "round", heat.Round, "heat", heat.Number)
+
+		channel, err := s.GuildChannelCreateComplex(guildID, discordgo.GuildChannelCreateData{
+			Name:     fmt.Sprintf("heat-t%d-r%d-%d", t.Number, heat.Round, heat.Number),
+			Type:     discordgo.ChannelTypeGuildText,
+			Topic:    fmt.Sprintf("Hot Potato tournament #%d, round %d, heat %d", t.Number, heat.Round, heat.Number),
+			ParentID: parentID,
+		})
+		if err != nil {
+			level.Error(logger).Log("event", "heat.channel.create.failure", "err", err)
+			continue
+		}
+
+		rsp, err := b.hotpotato.OpenHeat(ctx, &hotpotato.OpenHeatRequest{
+			Namespace:  namespace,
+			RoomID:     guildID,
+			Tournament: t.Number,
+			Round:      heat.Round,
+			Heat:       heat.Number,
+			ChannelID:  channel.ID,
+		})
+		if err != nil {
+			level.Error(logger).Log("event", "heat.open.failure", "channel", channel.ID, "err", err)
+			continue
+		}
+
+		if err := b.send(s, channel.ID, HeatStartedReply(t, rsp)); err != nil {
+			level.Error(logger).Log("event", "heat.announce.failure", "channel", channel.ID, "err", err)
+			continue
+		}
+		level.Info(logger).Log("event", "heat.open.success", "channel", channel.ID)
+	}
+}
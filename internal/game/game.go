@@ -3,28 +3,134 @@ package game
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
 	ErrGameAlreadyExists = errors.New("game for channel already exists")
 	ErrGameNotFound      = errors.New("game for channel not found")
+	ErrTurnConflict      = errors.New("game was changed by a concurrent turn")
 )
 
 type GameRepository interface {
-	GetGame(ctx context.Context, namespace, channelID string) (*Game, error)
-	CreateNewGame(ctx context.Context, namespace, roomID, channelID, potatoKind, startUserID string) (*Game, error)
-	NextTurn(ctx context.Context, namespace, channelID, holderUserID string) (*Game, error)
-	IncrementHeatLevel(ctx context.Context, namespace, channelID string) (*Game, error)
-	EndGame(ctx context.Context, namespace, channelID string) (*Game, error)
+	GetGame(ctx context.Context, namespace, channelID string, potatoID int) (*Game, error)
+	ListGames(ctx context.Context, namespace, channelID string) ([]*Game, error)
+	ListOngoingGames(ctx context.Context) ([]*Game, error)
+	CreateNewGame(ctx context.Context, namespace, roomID, channelID string, potatoID int, potatoKind, startUserID string, seed int64) (*Game, error)
+	RestartGame(ctx context.Context, namespace, channelID string, potatoID, round int, potatoKind, startUserID string, seed int64) (*Game, error)
+	PlayTurn(ctx context.Context, namespace, channelID string, potatoID int, play *Play) (*Game, error)
+	EndGame(ctx context.Context, namespace, channelID string, potatoID, turns int) (*Game, error)
+	RetireGame(ctx context.Context, namespace, channelID string, potatoID int) error
+	ListTurns(ctx context.Context, namespace, channelID string, potatoID, round int) ([]*Turn, error)
+	GetRecord(ctx context.Context, namespace, roomID, userID string) (*Record, error)
+	AssignTeams(ctx context.Context, namespace, channelID string, potatoID, round int, teams map[string]string) (*Game, error)
+	AssignPlayers(ctx context.Context, namespace, channelID string, potatoID, round int, userIDs []string) (*Game, error)
+	JoinLobby(ctx context.Context, namespace, roomID, channelID, userID string) ([]string, error)
+	ListLobby(ctx context.Context, namespace, channelID string) ([]string, error)
+	ClearLobby(ctx context.Context, namespace, channelID string) error
+	ListActivePlayers(ctx context.Context, namespace, channelID string) ([]string, error)
 }
 
+// Game is the play of a single potato in a channel. Channels can have several
+// potatoes in play at once, each told apart by its PotatoID.
 type Game struct {
 	Namespace    string
 	RoomID       string
 	ChannelID    string
+	PotatoID     int
 	PotatoKind   string
 	HeatLevel    int
 	HolderUserID string
 	Turns        int
 	Finished     bool
+	Seed         int64
+	Round        int
+	UpdatedAt    time.Time
+
+	// Teams maps the players of a team game to the team they are on, and is
+	// empty for free-for-all games.
+	Teams map[string]string
+
+	// Players are the players left in an elimination game, and is empty for
+	// other games.
+	Players []string
+}
+
+// TeamGame reports whether the game is played between teams.
+func (g *Game) TeamGame() bool {
+	return len(g.Teams) > 0
+}
+
+// EliminationGame reports whether the game is played until one player is
+// left standing.
+func (g *Game) EliminationGame() bool {
+	return len(g.Players) > 0
+}
+
+// Playing reports whether the user is one of the players left in an
+// elimination game.
+func (g *Game) Playing(userID string) bool {
+	for _, player := range g.Players {
+		if player == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Action string
+
+const (
+	ActionToss  Action = "toss"
+	ActionSteal Action = "steal"
+	ActionCook  Action = "cook"
+
+	// ActionExplode is the turn recorded when a potato explodes in its holder's
+	// hands after being held for too long.
+	ActionExplode Action = "explode"
+)
+
+// Play is a turn to be played on a game. It only takes effect if the game is
+// still ongoing, held by ExpectedHolderUserID and on ExpectedTurns, otherwise
+// ErrTurnConflict is returned. The game ends if the turn exploded.
+type Play struct {
+	ExpectedHolderUserID string
+	ExpectedTurns        int
+	HolderUserID         string
+	HeatIncrease         int
+	Turn                 *Turn
+}
+
+type Turn struct {
+	Round         int
+	Turn          int
+	Action        Action
+	ActorUserID   string
+	TargetUserID  string
+	HeatLevel     int
+	ExplodeChance int
+	Exploded      bool
+	CreatedAt     time.Time
+}
+
+// Players returns the users that took part in the turn, which count as active
+// in the channel it was played in.
+func (t *Turn) Players() []string {
+	if t.TargetUserID == "" || t.TargetUserID == t.ActorUserID {
+		return []string{t.ActorUserID}
+	}
+
+	return []string{t.ActorUserID, t.TargetUserID}
+}
+
+// Record is a user's history of play across all games in a room. A user's
+// nemesis is whoever most often tossed them a potato that exploded in their
+// hands.
+type Record struct {
+	GamesPlayed           int
+	FavouriteTargetUserID string
+	FavouriteTargetTosses int
+	NemesisUserID         string
+	NemesisKills          int
 }
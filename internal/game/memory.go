@@ -0,0 +1,417 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepository is a GameRepository that keeps games in memory, for running
+// without a database.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	games   map[string]*Game
+	turns   map[string][]*Turn
+	lobbies map[string][]string
+
+	// active holds the users that have played in each channel, most recently
+	// active first.
+	active map[string][]string
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		games:   make(map[string]*Game),
+		turns:   make(map[string][]*Turn),
+		lobbies: make(map[string][]string),
+		active:  make(map[string][]string),
+	}
+}
+
+func (r *MemoryRepository) GetGame(ctx context.Context, namespace, channelID string, potatoID int) (*Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	game, ok := r.games[memoryKey(namespace, channelID, potatoID)]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) ListGames(ctx context.Context, namespace, channelID string) ([]*Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var games []*Game
+	for _, game := range r.games {
+		if game.Namespace == namespace && game.ChannelID == channelID {
+			games = append(games, copyGame(game))
+		}
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].PotatoID < games[j].PotatoID
+	})
+
+	return games, nil
+}
+
+func (r *MemoryRepository) ListOngoingGames(ctx context.Context) ([]*Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var games []*Game
+	for _, game := range r.games {
+		if !game.Finished {
+			games = append(games, copyGame(game))
+		}
+	}
+
+	return games, nil
+}
+
+func (r *MemoryRepository) CreateNewGame(ctx context.Context, namespace, roomID, channelID string, potatoID int, potatoKind, startUserID string, seed int64) (*Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memoryKey(namespace, channelID, potatoID)
+	if _, ok := r.games[key]; ok {
+		return nil, ErrGameAlreadyExists
+	}
+
+	game := &Game{
+		Namespace:    namespace,
+		RoomID:       roomID,
+		ChannelID:    channelID,
+		PotatoID:     potatoID,
+		PotatoKind:   potatoKind,
+		HeatLevel:    1,
+		HolderUserID: startUserID,
+		Turns:        0,
+		Finished:     false,
+		Seed:         seed,
+		Round:        0,
+		UpdatedAt:    time.Now(),
+	}
+	r.games[key] = game
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) RestartGame(ctx context.Context, namespace, channelID string, potatoID, round int, potatoKind, startUserID string, seed int64) (*Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	game, ok := r.games[memoryKey(namespace, channelID, potatoID)]
+	if !ok || game.Round != round {
+		return nil, ErrGameAlreadyExists
+	}
+
+	game.PotatoKind = potatoKind
+	game.HeatLevel = 1
+	game.HolderUserID = startUserID
+	game.Turns = 0
+	game.Finished = false
+	game.Seed = seed
+	game.Round++
+	game.UpdatedAt = time.Now()
+	game.Teams = nil
+	game.Players = nil
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) PlayTurn(ctx context.Context, namespace, channelID string, potatoID int, play *Play) (*Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memoryKey(namespace, channelID, potatoID)
+	game, ok := r.games[key]
+	if !ok || game.Finished || game.HolderUserID != play.ExpectedHolderUserID || game.Turns != play.ExpectedTurns {
+		return nil, ErrTurnConflict
+	}
+
+	game.HolderUserID = play.HolderUserID
+	game.HeatLevel += play.HeatIncrease
+	game.Turns++
+	game.Finished = play.Turn.Exploded
+	game.UpdatedAt = time.Now()
+
+	turnsKey := memoryTurnsKey(namespace, channelID, potatoID, game.Round)
+	r.turns[turnsKey] = append(r.turns[turnsKey], &Turn{
+		Round:         game.Round,
+		Turn:          game.Turns,
+		Action:        play.Turn.Action,
+		ActorUserID:   play.Turn.ActorUserID,
+		TargetUserID:  play.Turn.TargetUserID,
+		HeatLevel:     game.HeatLevel,
+		ExplodeChance: play.Turn.ExplodeChance,
+		Exploded:      play.Turn.Exploded,
+		CreatedAt:     game.UpdatedAt,
+	})
+
+	activeKey := memoryActiveKey(namespace, channelID)
+	for _, userID := range play.Turn.Players() {
+		active := []string{userID}
+		for _, other := range r.active[activeKey] {
+			if other != userID {
+				active = append(active, other)
+			}
+		}
+		r.active[activeKey] = active
+	}
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) EndGame(ctx context.Context, namespace, channelID string, potatoID, turns int) (*Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	game, ok := r.games[memoryKey(namespace, channelID, potatoID)]
+	if !ok || game.Finished || game.Turns != turns {
+		return nil, ErrTurnConflict
+	}
+	game.Finished = true
+	game.Turns++
+	game.UpdatedAt = time.Now()
+
+	turnsKey := memoryTurnsKey(namespace, channelID, potatoID, game.Round)
+	r.turns[turnsKey] = append(r.turns[turnsKey], &Turn{
+		Round:         game.Round,
+		Turn:          game.Turns,
+		Action:        ActionExplode,
+		ActorUserID:   game.HolderUserID,
+		TargetUserID:  game.HolderUserID,
+		HeatLevel:     game.HeatLevel,
+		ExplodeChance: 100,
+		Exploded:      true,
+		CreatedAt:     game.UpdatedAt,
+	})
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) RetireGame(ctx context.Context, namespace, channelID string, potatoID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	game, ok := r.games[memoryKey(namespace, channelID, potatoID)]
+	if ok && !game.Finished {
+		game.Finished = true
+		game.UpdatedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) ListTurns(ctx context.Context, namespace, channelID string, potatoID, round int) ([]*Turn, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rows := r.turns[memoryTurnsKey(namespace, channelID, potatoID, round)]
+
+	turns := make([]*Turn, len(rows))
+	for i, row := range rows {
+		turn := *row
+		turns[i] = &turn
+	}
+
+	return turns, nil
+}
+
+func (r *MemoryRepository) GetRecord(ctx context.Context, namespace, roomID, userID string) (*Record, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record := &Record{}
+	tosses := make(map[string]int)
+	kills := make(map[string]int)
+	for _, game := range r.games {
+		if game.Namespace != namespace || game.RoomID != roomID {
+			continue
+		}
+
+		for round := 0; round <= game.Round; round++ {
+			played := false
+			for _, turn := range r.turns[memoryTurnsKey(namespace, game.ChannelID, game.PotatoID, round)] {
+				if turn.ActorUserID != userID && turn.TargetUserID != userID {
+					continue
+				}
+				played = true
+
+				if turn.Action != ActionToss || turn.ActorUserID == turn.TargetUserID {
+					continue
+				}
+
+				switch {
+				case turn.ActorUserID == userID:
+					tosses[turn.TargetUserID]++
+				case turn.Exploded:
+					kills[turn.ActorUserID]++
+				}
+			}
+
+			if played {
+				record.GamesPlayed++
+			}
+		}
+	}
+
+	record.FavouriteTargetUserID, record.FavouriteTargetTosses = mostFrequent(tosses)
+	record.NemesisUserID, record.NemesisKills = mostFrequent(kills)
+
+	return record, nil
+}
+
+func (r *MemoryRepository) AssignTeams(ctx context.Context, namespace, channelID string, potatoID, round int, teams map[string]string) (*Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	game, ok := r.games[memoryKey(namespace, channelID, potatoID)]
+	if !ok || game.Finished || game.Round != round || game.Turns != 0 || game.TeamGame() || game.EliminationGame() {
+		return nil, ErrTurnConflict
+	}
+
+	game.Teams = make(map[string]string, len(teams))
+	for userID, team := range teams {
+		game.Teams[userID] = team
+	}
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) AssignPlayers(ctx context.Context, namespace, channelID string, potatoID, round int, userIDs []string) (*Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	game, ok := r.games[memoryKey(namespace, channelID, potatoID)]
+	if !ok || game.Finished || game.Round != round || game.Turns != 0 || game.TeamGame() || game.EliminationGame() {
+		return nil, ErrTurnConflict
+	}
+
+	game.Players = append([]string(nil), userIDs...)
+	sort.Strings(game.Players)
+
+	return copyGame(game), nil
+}
+
+func (r *MemoryRepository) JoinLobby(ctx context.Context, namespace, roomID, channelID, userID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memoryLobbyKey(namespace, channelID)
+	for _, joined := range r.lobbies[key] {
+		if joined == userID {
+			return append([]string(nil), r.lobbies[key]...), nil
+		}
+	}
+	r.lobbies[key] = append(r.lobbies[key], userID)
+
+	return append([]string(nil), r.lobbies[key]...), nil
+}
+
+func (r *MemoryRepository) ListLobby(ctx context.Context, namespace, channelID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.lobbies[memoryLobbyKey(namespace, channelID)]...), nil
+}
+
+func (r *MemoryRepository) ClearLobby(ctx context.Context, namespace, channelID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.lobbies, memoryLobbyKey(namespace, channelID))
+
+	return nil
+}
+
+func (r *MemoryRepository) ListActivePlayers(ctx context.Context, namespace, channelID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.active[memoryActiveKey(namespace, channelID)]...), nil
+}
+
+// Snapshot copies the games held in memory, returning a function that restores
+// them.
+func (r *MemoryRepository) Snapshot() func() {
+	r.mu.RLock()
+	games := make(map[string]*Game, len(r.games))
+	for key, game := range r.games {
+		games[key] = copyGame(game)
+	}
+	turns := make(map[string][]*Turn, len(r.turns))
+	for key, ts := range r.turns {
+		turns[key] = make([]*Turn, len(ts))
+		for i, turn := range ts {
+			t := *turn
+			turns[key][i] = &t
+		}
+	}
+	lobbies := copyUserIDs(r.lobbies)
+	active := copyUserIDs(r.active)
+	r.mu.RUnlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.games, r.turns, r.lobbies, r.active = games, turns, lobbies, active
+	}
+}
+
+// mostFrequent returns the user with the highest count, breaking ties by user
+// ID.
+func mostFrequent(counts map[string]int) (string, int) {
+	var userID string
+	var count int
+	for id, n := range counts {
+		if n > count || (n == count && id < userID) {
+			userID, count = id, n
+		}
+	}
+
+	return userID, count
+}
+
+func copyGame(game *Game) *Game {
+	g := *game
+	if game.Teams != nil {
+		g.Teams = make(map[string]string, len(game.Teams))
+		for userID, team := range game.Teams {
+			g.Teams[userID] = team
+		}
+	}
+	g.Players = append([]string(nil), game.Players...)
+	return &g
+}
+
+func copyUserIDs(userIDs map[string][]string) map[string][]string {
+	c := make(map[string][]string, len(userIDs))
+	for key, ids := range userIDs {
+		c[key] = append([]string(nil), ids...)
+	}
+	return c
+}
+
+func memoryKey(namespace, channelID string, potatoID int) string {
+	return fmt.Sprintf("%s/%s/%d", namespace, channelID, potatoID)
+}
+
+func memoryTurnsKey(namespace, channelID string, potatoID, round int) string {
+	return fmt.Sprintf("%s/%s/%d/%d", namespace, channelID, potatoID, round)
+}
+
+func memoryLobbyKey(namespace, channelID string) string {
+	return fmt.Sprintf("%s/%s", namespace, channelID)
+}
+
+func memoryActiveKey(namespace, channelID string) string {
+	return fmt.Sprintf("%s/%s", namespace, channelID)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/jace-ys/hot-potato-discord/internal/database"
 	"github.com/jace-ys/hot-potato-discord/internal/game/store"
 )
 
@@ -13,17 +14,26 @@ type Repository struct {
 	store *store.Queries
 }
 
-func NewRepository(database *sql.DB) *Repository {
+func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		db:    database,
-		store: store.New(database),
+		db:    db,
+		store: store.New(db),
 	}
 }
 
-func (r *Repository) GetGame(ctx context.Context, namespace, channelID string) (*Game, error) {
-	game, err := r.store.GetGame(ctx, store.GetGameParams{
+// queries runs queries in the transaction carried on the context, if any.
+func (r *Repository) queries(ctx context.Context) *store.Queries {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return r.store.WithTx(tx)
+	}
+	return r.store
+}
+
+func (r *Repository) GetGame(ctx context.Context, namespace, channelID string, potatoID int) (*Game, error) {
+	game, err := r.queries(ctx).GetGame(ctx, store.GetGameParams{
 		Namespace: namespace,
 		ChannelID: channelID,
+		PotatoID:  int32(potatoID),
 	})
 	if err != nil {
 		switch {
@@ -33,101 +43,602 @@ func (r *Repository) GetGame(ctx context.Context, namespace, channelID string) (
 		return nil, err
 	}
 
-	return StoreToDomain(game), err
+	return withRoster(ctx, r.queries(ctx), game)
+}
+
+func (r *Repository) ListGames(ctx context.Context, namespace, channelID string) ([]*Game, error) {
+	q := r.queries(ctx)
+
+	rows, err := q.ListGames(ctx, store.ListGamesParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := q.ListChannelGameTeams(ctx, store.ListChannelGameTeamsParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := q.ListChannelGamePlayers(ctx, store.ListChannelGamePlayersParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rosters := make(rosters)
+	for _, row := range teams {
+		rosters.addTeam(row.Namespace, row.ChannelID, row.PotatoID, row.UserID, row.Team)
+	}
+	for _, row := range players {
+		rosters.addPlayer(row.Namespace, row.ChannelID, row.PotatoID, row.UserID)
+	}
+
+	return rosters.games(rows), nil
 }
 
-func (r *Repository) CreateNewGame(ctx context.Context, namespace, roomID, channelID, potatoKind, startUserID string) (*Game, error) {
-	game, err := r.store.UpsertGame(ctx, store.UpsertGameParams{
+func (r *Repository) ListOngoingGames(ctx context.Context) ([]*Game, error) {
+	q := r.queries(ctx)
+
+	rows, err := q.ListOngoingGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := q.ListOngoingGameTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := q.ListOngoingGamePlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rosters := make(rosters)
+	for _, row := range teams {
+		rosters.addTeam(row.Namespace, row.ChannelID, row.PotatoID, row.UserID, row.Team)
+	}
+	for _, row := range players {
+		rosters.addPlayer(row.Namespace, row.ChannelID, row.PotatoID, row.UserID)
+	}
+
+	return rosters.games(rows), nil
+}
+
+func (r *Repository) CreateNewGame(ctx context.Context, namespace, roomID, channelID string, potatoID int, potatoKind, startUserID string, seed int64) (*Game, error) {
+	game, err := r.queries(ctx).InsertGame(ctx, store.InsertGameParams{
 		Namespace:    namespace,
 		RoomID:       roomID,
 		ChannelID:    channelID,
+		PotatoID:     int32(potatoID),
+		PotatoKind:   potatoKind,
+		HeatLevel:    1,
+		HolderUserID: startUserID,
+		Turns:        0,
+		Finished:     false,
+		Seed:         seed,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrGameAlreadyExists
+		}
+		return nil, err
+	}
+
+	return StoreToDomain(game), err
+}
+
+func (r *Repository) RestartGame(ctx context.Context, namespace, channelID string, potatoID, round int, potatoKind, startUserID string, seed int64) (*Game, error) {
+	game, err := r.queries(ctx).RestartGame(ctx, store.RestartGameParams{
+		Namespace:    namespace,
+		ChannelID:    channelID,
+		PotatoID:     int32(potatoID),
+		Round:        int32(round),
 		PotatoKind:   potatoKind,
 		HeatLevel:    1,
 		HolderUserID: startUserID,
 		Turns:        0,
 		Finished:     false,
+		Seed:         seed,
 	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrGameAlreadyExists
+		}
+		return nil, err
+	}
 
 	return StoreToDomain(game), err
 }
 
-func (r *Repository) NextTurn(ctx context.Context, namespace, channelID, holderUserID string) (*Game, error) {
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+func (r *Repository) PlayTurn(ctx context.Context, namespace, channelID string, potatoID int, play *Play) (*Game, error) {
+	tx, err := database.BeginTx(ctx, r.db)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	game, err := r.store.WithTx(tx).UpdateHolder(ctx, store.UpdateHolderParams{
-		Namespace:    namespace,
-		ChannelID:    channelID,
-		HolderUserID: holderUserID,
+	game, err := r.store.WithTx(tx.Tx).AdvanceTurn(ctx, store.AdvanceTurnParams{
+		HolderUserID:         play.HolderUserID,
+		HeatIncrease:         int32(play.HeatIncrease),
+		Finished:             play.Turn.Exploded,
+		Namespace:            namespace,
+		ChannelID:            channelID,
+		PotatoID:             int32(potatoID),
+		ExpectedHolderUserID: play.ExpectedHolderUserID,
+		ExpectedTurns:        int32(play.ExpectedTurns),
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return nil, ErrGameNotFound
+			return nil, ErrTurnConflict
+		}
+		return nil, err
+	}
+
+	err = r.store.WithTx(tx.Tx).InsertTurn(ctx, store.InsertTurnParams{
+		Namespace:     game.Namespace,
+		RoomID:        game.RoomID,
+		ChannelID:     game.ChannelID,
+		PotatoID:      game.PotatoID,
+		Round:         game.Round,
+		Turn:          game.Turns,
+		Action:        string(play.Turn.Action),
+		ActorUserID:   play.Turn.ActorUserID,
+		TargetUserID:  play.Turn.TargetUserID,
+		HeatLevel:     game.HeatLevel,
+		ExplodeChance: int32(play.Turn.ExplodeChance),
+		Exploded:      play.Turn.Exploded,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range play.Turn.Players() {
+		err = r.store.WithTx(tx.Tx).UpsertChannelActivity(ctx, store.UpsertChannelActivityParams{
+			Namespace: game.Namespace,
+			RoomID:    game.RoomID,
+			ChannelID: game.ChannelID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	next, err := withRoster(ctx, r.store.WithTx(tx.Tx), game)
+	if err != nil {
 		return nil, err
 	}
 
-	game, err = r.store.WithTx(tx).IncrementTurns(ctx, store.IncrementTurnsParams{
+	return next, tx.Commit()
+}
+
+// EndGame explodes the potato in its holder's hands if the game is still on
+// the given turn, recording the explosion as a turn of its own.
+func (r *Repository) EndGame(ctx context.Context, namespace, channelID string, potatoID, turns int) (*Game, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	game, err := r.store.WithTx(tx.Tx).EndGame(ctx, store.EndGameParams{
 		Namespace: namespace,
 		ChannelID: channelID,
+		PotatoID:  int32(potatoID),
+		Turns:     int32(turns),
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return nil, ErrGameNotFound
+			return nil, ErrTurnConflict
 		}
 		return nil, err
 	}
 
-	return StoreToDomain(game), tx.Commit()
+	err = r.store.WithTx(tx.Tx).InsertTurn(ctx, store.InsertTurnParams{
+		Namespace:     game.Namespace,
+		RoomID:        game.RoomID,
+		ChannelID:     game.ChannelID,
+		PotatoID:      game.PotatoID,
+		Round:         game.Round,
+		Turn:          game.Turns,
+		Action:        string(ActionExplode),
+		ActorUserID:   game.HolderUserID,
+		TargetUserID:  game.HolderUserID,
+		HeatLevel:     game.HeatLevel,
+		ExplodeChance: 100,
+		Exploded:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := withRoster(ctx, r.store.WithTx(tx.Tx), game)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, tx.Commit()
 }
 
-func (r *Repository) IncrementHeatLevel(ctx context.Context, namespace, channelID string) (*Game, error) {
-	game, err := r.store.IncreaseHeatLevel(ctx, store.IncreaseHeatLevelParams{
+func (r *Repository) ListTurns(ctx context.Context, namespace, channelID string, potatoID, round int) ([]*Turn, error) {
+	rows, err := r.queries(ctx).ListTurns(ctx, store.ListTurnsParams{
 		Namespace: namespace,
 		ChannelID: channelID,
-		Amount:    1,
+		PotatoID:  int32(potatoID),
+		Round:     int32(round),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	turns := make([]*Turn, len(rows))
+	for i, row := range rows {
+		turns[i] = TurnStoreToDomain(row)
+	}
+
+	return turns, nil
+}
+
+func (r *Repository) GetRecord(ctx context.Context, namespace, roomID, userID string) (*Record, error) {
+	games, err := r.queries(ctx).CountGamesPlayed(ctx, store.CountGamesPlayedParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record := &Record{
+		GamesPlayed: int(games),
+	}
+
+	target, err := r.queries(ctx).GetFavouriteTarget(ctx, store.GetFavouriteTargetParams{
+		Namespace:   namespace,
+		RoomID:      roomID,
+		ActorUserID: userID,
+	})
+	switch {
+	case err == nil:
+		record.FavouriteTargetUserID = target.TargetUserID
+		record.FavouriteTargetTosses = int(target.Tosses)
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	nemesis, err := r.queries(ctx).GetNemesis(ctx, store.GetNemesisParams{
+		Namespace:    namespace,
+		RoomID:       roomID,
+		TargetUserID: userID,
+	})
+	switch {
+	case err == nil:
+		record.NemesisUserID = nemesis.ActorUserID
+		record.NemesisKills = int(nemesis.Kills)
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// RetireGame ends the game without the potato exploding, for games holding a
+// potato kind that is no longer in play.
+func (r *Repository) RetireGame(ctx context.Context, namespace, channelID string, potatoID int) error {
+	return r.queries(ctx).RetireGame(ctx, store.RetireGameParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+		PotatoID:  int32(potatoID),
+	})
+}
+
+func (r *Repository) AssignTeams(ctx context.Context, namespace, channelID string, potatoID, round int, teams map[string]string) (*Game, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.store.WithTx(tx.Tx)
+
+	game, err := q.GetGame(ctx, store.GetGameParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+		PotatoID:  int32(potatoID),
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return nil, ErrGameNotFound
+			return nil, ErrTurnConflict
 		}
 		return nil, err
 	}
 
-	return StoreToDomain(game), err
+	assigned, err := q.ListGameTeams(ctx, store.ListGameTeamsParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+		PotatoID:  game.PotatoID,
+		Round:     game.Round,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	playing, err := q.ListGamePlayers(ctx, store.ListGamePlayersParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+		PotatoID:  game.PotatoID,
+		Round:     game.Round,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if game.Finished || int(game.Round) != round || game.Turns != 0 || len(assigned) > 0 || len(playing) > 0 {
+		return nil, ErrTurnConflict
+	}
+
+	for userID, team := range teams {
+		err := q.InsertGameTeam(ctx, store.InsertGameTeamParams{
+			Namespace: namespace,
+			RoomID:    game.RoomID,
+			ChannelID: channelID,
+			PotatoID:  game.PotatoID,
+			Round:     game.Round,
+			UserID:    userID,
+			Team:      team,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	next, err := withRoster(ctx, q, game)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, tx.Commit()
 }
 
-func (r *Repository) EndGame(ctx context.Context, namespace, channelID string) (*Game, error) {
-	game, err := r.store.EndGame(ctx, store.EndGameParams{
+func (r *Repository) AssignPlayers(ctx context.Context, namespace, channelID string, potatoID, round int, userIDs []string) (*Game, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.store.WithTx(tx.Tx)
+
+	game, err := q.GetGame(ctx, store.GetGameParams{
 		Namespace: namespace,
 		ChannelID: channelID,
+		PotatoID:  int32(potatoID),
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return nil, ErrGameNotFound
+			return nil, ErrTurnConflict
 		}
 		return nil, err
 	}
 
-	return StoreToDomain(game), err
+	current, err := withRoster(ctx, q, game)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Finished || current.Round != round || current.Turns != 0 || current.TeamGame() || current.EliminationGame() {
+		return nil, ErrTurnConflict
+	}
+
+	for _, userID := range userIDs {
+		err := q.InsertGamePlayer(ctx, store.InsertGamePlayerParams{
+			Namespace: namespace,
+			RoomID:    game.RoomID,
+			ChannelID: channelID,
+			PotatoID:  game.PotatoID,
+			Round:     game.Round,
+			UserID:    userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	next, err := withRoster(ctx, q, game)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, tx.Commit()
+}
+
+func (r *Repository) JoinLobby(ctx context.Context, namespace, roomID, channelID, userID string) ([]string, error) {
+	err := r.queries(ctx).InsertLobbyPlayer(ctx, store.InsertLobbyPlayerParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		ChannelID: channelID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ListLobby(ctx, namespace, channelID)
+}
+
+func (r *Repository) ListLobby(ctx context.Context, namespace, channelID string) ([]string, error) {
+	rows, err := r.queries(ctx).ListLobbyPlayers(ctx, store.ListLobbyPlayersParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, len(rows))
+	for i, row := range rows {
+		userIDs[i] = row.UserID
+	}
+
+	return userIDs, nil
+}
+
+func (r *Repository) ClearLobby(ctx context.Context, namespace, channelID string) error {
+	return r.queries(ctx).ClearLobby(ctx, store.ClearLobbyParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+	})
+}
+
+// ListActivePlayers lists the users that have played turns in the channel,
+// most recently active first.
+func (r *Repository) ListActivePlayers(ctx context.Context, namespace, channelID string) ([]string, error) {
+	rows, err := r.queries(ctx).ListChannelActivity(ctx, store.ListChannelActivityParams{
+		Namespace: namespace,
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, len(rows))
+	for i, row := range rows {
+		userIDs[i] = row.UserID
+	}
+
+	return userIDs, nil
+}
+
+// withRoster converts the game to its domain model, along with the teams or
+// players of its current round.
+func withRoster(ctx context.Context, q *store.Queries, game store.Game) (*Game, error) {
+	rows, err := q.ListGameTeams(ctx, store.ListGameTeamsParams{
+		Namespace: game.Namespace,
+		ChannelID: game.ChannelID,
+		PotatoID:  game.PotatoID,
+		Round:     game.Round,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := q.ListGamePlayers(ctx, store.ListGamePlayersParams{
+		Namespace: game.Namespace,
+		ChannelID: game.ChannelID,
+		PotatoID:  game.PotatoID,
+		Round:     game.Round,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	g := StoreToDomain(game)
+	if len(rows) > 0 {
+		g.Teams = make(map[string]string, len(rows))
+		for _, row := range rows {
+			g.Teams[row.UserID] = row.Team
+		}
+	}
+
+	for _, row := range players {
+		g.Players = append(g.Players, row.UserID)
+	}
+
+	return g, nil
+}
+
+// rosters holds the teams and players of the current round of several games,
+// so that they can be loaded together instead of game by game.
+type rosters map[rosterKey]*Game
+
+type rosterKey struct {
+	namespace string
+	channelID string
+	potatoID  int32
+}
+
+func (r rosters) game(namespace, channelID string, potatoID int32) *Game {
+	key := rosterKey{namespace, channelID, potatoID}
+	if _, ok := r[key]; !ok {
+		r[key] = &Game{}
+	}
+	return r[key]
+}
+
+func (r rosters) addTeam(namespace, channelID string, potatoID int32, userID, team string) {
+	g := r.game(namespace, channelID, potatoID)
+	if g.Teams == nil {
+		g.Teams = make(map[string]string)
+	}
+	g.Teams[userID] = team
+}
+
+func (r rosters) addPlayer(namespace, channelID string, potatoID int32, userID string) {
+	g := r.game(namespace, channelID, potatoID)
+	g.Players = append(g.Players, userID)
+}
+
+// games converts the games to their domain model, along with their rosters.
+func (r rosters) games(rows []store.Game) []*Game {
+	games := make([]*Game, len(rows))
+	for i, row := range rows {
+		games[i] = StoreToDomain(row)
+		if roster, ok := r[rosterKey{row.Namespace, row.ChannelID, row.PotatoID}]; ok {
+			games[i].Teams = roster.Teams
+			games[i].Players = roster.Players
+		}
+	}
+
+	return games
 }
 
 func StoreToDomain(game store.Game) *Game {
+	updatedAt := game.CreatedAt.Time
+	if game.UpdatedAt.Valid {
+		updatedAt = game.UpdatedAt.Time
+	}
+
 	return &Game{
 		Namespace:    game.Namespace,
 		RoomID:       game.RoomID,
 		ChannelID:    game.ChannelID,
+		PotatoID:     int(game.PotatoID),
 		PotatoKind:   game.PotatoKind,
 		HeatLevel:    int(game.HeatLevel),
 		HolderUserID: game.HolderUserID,
 		Turns:        int(game.Turns),
 		Finished:     game.Finished,
+		Seed:         game.Seed,
+		Round:        int(game.Round),
+		UpdatedAt:    updatedAt,
+	}
+}
+
+func TurnStoreToDomain(turn store.GameTurn) *Turn {
+	return &Turn{
+		Round:         int(turn.Round),
+		Turn:          int(turn.Turn),
+		Action:        Action(turn.Action),
+		ActorUserID:   turn.ActorUserID,
+		TargetUserID:  turn.TargetUserID,
+		HeatLevel:     int(turn.HeatLevel),
+		ExplodeChance: int(turn.ExplodeChance),
+		Exploded:      turn.Exploded,
+		CreatedAt:     turn.CreatedAt.Time,
 	}
 }
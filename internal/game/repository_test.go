@@ -0,0 +1,285 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/jace-ys/hot-potato-discord/db"
+	"github.com/jace-ys/hot-potato-discord/internal/database"
+)
+
+const (
+	testNamespace = "guild"
+	testRoomID    = "room"
+	testChannelID = "channel"
+)
+
+type testStorage struct {
+	name string
+	open func(t *testing.T) GameRepository
+}
+
+var testStorages = []testStorage{
+	{name: "memory", open: func(t *testing.T) GameRepository { return NewMemoryRepository() }},
+	{name: "sqlite", open: openSQLiteRepository},
+}
+
+func openSQLiteRepository(t *testing.T) GameRepository {
+	sqldb, dialect, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "hotpotato.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqldb.Close() })
+
+	migrator := database.NewMigrator(log.NewNopLogger(), sqldb, dialect, db.Migrations(string(dialect)))
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	if _, err := sqldb.Exec("INSERT INTO rooms (namespace, id) VALUES (?, ?)", testNamespace, testRoomID); err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	return NewRepository(sqldb)
+}
+
+func TestRepositoryTurns(t *testing.T) {
+	plays := []struct {
+		holderUserID string
+		heatIncrease int
+		turn         *Turn
+	}{
+		{holderUserID: "b", turn: &Turn{Action: ActionToss, ActorUserID: "a", TargetUserID: "b", HeatLevel: 1, ExplodeChance: 5}},
+		{holderUserID: "b", heatIncrease: 1, turn: &Turn{Action: ActionCook, ActorUserID: "b", HeatLevel: 2, ExplodeChance: 7}},
+		{holderUserID: "c", turn: &Turn{Action: ActionSteal, ActorUserID: "c", TargetUserID: "b", HeatLevel: 2, ExplodeChance: 7}},
+		{holderUserID: "a", turn: &Turn{Action: ActionToss, ActorUserID: "c", TargetUserID: "a", HeatLevel: 2, ExplodeChance: 9, Exploded: true}},
+	}
+
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			games := storage.open(t)
+			ctx := context.Background()
+
+			g, err := games.CreateNewGame(ctx, testNamespace, testRoomID, testChannelID, 1, "hot", "a", 1)
+			if err != nil {
+				t.Fatalf("failed to create game: %v", err)
+			}
+
+			for i, play := range plays {
+				play.turn.Round = g.Round
+				play.turn.Turn = g.Turns + 1
+
+				g, err = games.PlayTurn(ctx, testNamespace, testChannelID, 1, &Play{
+					ExpectedHolderUserID: g.HolderUserID,
+					ExpectedTurns:        g.Turns,
+					HolderUserID:         play.holderUserID,
+					HeatIncrease:         play.heatIncrease,
+					Turn:                 play.turn,
+				})
+				if err != nil {
+					t.Fatalf("failed to play turn %d: %v", i+1, err)
+				}
+			}
+
+			if g.Turns != len(plays) || g.HolderUserID != "a" || g.HeatLevel != 2 || !g.Finished {
+				t.Fatalf("unexpected game after playing turns: %+v", g)
+			}
+
+			turns, err := games.ListTurns(ctx, testNamespace, testChannelID, 1, g.Round)
+			if err != nil {
+				t.Fatalf("failed to list turns: %v", err)
+			}
+			if len(turns) != len(plays) {
+				t.Fatalf("%d turns recorded, want %d", len(turns), len(plays))
+			}
+
+			for i, turn := range turns {
+				want := *plays[i].turn
+				want.CreatedAt = turn.CreatedAt
+				if *turn != want {
+					t.Errorf("turn %d = %+v, want %+v", i+1, *turn, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRepositoryPlayTurnConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		play *Play
+	}{
+		{name: "wrong holder", play: &Play{ExpectedHolderUserID: "b", ExpectedTurns: 1}},
+		{name: "wrong turns", play: &Play{ExpectedHolderUserID: "a", ExpectedTurns: 0}},
+	}
+
+	for _, storage := range testStorages {
+		for _, tt := range tests {
+			t.Run(storage.name+"/"+tt.name, func(t *testing.T) {
+				games := storage.open(t)
+				ctx := context.Background()
+
+				g, err := games.CreateNewGame(ctx, testNamespace, testRoomID, testChannelID, 1, "hot", "a", 1)
+				if err != nil {
+					t.Fatalf("failed to create game: %v", err)
+				}
+
+				_, err = games.PlayTurn(ctx, testNamespace, testChannelID, 1, &Play{
+					ExpectedHolderUserID: "a",
+					ExpectedTurns:        0,
+					HolderUserID:         "a",
+					Turn:                 &Turn{Round: g.Round, Turn: 1, Action: ActionCook, ActorUserID: "a"},
+				})
+				if err != nil {
+					t.Fatalf("failed to play turn: %v", err)
+				}
+
+				tt.play.HolderUserID = "c"
+				tt.play.Turn = &Turn{Round: g.Round, Turn: 2, Action: ActionToss, ActorUserID: "a", TargetUserID: "c"}
+				if _, err := games.PlayTurn(ctx, testNamespace, testChannelID, 1, tt.play); !errors.Is(err, ErrTurnConflict) {
+					t.Fatalf("playing a stale turn returned %v, want ErrTurnConflict", err)
+				}
+
+				turns, err := games.ListTurns(ctx, testNamespace, testChannelID, 1, g.Round)
+				if err != nil {
+					t.Fatalf("failed to list turns: %v", err)
+				}
+				if len(turns) != 1 {
+					t.Errorf("%d turns recorded, want 1", len(turns))
+				}
+			})
+		}
+	}
+}
+
+func TestRepositoryListGamesRosters(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			games := storage.open(t)
+			ctx := context.Background()
+
+			for potatoID := 1; potatoID <= 3; potatoID++ {
+				if _, err := games.CreateNewGame(ctx, testNamespace, testRoomID, testChannelID, potatoID, "hot", "a", 1); err != nil {
+					t.Fatalf("failed to create game: %v", err)
+				}
+			}
+
+			if _, err := games.AssignTeams(ctx, testNamespace, testChannelID, 1, 0, map[string]string{"a": "red", "b": "blue"}); err != nil {
+				t.Fatalf("failed to assign teams: %v", err)
+			}
+			if _, err := games.AssignPlayers(ctx, testNamespace, testChannelID, 2, 0, []string{"a", "b", "c"}); err != nil {
+				t.Fatalf("failed to assign players: %v", err)
+			}
+
+			// The roster of an earlier round is left out once a game is restarted.
+			if _, err := games.AssignPlayers(ctx, testNamespace, testChannelID, 3, 0, []string{"a", "d"}); err != nil {
+				t.Fatalf("failed to assign players: %v", err)
+			}
+			if _, err := games.EndGame(ctx, testNamespace, testChannelID, 3, 0); err != nil {
+				t.Fatalf("failed to end game: %v", err)
+			}
+			if _, err := games.RestartGame(ctx, testNamespace, testChannelID, 3, 0, "hot", "a", 2); err != nil {
+				t.Fatalf("failed to restart game: %v", err)
+			}
+
+			listed, err := games.ListGames(ctx, testNamespace, testChannelID)
+			if err != nil {
+				t.Fatalf("failed to list games: %v", err)
+			}
+
+			ongoing, err := games.ListOngoingGames(ctx)
+			if err != nil {
+				t.Fatalf("failed to list ongoing games: %v", err)
+			}
+			sort.Slice(ongoing, func(i, j int) bool {
+				return ongoing[i].PotatoID < ongoing[j].PotatoID
+			})
+
+			for name, gs := range map[string][]*Game{"listed": listed, "ongoing": ongoing} {
+				if len(gs) != 3 {
+					t.Fatalf("%s %d games, want 3", name, len(gs))
+				}
+				if want := map[string]string{"a": "red", "b": "blue"}; !reflect.DeepEqual(gs[0].Teams, want) || len(gs[0].Players) != 0 {
+					t.Errorf("%s team game with teams %v and players %v, want teams %v", name, gs[0].Teams, gs[0].Players, want)
+				}
+				if want := []string{"a", "b", "c"}; !reflect.DeepEqual(gs[1].Players, want) || len(gs[1].Teams) != 0 {
+					t.Errorf("%s elimination game with teams %v and players %v, want players %v", name, gs[1].Teams, gs[1].Players, want)
+				}
+				if gs[2].TeamGame() || gs[2].EliminationGame() {
+					t.Errorf("%s restarted game with teams %v and players %v, want none", name, gs[2].Teams, gs[2].Players)
+				}
+			}
+		})
+	}
+}
+
+func TestRepositoryGetRecord(t *testing.T) {
+	toss := func(actor, target string) *Turn {
+		return &Turn{Action: ActionToss, ActorUserID: actor, TargetUserID: target}
+	}
+	explode := func(actor, target string) *Turn {
+		return &Turn{Action: ActionToss, ActorUserID: actor, TargetUserID: target, Exploded: true}
+	}
+
+	played := [][]*Turn{
+		{toss("a", "b"), toss("b", "c"), toss("c", "b"), toss("b", "a"), explode("a", "b")},
+		{explode("c", "b")},
+		{explode("c", "a")},
+	}
+
+	tests := []struct {
+		userID string
+		want   Record
+	}{
+		{userID: "a", want: Record{GamesPlayed: 2, FavouriteTargetUserID: "b", FavouriteTargetTosses: 2, NemesisUserID: "c", NemesisKills: 1}},
+		{userID: "b", want: Record{GamesPlayed: 2, FavouriteTargetUserID: "a", FavouriteTargetTosses: 1, NemesisUserID: "a", NemesisKills: 1}},
+		{userID: "c", want: Record{GamesPlayed: 3, FavouriteTargetUserID: "b", FavouriteTargetTosses: 2}},
+		{userID: "d", want: Record{}},
+	}
+
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			games := storage.open(t)
+			ctx := context.Background()
+
+			for i, turns := range played {
+				potatoID := i + 1
+				g, err := games.CreateNewGame(ctx, testNamespace, testRoomID, testChannelID, potatoID, "hot", turns[0].ActorUserID, 1)
+				if err != nil {
+					t.Fatalf("failed to create game: %v", err)
+				}
+
+				for _, turn := range turns {
+					turn.Round = g.Round
+					turn.Turn = g.Turns + 1
+
+					g, err = games.PlayTurn(ctx, testNamespace, testChannelID, potatoID, &Play{
+						ExpectedHolderUserID: g.HolderUserID,
+						ExpectedTurns:        g.Turns,
+						HolderUserID:         turn.TargetUserID,
+						Turn:                 turn,
+					})
+					if err != nil {
+						t.Fatalf("failed to play turn %d of game %d: %v", turn.Turn, potatoID, err)
+					}
+				}
+			}
+
+			for _, tt := range tests {
+				record, err := games.GetRecord(ctx, testNamespace, testRoomID, tt.userID)
+				if err != nil {
+					t.Fatalf("failed to get record of %s: %v", tt.userID, err)
+				}
+				if *record != tt.want {
+					t.Errorf("record of %s = %+v, want %+v", tt.userID, *record, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -7,20 +7,35 @@ import (
 	"context"
 )
 
-const endGame = `-- name: EndGame :one
+const advanceTurn = `-- name: AdvanceTurn :one
 UPDATE games
-SET finished = true
-WHERE namespace = $1 AND channel_id = $2
-RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at
+SET holder_user_id = $1, heat_level = heat_level + $2, turns = turns + 1, finished = $3, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $4 AND channel_id = $5 AND potato_id = $6 AND holder_user_id = $7 AND turns = $8 AND finished = false
+RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id
 `
 
-type EndGameParams struct {
-	Namespace string
-	ChannelID string
+type AdvanceTurnParams struct {
+	HolderUserID         string
+	HeatIncrease         int32
+	Finished             bool
+	Namespace            string
+	ChannelID            string
+	PotatoID             int32
+	ExpectedHolderUserID string
+	ExpectedTurns        int32
 }
 
-func (q *Queries) EndGame(ctx context.Context, arg EndGameParams) (Game, error) {
-	row := q.db.QueryRowContext(ctx, endGame, arg.Namespace, arg.ChannelID)
+func (q *Queries) AdvanceTurn(ctx context.Context, arg AdvanceTurnParams) (Game, error) {
+	row := q.db.QueryRowContext(ctx, advanceTurn,
+		arg.HolderUserID,
+		arg.HeatIncrease,
+		arg.Finished,
+		arg.Namespace,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.ExpectedHolderUserID,
+		arg.ExpectedTurns,
+	)
 	var i Game
 	err := row.Scan(
 		&i.Namespace,
@@ -32,53 +47,70 @@ func (q *Queries) EndGame(ctx context.Context, arg EndGameParams) (Game, error)
 		&i.Turns,
 		&i.Finished,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Seed,
+		&i.Round,
+		&i.PotatoID,
 	)
 	return i, err
 }
 
-const getGame = `-- name: GetGame :one
-SELECT namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at FROM games
+const clearLobby = `-- name: ClearLobby :exec
+DELETE FROM game_lobbies
 WHERE namespace = $1 AND channel_id = $2
-LIMIT 1
 `
 
-type GetGameParams struct {
+type ClearLobbyParams struct {
 	Namespace string
 	ChannelID string
 }
 
-func (q *Queries) GetGame(ctx context.Context, arg GetGameParams) (Game, error) {
-	row := q.db.QueryRowContext(ctx, getGame, arg.Namespace, arg.ChannelID)
-	var i Game
-	err := row.Scan(
-		&i.Namespace,
-		&i.RoomID,
-		&i.ChannelID,
-		&i.PotatoKind,
-		&i.HeatLevel,
-		&i.HolderUserID,
-		&i.Turns,
-		&i.Finished,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) ClearLobby(ctx context.Context, arg ClearLobbyParams) error {
+	_, err := q.db.ExecContext(ctx, clearLobby, arg.Namespace, arg.ChannelID)
+	return err
+}
+
+const countGamesPlayed = `-- name: CountGamesPlayed :one
+SELECT COUNT(*) AS games FROM (
+  SELECT DISTINCT channel_id, potato_id, round FROM game_turns
+  WHERE namespace = $1 AND room_id = $2 AND (actor_user_id = $3 OR target_user_id = $3)
+) AS played
+`
+
+type CountGamesPlayedParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) CountGamesPlayed(ctx context.Context, arg CountGamesPlayedParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGamesPlayed, arg.Namespace, arg.RoomID, arg.UserID)
+	var games int64
+	err := row.Scan(&games)
+	return games, err
 }
 
-const increaseHeatLevel = `-- name: IncreaseHeatLevel :one
+const endGame = `-- name: EndGame :one
 UPDATE games
-SET heat_level = heat_level + $3::int
-WHERE namespace = $1 AND channel_id = $2
-RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at
+SET finished = true, turns = turns + 1, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND turns = $4 AND finished = false
+RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id
 `
 
-type IncreaseHeatLevelParams struct {
+type EndGameParams struct {
 	Namespace string
 	ChannelID string
-	Amount    int32
+	PotatoID  int32
+	Turns     int32
 }
 
-func (q *Queries) IncreaseHeatLevel(ctx context.Context, arg IncreaseHeatLevelParams) (Game, error) {
-	row := q.db.QueryRowContext(ctx, increaseHeatLevel, arg.Namespace, arg.ChannelID, arg.Amount)
+func (q *Queries) EndGame(ctx context.Context, arg EndGameParams) (Game, error) {
+	row := q.db.QueryRowContext(ctx, endGame,
+		arg.Namespace,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Turns,
+	)
 	var i Game
 	err := row.Scan(
 		&i.Namespace,
@@ -90,24 +122,54 @@ func (q *Queries) IncreaseHeatLevel(ctx context.Context, arg IncreaseHeatLevelPa
 		&i.Turns,
 		&i.Finished,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Seed,
+		&i.Round,
+		&i.PotatoID,
 	)
 	return i, err
 }
 
-const incrementTurns = `-- name: IncrementTurns :one
-UPDATE games
-SET turns = turns + 1
-WHERE namespace = $1 AND channel_id = $2
-RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at
+const getFavouriteTarget = `-- name: GetFavouriteTarget :one
+SELECT target_user_id, COUNT(*) AS tosses FROM game_turns
+WHERE namespace = $1 AND room_id = $2 AND actor_user_id = $3 AND action = 'toss' AND target_user_id <> actor_user_id
+GROUP BY target_user_id
+ORDER BY tosses DESC, target_user_id
+LIMIT 1
+`
+
+type GetFavouriteTargetParams struct {
+	Namespace   string
+	RoomID      string
+	ActorUserID string
+}
+
+type GetFavouriteTargetRow struct {
+	TargetUserID string
+	Tosses       int64
+}
+
+func (q *Queries) GetFavouriteTarget(ctx context.Context, arg GetFavouriteTargetParams) (GetFavouriteTargetRow, error) {
+	row := q.db.QueryRowContext(ctx, getFavouriteTarget, arg.Namespace, arg.RoomID, arg.ActorUserID)
+	var i GetFavouriteTargetRow
+	err := row.Scan(&i.TargetUserID, &i.Tosses)
+	return i, err
+}
+
+const getGame = `-- name: GetGame :one
+SELECT namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id FROM games
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3
+LIMIT 1
 `
 
-type IncrementTurnsParams struct {
+type GetGameParams struct {
 	Namespace string
 	ChannelID string
+	PotatoID  int32
 }
 
-func (q *Queries) IncrementTurns(ctx context.Context, arg IncrementTurnsParams) (Game, error) {
-	row := q.db.QueryRowContext(ctx, incrementTurns, arg.Namespace, arg.ChannelID)
+func (q *Queries) GetGame(ctx context.Context, arg GetGameParams) (Game, error) {
+	row := q.db.QueryRowContext(ctx, getGame, arg.Namespace, arg.ChannelID, arg.PotatoID)
 	var i Game
 	err := row.Scan(
 		&i.Namespace,
@@ -119,25 +181,75 @@ func (q *Queries) IncrementTurns(ctx context.Context, arg IncrementTurnsParams)
 		&i.Turns,
 		&i.Finished,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Seed,
+		&i.Round,
+		&i.PotatoID,
 	)
 	return i, err
 }
 
-const updateHolder = `-- name: UpdateHolder :one
-UPDATE games
-SET holder_user_id = $3
-WHERE namespace = $1 AND channel_id = $2
-RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at
+const getNemesis = `-- name: GetNemesis :one
+SELECT actor_user_id, COUNT(*) AS kills FROM game_turns
+WHERE namespace = $1 AND room_id = $2 AND target_user_id = $3 AND action = 'toss' AND exploded = true AND actor_user_id <> target_user_id
+GROUP BY actor_user_id
+ORDER BY kills DESC, actor_user_id
+LIMIT 1
+`
+
+type GetNemesisParams struct {
+	Namespace    string
+	RoomID       string
+	TargetUserID string
+}
+
+type GetNemesisRow struct {
+	ActorUserID string
+	Kills       int64
+}
+
+func (q *Queries) GetNemesis(ctx context.Context, arg GetNemesisParams) (GetNemesisRow, error) {
+	row := q.db.QueryRowContext(ctx, getNemesis, arg.Namespace, arg.RoomID, arg.TargetUserID)
+	var i GetNemesisRow
+	err := row.Scan(&i.ActorUserID, &i.Kills)
+	return i, err
+}
+
+const insertGame = `-- name: InsertGame :one
+INSERT INTO games (
+  namespace, room_id, channel_id, potato_id, potato_kind, heat_level, holder_user_id, turns, finished, seed, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP
+) ON CONFLICT (namespace, room_id, channel_id, potato_id) DO NOTHING
+RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id
 `
 
-type UpdateHolderParams struct {
+type InsertGameParams struct {
 	Namespace    string
+	RoomID       string
 	ChannelID    string
+	PotatoID     int32
+	PotatoKind   string
+	HeatLevel    int32
 	HolderUserID string
+	Turns        int32
+	Finished     bool
+	Seed         int64
 }
 
-func (q *Queries) UpdateHolder(ctx context.Context, arg UpdateHolderParams) (Game, error) {
-	row := q.db.QueryRowContext(ctx, updateHolder, arg.Namespace, arg.ChannelID, arg.HolderUserID)
+func (q *Queries) InsertGame(ctx context.Context, arg InsertGameParams) (Game, error) {
+	row := q.db.QueryRowContext(ctx, insertGame,
+		arg.Namespace,
+		arg.RoomID,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.PotatoKind,
+		arg.HeatLevel,
+		arg.HolderUserID,
+		arg.Turns,
+		arg.Finished,
+		arg.Seed,
+	)
 	var i Game
 	err := row.Scan(
 		&i.Namespace,
@@ -149,42 +261,676 @@ func (q *Queries) UpdateHolder(ctx context.Context, arg UpdateHolderParams) (Gam
 		&i.Turns,
 		&i.Finished,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Seed,
+		&i.Round,
+		&i.PotatoID,
 	)
 	return i, err
 }
 
-const upsertGame = `-- name: UpsertGame :one
-INSERT INTO games (
-  namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished
+const insertGamePlayer = `-- name: InsertGamePlayer :exec
+INSERT INTO game_players (
+  namespace, room_id, channel_id, potato_id, round, user_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+`
+
+type InsertGamePlayerParams struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	PotatoID  int32
+	Round     int32
+	UserID    string
+}
+
+func (q *Queries) InsertGamePlayer(ctx context.Context, arg InsertGamePlayerParams) error {
+	_, err := q.db.ExecContext(ctx, insertGamePlayer,
+		arg.Namespace,
+		arg.RoomID,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
+		arg.UserID,
+	)
+	return err
+}
+
+const insertGameTeam = `-- name: InsertGameTeam :exec
+INSERT INTO game_teams (
+  namespace, room_id, channel_id, potato_id, round, user_id, team
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+`
+
+type InsertGameTeamParams struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	PotatoID  int32
+	Round     int32
+	UserID    string
+	Team      string
+}
+
+func (q *Queries) InsertGameTeam(ctx context.Context, arg InsertGameTeamParams) error {
+	_, err := q.db.ExecContext(ctx, insertGameTeam,
+		arg.Namespace,
+		arg.RoomID,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
+		arg.UserID,
+		arg.Team,
+	)
+	return err
+}
+
+const insertLobbyPlayer = `-- name: InsertLobbyPlayer :exec
+INSERT INTO game_lobbies (
+  namespace, room_id, channel_id, user_id
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (namespace, channel_id, user_id) DO NOTHING
+`
+
+type InsertLobbyPlayerParams struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	UserID    string
+}
+
+func (q *Queries) InsertLobbyPlayer(ctx context.Context, arg InsertLobbyPlayerParams) error {
+	_, err := q.db.ExecContext(ctx, insertLobbyPlayer,
+		arg.Namespace,
+		arg.RoomID,
+		arg.ChannelID,
+		arg.UserID,
+	)
+	return err
+}
+
+const insertTurn = `-- name: InsertTurn :exec
+INSERT INTO game_turns (
+  namespace, room_id, channel_id, potato_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
-) ON CONFLICT (namespace, room_id, channel_id)
-  DO UPDATE SET potato_kind = $4, heat_level = $5, holder_user_id = $6, turns = $7, finished = $8
-  WHERE games.namespace = $1 AND games.room_id = $2 AND games.channel_id = $3
-RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+`
+
+type InsertTurnParams struct {
+	Namespace     string
+	RoomID        string
+	ChannelID     string
+	PotatoID      int32
+	Round         int32
+	Turn          int32
+	Action        string
+	ActorUserID   string
+	TargetUserID  string
+	HeatLevel     int32
+	ExplodeChance int32
+	Exploded      bool
+}
+
+func (q *Queries) InsertTurn(ctx context.Context, arg InsertTurnParams) error {
+	_, err := q.db.ExecContext(ctx, insertTurn,
+		arg.Namespace,
+		arg.RoomID,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
+		arg.Turn,
+		arg.Action,
+		arg.ActorUserID,
+		arg.TargetUserID,
+		arg.HeatLevel,
+		arg.ExplodeChance,
+		arg.Exploded,
+	)
+	return err
+}
+
+const listChannelActivity = `-- name: ListChannelActivity :many
+SELECT namespace, room_id, channel_id, user_id, active_at FROM channel_activity
+WHERE namespace = $1 AND channel_id = $2
+ORDER BY active_at DESC, user_id
+`
+
+type ListChannelActivityParams struct {
+	Namespace string
+	ChannelID string
+}
+
+func (q *Queries) ListChannelActivity(ctx context.Context, arg ListChannelActivityParams) ([]ChannelActivity, error) {
+	rows, err := q.db.QueryContext(ctx, listChannelActivity, arg.Namespace, arg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ChannelActivity
+	for rows.Next() {
+		var i ChannelActivity
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.UserID,
+			&i.ActiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChannelGamePlayers = `-- name: ListChannelGamePlayers :many
+SELECT game_players.namespace, game_players.channel_id, game_players.potato_id, game_players.user_id FROM game_players
+JOIN games ON games.namespace = game_players.namespace AND games.channel_id = game_players.channel_id AND games.potato_id = game_players.potato_id AND games.round = game_players.round
+WHERE games.namespace = $1 AND games.channel_id = $2
+ORDER BY game_players.user_id
+`
+
+type ListChannelGamePlayersParams struct {
+	Namespace string
+	ChannelID string
+}
+
+type ListChannelGamePlayersRow struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	UserID    string
+}
+
+func (q *Queries) ListChannelGamePlayers(ctx context.Context, arg ListChannelGamePlayersParams) ([]ListChannelGamePlayersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listChannelGamePlayers, arg.Namespace, arg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListChannelGamePlayersRow
+	for rows.Next() {
+		var i ListChannelGamePlayersRow
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.ChannelID,
+			&i.PotatoID,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChannelGameTeams = `-- name: ListChannelGameTeams :many
+SELECT game_teams.namespace, game_teams.channel_id, game_teams.potato_id, game_teams.user_id, game_teams.team FROM game_teams
+JOIN games ON games.namespace = game_teams.namespace AND games.channel_id = game_teams.channel_id AND games.potato_id = game_teams.potato_id AND games.round = game_teams.round
+WHERE games.namespace = $1 AND games.channel_id = $2
+ORDER BY game_teams.team, game_teams.user_id
+`
+
+type ListChannelGameTeamsParams struct {
+	Namespace string
+	ChannelID string
+}
+
+type ListChannelGameTeamsRow struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	UserID    string
+	Team      string
+}
+
+func (q *Queries) ListChannelGameTeams(ctx context.Context, arg ListChannelGameTeamsParams) ([]ListChannelGameTeamsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listChannelGameTeams, arg.Namespace, arg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListChannelGameTeamsRow
+	for rows.Next() {
+		var i ListChannelGameTeamsRow
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.ChannelID,
+			&i.PotatoID,
+			&i.UserID,
+			&i.Team,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGamePlayers = `-- name: ListGamePlayers :many
+SELECT namespace, room_id, channel_id, potato_id, round, user_id FROM game_players
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+ORDER BY user_id
+`
+
+type ListGamePlayersParams struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	Round     int32
+}
+
+func (q *Queries) ListGamePlayers(ctx context.Context, arg ListGamePlayersParams) ([]GamePlayer, error) {
+	rows, err := q.db.QueryContext(ctx, listGamePlayers,
+		arg.Namespace,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GamePlayer
+	for rows.Next() {
+		var i GamePlayer
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.PotatoID,
+			&i.Round,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGames = `-- name: ListGames :many
+SELECT namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id FROM games
+WHERE namespace = $1 AND channel_id = $2
+ORDER BY potato_id
+`
+
+type ListGamesParams struct {
+	Namespace string
+	ChannelID string
+}
+
+func (q *Queries) ListGames(ctx context.Context, arg ListGamesParams) ([]Game, error) {
+	rows, err := q.db.QueryContext(ctx, listGames, arg.Namespace, arg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Game
+	for rows.Next() {
+		var i Game
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.PotatoKind,
+			&i.HeatLevel,
+			&i.HolderUserID,
+			&i.Turns,
+			&i.Finished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Seed,
+			&i.Round,
+			&i.PotatoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGameTeams = `-- name: ListGameTeams :many
+SELECT namespace, room_id, channel_id, round, user_id, team, potato_id FROM game_teams
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+ORDER BY team, user_id
+`
+
+type ListGameTeamsParams struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	Round     int32
+}
+
+func (q *Queries) ListGameTeams(ctx context.Context, arg ListGameTeamsParams) ([]GameTeam, error) {
+	rows, err := q.db.QueryContext(ctx, listGameTeams,
+		arg.Namespace,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GameTeam
+	for rows.Next() {
+		var i GameTeam
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.Round,
+			&i.UserID,
+			&i.Team,
+			&i.PotatoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLobbyPlayers = `-- name: ListLobbyPlayers :many
+SELECT namespace, room_id, channel_id, user_id, joined_at FROM game_lobbies
+WHERE namespace = $1 AND channel_id = $2
+ORDER BY joined_at, user_id
+`
+
+type ListLobbyPlayersParams struct {
+	Namespace string
+	ChannelID string
+}
+
+func (q *Queries) ListLobbyPlayers(ctx context.Context, arg ListLobbyPlayersParams) ([]GameLobby, error) {
+	rows, err := q.db.QueryContext(ctx, listLobbyPlayers, arg.Namespace, arg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GameLobby
+	for rows.Next() {
+		var i GameLobby
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.UserID,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOngoingGamePlayers = `-- name: ListOngoingGamePlayers :many
+SELECT game_players.namespace, game_players.channel_id, game_players.potato_id, game_players.user_id FROM game_players
+JOIN games ON games.namespace = game_players.namespace AND games.channel_id = game_players.channel_id AND games.potato_id = game_players.potato_id AND games.round = game_players.round
+WHERE games.finished = false
+ORDER BY game_players.user_id
+`
+
+type ListOngoingGamePlayersRow struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	UserID    string
+}
+
+func (q *Queries) ListOngoingGamePlayers(ctx context.Context) ([]ListOngoingGamePlayersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOngoingGamePlayers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOngoingGamePlayersRow
+	for rows.Next() {
+		var i ListOngoingGamePlayersRow
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.ChannelID,
+			&i.PotatoID,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOngoingGames = `-- name: ListOngoingGames :many
+SELECT namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id FROM games
+WHERE finished = false
+`
+
+func (q *Queries) ListOngoingGames(ctx context.Context) ([]Game, error) {
+	rows, err := q.db.QueryContext(ctx, listOngoingGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Game
+	for rows.Next() {
+		var i Game
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.PotatoKind,
+			&i.HeatLevel,
+			&i.HolderUserID,
+			&i.Turns,
+			&i.Finished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Seed,
+			&i.Round,
+			&i.PotatoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOngoingGameTeams = `-- name: ListOngoingGameTeams :many
+SELECT game_teams.namespace, game_teams.channel_id, game_teams.potato_id, game_teams.user_id, game_teams.team FROM game_teams
+JOIN games ON games.namespace = game_teams.namespace AND games.channel_id = game_teams.channel_id AND games.potato_id = game_teams.potato_id AND games.round = game_teams.round
+WHERE games.finished = false
+ORDER BY game_teams.team, game_teams.user_id
+`
+
+type ListOngoingGameTeamsRow struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	UserID    string
+	Team      string
+}
+
+func (q *Queries) ListOngoingGameTeams(ctx context.Context) ([]ListOngoingGameTeamsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOngoingGameTeams)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOngoingGameTeamsRow
+	for rows.Next() {
+		var i ListOngoingGameTeamsRow
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.ChannelID,
+			&i.PotatoID,
+			&i.UserID,
+			&i.Team,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTurns = `-- name: ListTurns :many
+SELECT namespace, room_id, channel_id, round, turn, action, actor_user_id, target_user_id, heat_level, explode_chance, exploded, created_at, potato_id FROM game_turns
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+ORDER BY turn
+`
+
+type ListTurnsParams struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+	Round     int32
+}
+
+func (q *Queries) ListTurns(ctx context.Context, arg ListTurnsParams) ([]GameTurn, error) {
+	rows, err := q.db.QueryContext(ctx, listTurns,
+		arg.Namespace,
+		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GameTurn
+	for rows.Next() {
+		var i GameTurn
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.ChannelID,
+			&i.Round,
+			&i.Turn,
+			&i.Action,
+			&i.ActorUserID,
+			&i.TargetUserID,
+			&i.HeatLevel,
+			&i.ExplodeChance,
+			&i.Exploded,
+			&i.CreatedAt,
+			&i.PotatoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restartGame = `-- name: RestartGame :one
+UPDATE games
+SET potato_kind = $5, heat_level = $6, holder_user_id = $7, turns = $8, finished = $9, seed = $10, round = round + 1, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND round = $4
+RETURNING namespace, room_id, channel_id, potato_kind, heat_level, holder_user_id, turns, finished, created_at, updated_at, seed, round, potato_id
 `
 
-type UpsertGameParams struct {
+type RestartGameParams struct {
 	Namespace    string
-	RoomID       string
 	ChannelID    string
+	PotatoID     int32
+	Round        int32
 	PotatoKind   string
 	HeatLevel    int32
 	HolderUserID string
 	Turns        int32
 	Finished     bool
+	Seed         int64
 }
 
-func (q *Queries) UpsertGame(ctx context.Context, arg UpsertGameParams) (Game, error) {
-	row := q.db.QueryRowContext(ctx, upsertGame,
+func (q *Queries) RestartGame(ctx context.Context, arg RestartGameParams) (Game, error) {
+	row := q.db.QueryRowContext(ctx, restartGame,
 		arg.Namespace,
-		arg.RoomID,
 		arg.ChannelID,
+		arg.PotatoID,
+		arg.Round,
 		arg.PotatoKind,
 		arg.HeatLevel,
 		arg.HolderUserID,
 		arg.Turns,
 		arg.Finished,
+		arg.Seed,
 	)
 	var i Game
 	err := row.Scan(
@@ -197,6 +943,53 @@ func (q *Queries) UpsertGame(ctx context.Context, arg UpsertGameParams) (Game, e
 		&i.Turns,
 		&i.Finished,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Seed,
+		&i.Round,
+		&i.PotatoID,
 	)
 	return i, err
 }
+
+const retireGame = `-- name: RetireGame :exec
+UPDATE games
+SET finished = true, updated_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND channel_id = $2 AND potato_id = $3 AND finished = false
+`
+
+type RetireGameParams struct {
+	Namespace string
+	ChannelID string
+	PotatoID  int32
+}
+
+func (q *Queries) RetireGame(ctx context.Context, arg RetireGameParams) error {
+	_, err := q.db.ExecContext(ctx, retireGame, arg.Namespace, arg.ChannelID, arg.PotatoID)
+	return err
+}
+
+const upsertChannelActivity = `-- name: UpsertChannelActivity :exec
+INSERT INTO channel_activity (
+  namespace, room_id, channel_id, user_id, active_at
+) VALUES (
+  $1, $2, $3, $4, CURRENT_TIMESTAMP
+) ON CONFLICT (namespace, channel_id, user_id)
+  DO UPDATE SET active_at = CURRENT_TIMESTAMP
+`
+
+type UpsertChannelActivityParams struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	UserID    string
+}
+
+func (q *Queries) UpsertChannelActivity(ctx context.Context, arg UpsertChannelActivityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertChannelActivity,
+		arg.Namespace,
+		arg.RoomID,
+		arg.ChannelID,
+		arg.UserID,
+	)
+	return err
+}
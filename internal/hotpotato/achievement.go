@@ -0,0 +1,137 @@
+package hotpotato
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+)
+
+// Achievement is unlocked by a user on a turn that meets all of its set
+// criteria.
+type Achievement struct {
+	ID          string
+	Name        string
+	Description string
+
+	// Action requires the turn to be of the given action, played by the user.
+	Action game.Action
+
+	// Count requires the user to have played at least the given number of turns
+	// matching the Action and Survived criteria in the game so far.
+	Count int
+
+	// Survived requires the potato not to explode on the turn.
+	Survived bool
+
+	// Exploded requires the potato to explode in the user's hands on the turn.
+	Exploded bool
+
+	// FirstTouch requires the turn to be the first time that the user has held
+	// the potato in the game.
+	FirstTouch bool
+
+	// PotatoKind requires the game to be played with the given kind of potato.
+	PotatoKind string
+
+	// HeatLevel requires the potato to have reached at least the given heat
+	// level on the turn.
+	HeatLevel int
+}
+
+var DefaultAchievements = []Achievement{
+	{ID: "butterfingers", Name: "Butterfingers", Description: "Had a potato explode on first touch", Exploded: true, FirstTouch: true},
+	{ID: "master-thief", Name: "Master Thief", Description: "Survived 10 steals in one game", Action: game.ActionSteal, Count: 10, Survived: true},
+	{ID: "well-done", Name: "Well Done", Description: "Cooked a burnt potato five times in one game", Action: game.ActionCook, Count: 5, PotatoKind: "burnt"},
+	{ID: "hot-hands", Name: "Hot Hands", Description: "Cooked a potato up to heat level 5", Action: game.ActionCook, HeatLevel: 5},
+}
+
+// Unlock is an achievement unlocked by a user on a turn.
+type Unlock struct {
+	UserID      string
+	Achievement Achievement
+}
+
+// Unlocked reports whether the user meets the achievement's criteria on the
+// latest of the given turns.
+func (a Achievement) Unlocked(userID, potatoKind string, turns []*game.Turn) bool {
+	if len(turns) == 0 {
+		return false
+	}
+	turn := turns[len(turns)-1]
+	holds := turnHolder(turn) == userID
+
+	switch {
+	case a.Action != "" && (turn.Action != a.Action || turn.ActorUserID != userID):
+		return false
+	case a.Survived && turn.Exploded:
+		return false
+	case a.Exploded && !(turn.Exploded && holds):
+		return false
+	case a.PotatoKind != "" && potatoKind != a.PotatoKind:
+		return false
+	case turn.HeatLevel < a.HeatLevel:
+		return false
+	}
+
+	if a.FirstTouch {
+		if !holds {
+			return false
+		}
+		for _, earlier := range turns[:len(turns)-1] {
+			if earlier.ActorUserID == userID || turnHolder(earlier) == userID {
+				return false
+			}
+		}
+	}
+
+	if a.Count > 0 {
+		var count int
+		for _, t := range turns {
+			if t.Action == a.Action && t.ActorUserID == userID && !(a.Survived && t.Exploded) {
+				count++
+			}
+		}
+		if count < a.Count {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (gm *GameMaster) unlockAchievements(ctx context.Context, g *game.Game, turn *game.Turn) ([]Unlock, error) {
+	if len(gm.achievements) == 0 {
+		return nil, nil
+	}
+
+	turns, err := gm.games.ListTurns(ctx, g.Namespace, g.ChannelID, g.PotatoID, g.Round)
+	if err != nil {
+		return nil, fmt.Errorf("error listing turns: %w", err)
+	}
+
+	users := []string{turn.ActorUserID}
+	if holder := turnHolder(turn); holder != turn.ActorUserID {
+		users = append(users, holder)
+	}
+
+	var unlocks []Unlock
+	for _, achievement := range gm.achievements {
+		for _, userID := range users {
+			if !achievement.Unlocked(userID, g.PotatoKind, turns) {
+				continue
+			}
+
+			unlocked, err := gm.rooms.UnlockAchievement(ctx, g.Namespace, g.RoomID, userID, achievement.ID)
+			if err != nil {
+				return unlocks, fmt.Errorf("error unlocking achievement '%s': %w", achievement.ID, err)
+			}
+
+			if unlocked {
+				unlocks = append(unlocks, Unlock{UserID: userID, Achievement: achievement})
+			}
+		}
+	}
+
+	return unlocks, nil
+}
@@ -0,0 +1,68 @@
+package hotpotato
+
+import (
+	"testing"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+)
+
+func TestAchievementUnlocked(t *testing.T) {
+	achievements := make(map[string]Achievement, len(DefaultAchievements))
+	for _, achievement := range DefaultAchievements {
+		achievements[achievement.ID] = achievement
+	}
+
+	toss := func(actor, target string, exploded bool) *game.Turn {
+		return &game.Turn{Action: game.ActionToss, ActorUserID: actor, TargetUserID: target, Exploded: exploded}
+	}
+	steal := func(actor, target string, exploded bool) *game.Turn {
+		return &game.Turn{Action: game.ActionSteal, ActorUserID: actor, TargetUserID: target, Exploded: exploded}
+	}
+	cook := func(actor string, heatLevel int) *game.Turn {
+		return &game.Turn{Action: game.ActionCook, ActorUserID: actor, TargetUserID: actor, HeatLevel: heatLevel}
+	}
+	repeat := func(n int, turn func() *game.Turn) []*game.Turn {
+		turns := make([]*game.Turn, n)
+		for i := range turns {
+			turns[i] = turn()
+		}
+		return turns
+	}
+
+	tests := []struct {
+		name        string
+		achievement string
+		userID      string
+		potatoKind  string
+		turns       []*game.Turn
+		want        bool
+	}{
+		{name: "no turns", achievement: "butterfingers", userID: "b", turns: nil},
+		{name: "butterfingers", achievement: "butterfingers", userID: "b", turns: []*game.Turn{toss("a", "b", true)}, want: true},
+		{name: "butterfingers survived", achievement: "butterfingers", userID: "b", turns: []*game.Turn{toss("a", "b", false)}},
+		{name: "butterfingers held before", achievement: "butterfingers", userID: "b", turns: []*game.Turn{toss("a", "b", false), toss("b", "a", false), toss("a", "b", true)}},
+		{name: "butterfingers tosser", achievement: "butterfingers", userID: "a", turns: []*game.Turn{toss("a", "b", true)}},
+		{name: "master thief", achievement: "master-thief", userID: "a", turns: repeat(10, func() *game.Turn { return steal("a", "b", false) }), want: true},
+		{name: "master thief too few", achievement: "master-thief", userID: "a", turns: repeat(9, func() *game.Turn { return steal("a", "b", false) })},
+		{name: "master thief exploded", achievement: "master-thief", userID: "a", turns: append(repeat(10, func() *game.Turn { return steal("a", "b", false) }), steal("a", "b", true))},
+		{name: "master thief other thief", achievement: "master-thief", userID: "b", turns: repeat(10, func() *game.Turn { return steal("a", "b", false) })},
+		{name: "well done", achievement: "well-done", userID: "a", potatoKind: "burnt", turns: repeat(5, func() *game.Turn { return cook("a", 1) }), want: true},
+		{name: "well done other potato", achievement: "well-done", userID: "a", potatoKind: "hot", turns: repeat(5, func() *game.Turn { return cook("a", 1) })},
+		{name: "hot hands", achievement: "hot-hands", userID: "a", turns: []*game.Turn{cook("a", 5)}, want: true},
+		{name: "hot hands too cold", achievement: "hot-hands", userID: "a", turns: []*game.Turn{cook("a", 4)}},
+		{name: "hot hands wrong action", achievement: "hot-hands", userID: "a", turns: []*game.Turn{{Action: game.ActionToss, ActorUserID: "a", TargetUserID: "b", HeatLevel: 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			achievement, ok := achievements[tt.achievement]
+			if !ok {
+				t.Fatalf("achievement %s not found", tt.achievement)
+			}
+
+			if got := achievement.Unlocked(tt.userID, tt.potatoKind, tt.turns); got != tt.want {
+				t.Errorf("Unlocked() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package hotpotato
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+)
+
+var (
+	throttledActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "throttled_actions_total",
+		Help: "Total number of actions rejected because the user was on cooldown.",
+	}, []string{"action"})
+)
+
+func init() {
+	prometheus.MustRegister(throttledActions)
+}
+
+// Cooldowns throttles how often each user can perform an action in a room.
+type Cooldowns struct {
+	windows map[game.Action]time.Duration
+	longest time.Duration
+
+	mu        sync.Mutex
+	last      map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewCooldowns(windows map[game.Action]time.Duration) *Cooldowns {
+	var longest time.Duration
+	for _, window := range windows {
+		if window > longest {
+			longest = window
+		}
+	}
+
+	return &Cooldowns{
+		windows: windows,
+		longest: longest,
+		last:    make(map[string]time.Time),
+	}
+}
+
+// Allow reserves the user's action, returning a CooldownError if they are
+// still cooling down from it, or a function to release an action that failed.
+func (c *Cooldowns) Allow(namespace, roomID, userID string, action game.Action) (func(), error) {
+	window := c.windows[action]
+	if window <= 0 {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.sweep(now)
+
+	key := cooldownKey(namespace, roomID, userID, action)
+	if last, ok := c.last[key]; ok {
+		if retryAfter := last.Add(window).Sub(now); retryAfter > 0 {
+			throttledActions.WithLabelValues(string(action)).Inc()
+			return nil, &CooldownError{Action: action, RetryAfter: retryAfter}
+		}
+	}
+	c.last[key] = now
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.last[key].Equal(now) {
+			delete(c.last, key)
+		}
+	}, nil
+}
+
+func (c *Cooldowns) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.longest {
+		return
+	}
+
+	for key, last := range c.last {
+		if now.Sub(last) >= c.longest {
+			delete(c.last, key)
+		}
+	}
+	c.lastSweep = now
+}
+
+func cooldownKey(namespace, roomID, userID string, action game.Action) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, roomID, userID, action)
+}
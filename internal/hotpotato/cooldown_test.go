@@ -0,0 +1,84 @@
+package hotpotato
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+)
+
+func TestCooldownsAllow(t *testing.T) {
+	windows := map[game.Action]time.Duration{
+		game.ActionToss:  time.Minute,
+		game.ActionSteal: time.Nanosecond,
+	}
+
+	tests := []struct {
+		name      string
+		first     game.Action
+		release   bool
+		userID    string
+		second    game.Action
+		throttled bool
+	}{
+		{name: "same action", first: game.ActionToss, userID: "a", second: game.ActionToss, throttled: true},
+		{name: "released", first: game.ActionToss, release: true, userID: "a", second: game.ActionToss},
+		{name: "other user", first: game.ActionToss, userID: "b", second: game.ActionToss},
+		{name: "other action", first: game.ActionToss, userID: "a", second: game.ActionCook},
+		{name: "no window", first: game.ActionCook, userID: "a", second: game.ActionCook},
+		{name: "window passed", first: game.ActionSteal, userID: "a", second: game.ActionSteal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCooldowns(windows)
+
+			release, err := c.Allow(testNamespace, testRoomID, "a", tt.first)
+			if err != nil {
+				t.Fatalf("first action returned %v", err)
+			}
+			if tt.release {
+				release()
+			}
+			time.Sleep(time.Millisecond)
+
+			_, err = c.Allow(testNamespace, testRoomID, tt.userID, tt.second)
+			if throttled := errors.As(err, new(*CooldownError)); throttled != tt.throttled {
+				t.Errorf("second action returned %v, want throttled %t", err, tt.throttled)
+			}
+		})
+	}
+}
+
+func TestCooldownsAllowConcurrently(t *testing.T) {
+	const attempts = 16
+
+	c := NewCooldowns(map[game.Action]time.Duration{game.ActionToss: time.Minute})
+
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.Allow(testNamespace, testRoomID, "a", game.ActionToss)
+		}(i)
+	}
+	wg.Wait()
+
+	var allowed int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			allowed++
+		case errors.As(err, new(*CooldownError)):
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if allowed != 1 {
+		t.Errorf("%d actions allowed at once, want 1", allowed)
+	}
+}
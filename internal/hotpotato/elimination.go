@@ -0,0 +1,83 @@
+package hotpotato
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// EliminationUpdate is the progress of an elimination game after the potato
+// exploded on one of its players.
+type EliminationUpdate struct {
+	EliminatedUserID string
+
+	// Players are the players left standing after the elimination.
+	Players []string
+
+	// HolderUserID is the player handed a fresh potato for the game to go on
+	// with, if there is more than one player left standing.
+	HolderUserID string
+
+	// WinnerUserID is the last player standing, once the game has been won.
+	WinnerUserID string
+}
+
+// checkEliminationToss enforces that potatoes in an elimination game are only
+// tossed to players that are still in it.
+func checkEliminationToss(g *game.Game, targetUserID string) error {
+	if !g.EliminationGame() || g.Playing(targetUserID) {
+		return nil
+	}
+
+	return &NotPlayingError{targetUserID}
+}
+
+func (gm *GameMaster) eliminate(ctx context.Context, logger log.Logger, r *room.Room, g *game.Game) (*EliminationUpdate, *game.Game, error) {
+	if !g.Finished || !g.EliminationGame() {
+		return nil, nil, nil
+	}
+
+	var players []string
+	for _, userID := range g.Players {
+		if userID != g.HolderUserID {
+			players = append(players, userID)
+		}
+	}
+
+	update := &EliminationUpdate{
+		EliminatedUserID: g.HolderUserID,
+		Players:          players,
+	}
+	level.Info(logger).Log("event", "game.player.eliminated", "players", len(players))
+
+	switch len(players) {
+	case 0:
+		return update, nil, nil
+	case 1:
+		if err := gm.rooms.IncrementWins(ctx, g.Namespace, g.RoomID, players[0]); err != nil {
+			return nil, nil, fmt.Errorf("error incrementing wins: %w", err)
+		}
+		level.Info(logger).Log("event", "game.elimination.won")
+
+		update.WinnerUserID = players[0]
+		return update, nil, nil
+	}
+
+	next, err := gm.startGame(ctx, logger, r, []*game.Game{g}, g.ChannelID, g.PotatoID, players[gm.random.Intn(len(players))])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next, err = gm.games.AssignPlayers(ctx, next.Namespace, next.ChannelID, next.PotatoID, next.Round, players)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error assigning players: %w", err)
+	}
+
+	update.HolderUserID = next.HolderUserID
+	return update, next, nil
+}
@@ -1,11 +1,34 @@
 package hotpotato
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+)
 
 var (
 	ErrNoOngoingGame      = errors.New("no ongoing game found")
+	ErrNoGameHistory      = errors.New("no game history found")
+	ErrPotatoNotFound     = errors.New("potato not found in channel")
 	ErrInvalidPotatoKind  = errors.New("unrecognised potato kind")
 	ErrSelfStealUnallowed = errors.New("cannot steal potato from self")
+	ErrStealDisabled      = errors.New("stealing is disabled in room")
+	ErrCookCapReached     = errors.New("potato cannot be cooked any hotter")
+	ErrSeasonNotFound     = errors.New("season not found")
+	ErrSeasonAlreadyEnded = errors.New("season already ended")
+	ErrGameInProgress     = errors.New("game already in progress")
+	ErrNotEnoughPlayers   = errors.New("not enough players to form teams")
+	ErrTeammateToss       = errors.New("cannot toss potato to teammate")
+	ErrNoTournament       = errors.New("no tournament in progress")
+	ErrTournamentStarted  = errors.New("tournament already started")
+	ErrNotEnoughEntrants  = errors.New("not enough players registered for tournament")
+	ErrHeatNotFound       = errors.New("heat not found in tournament")
+	ErrLobbyTooSmall      = errors.New("not enough players in lobby")
+	ErrNoTossTarget       = errors.New("no one to toss potato to")
+	ErrItemNotFound       = errors.New("unrecognised item")
+	ErrItemNotHeld        = errors.New("item not held by user")
+	ErrItemAlreadyArmed   = errors.New("item already armed by user")
 )
 
 type NotHolderError struct {
@@ -15,3 +38,56 @@ type NotHolderError struct {
 func (e *NotHolderError) Error() string {
 	return "user does not current hold the potato"
 }
+
+type NotOnTeamError struct {
+	UserID string
+}
+
+func (e *NotOnTeamError) Error() string {
+	return "user is not on a team in the game"
+}
+
+type NotInHeatError struct {
+	UserID string
+}
+
+func (e *NotInHeatError) Error() string {
+	return "user is not playing in the tournament heat"
+}
+
+type NotPlayingError struct {
+	UserID string
+}
+
+func (e *NotPlayingError) Error() string {
+	return "user is not left standing in the elimination game"
+}
+
+type NotOptedInError struct {
+	UserID string
+}
+
+func (e *NotOptedInError) Error() string {
+	return "user has not opted in to play"
+}
+
+type InvalidSettingsError struct {
+	Err error
+}
+
+func (e *InvalidSettingsError) Error() string {
+	return "invalid settings: " + e.Err.Error()
+}
+
+func (e *InvalidSettingsError) Unwrap() error {
+	return e.Err
+}
+
+type CooldownError struct {
+	Action     game.Action
+	RetryAfter time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return "user is on cooldown for action"
+}
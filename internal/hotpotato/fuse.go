@@ -0,0 +1,228 @@
+package hotpotato
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+const (
+	detonateTimeout   = 10 * time.Second
+	detonationsBuffer = 64
+)
+
+// Fuse schedules potatoes to explode in their holder's hands once they have
+// been held for longer than their timeout.
+type Fuse struct {
+	timeout time.Duration
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func NewFuse(timeout time.Duration) *Fuse {
+	return &Fuse{
+		timeout: timeout,
+		timers:  make(map[string]*time.Timer),
+	}
+}
+
+// Timeout returns the default fuse timeout.
+func (f *Fuse) Timeout() time.Duration {
+	return f.timeout
+}
+
+// Light (re)starts the fuse for the given key, calling detonate once the
+// timeout has elapsed since the given time.
+func (f *Fuse) Light(key string, timeout time.Duration, since time.Time, detonate func()) {
+	if timeout <= 0 {
+		f.Snuff(key)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if timer, ok := f.timers[key]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(since.Add(timeout)), func() {
+		f.mu.Lock()
+		if f.timers[key] == timer {
+			delete(f.timers, key)
+		}
+		f.mu.Unlock()
+
+		detonate()
+	})
+	f.timers[key] = timer
+}
+
+func (f *Fuse) Snuff(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if timer, ok := f.timers[key]; ok {
+		timer.Stop()
+		delete(f.timers, key)
+	}
+}
+
+func (f *Fuse) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key, timer := range f.timers {
+		timer.Stop()
+		delete(f.timers, key)
+	}
+}
+
+type Detonation struct {
+	Namespace    string
+	RoomID       string
+	ChannelID    string
+	PotatoID     int
+	Turn         int
+	Potato       Potato
+	HolderUserID string
+	LosingTeam   string
+	Tournament   *TournamentUpdate
+	Elimination  *EliminationUpdate
+}
+
+func fuseKey(namespace, channelID string, potatoID int) string {
+	return fmt.Sprintf("%s/%s/%d", namespace, channelID, potatoID)
+}
+
+// Start rebuilds the fuses for all ongoing games and keeps them burning until
+// the given context is cancelled.
+func (gm *GameMaster) Start(ctx context.Context) error {
+	games, err := gm.games.ListOngoingGames(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list ongoing games: %w", err)
+	}
+
+	if err := gm.retireGames(ctx, gm.logger, games); err != nil {
+		return fmt.Errorf("failed to retire games: %w", err)
+	}
+
+	for _, g := range games {
+		r, err := gm.rooms.GetRoom(ctx, g.Namespace, g.RoomID)
+		if err != nil {
+			return fmt.Errorf("failed to get room: %w", err)
+		}
+		gm.lightFuse(r.Settings, g)
+	}
+	level.Info(gm.logger).Log("event", "fuse.rebuilt", "games", len(games))
+
+	<-ctx.Done()
+	gm.fuse.Stop()
+
+	return nil
+}
+
+func (gm *GameMaster) Detonations() <-chan *Detonation {
+	return gm.detonations
+}
+
+func (gm *GameMaster) lightFuse(settings *room.Settings, g *game.Game) {
+	key := fuseKey(g.Namespace, g.ChannelID, g.PotatoID)
+	if g.Finished || !gm.potatoes.Has(g.PotatoKind) {
+		gm.fuse.Snuff(key)
+		return
+	}
+
+	timeout := gm.fuse.Timeout()
+	if settings.FuseTimeout > 0 {
+		timeout = settings.FuseTimeout
+	}
+
+	turn := g.Turns
+	gm.fuse.Light(key, timeout, g.UpdatedAt, func() {
+		gm.detonate(g.Namespace, g.ChannelID, g.PotatoID, turn)
+	})
+}
+
+func (gm *GameMaster) detonate(namespace, channelID string, potatoID, turn int) {
+	logger := log.WithSuffix(gm.logger, "namespace", namespace, "channel", channelID, "potato", potatoID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), detonateTimeout)
+	defer cancel()
+
+	g, err := gm.games.GetGame(ctx, namespace, channelID, potatoID)
+	if err != nil {
+		level.Error(logger).Log("event", "fuse.detonate.failure", "err", fmt.Errorf("error getting game: %w", err))
+		return
+	}
+
+	if !gm.isOngoing(logger, g) || g.Turns != turn {
+		return
+	}
+
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		level.Error(logger).Log("event", "fuse.detonate.failure", "err", fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err))
+		return
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, g.Namespace, g.RoomID)
+	if err != nil {
+		level.Error(logger).Log("event", "fuse.detonate.failure", "err", fmt.Errorf("error getting room: %w", err))
+		return
+	}
+
+	var update *TournamentUpdate
+	var elimination *EliminationUpdate
+	var fresh *game.Game
+	err = gm.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		g, err = gm.games.EndGame(ctx, namespace, channelID, potatoID, turn)
+		if err != nil {
+			return fmt.Errorf("error ending game: %w", err)
+		}
+
+		update, err = gm.settleGame(ctx, logger, g)
+		if err != nil {
+			return err
+		}
+
+		elimination, fresh, err = gm.eliminate(ctx, logger, r, g)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, game.ErrTurnConflict) {
+			return
+		}
+		level.Error(logger).Log("event", "fuse.detonate.failure", "err", err)
+		return
+	}
+	level.Info(logger).Log("event", "game.ended", "reason", "fuse")
+
+	if fresh != nil {
+		gm.lightFuse(r.Settings, fresh)
+	}
+
+	gm.detonations <- &Detonation{
+		Namespace:    g.Namespace,
+		RoomID:       g.RoomID,
+		ChannelID:    g.ChannelID,
+		PotatoID:     g.PotatoID,
+		Turn:         g.Turns,
+		Potato:       potato,
+		HolderUserID: g.HolderUserID,
+		LosingTeam:   losingTeam(g),
+		Tournament:   update,
+		Elimination:  elimination,
+	}
+}
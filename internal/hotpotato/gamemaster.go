@@ -4,42 +4,66 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/log/level"
 
+	"github.com/jace-ys/hot-potato-discord/internal/database"
 	"github.com/jace-ys/hot-potato-discord/internal/game"
 	"github.com/jace-ys/hot-potato-discord/internal/room"
 )
 
 type GameMaster struct {
-	logger   log.Logger
-	rooms    room.RoomRepository
-	games    game.GameRepository
-	potatoes []Potato
+	logger      log.Logger
+	rooms       room.RoomRepository
+	games       game.GameRepository
+	tx          database.Transactor
+	fuse        *Fuse
+	cooldowns   *Cooldowns
+	detonations chan *Detonation
+	potatoes    *PotatoRegistry
+	random      Randomizer
+
+	achievements []Achievement
+	items        []Item
 }
 
-func NewGameMaster(logger log.Logger, rooms room.RoomRepository, games game.GameRepository) *GameMaster {
+func NewGameMaster(logger log.Logger, rooms room.RoomRepository, games game.GameRepository, tx database.Transactor, potatoes *PotatoRegistry, random Randomizer, fuse *Fuse, cooldowns *Cooldowns) *GameMaster {
 	return &GameMaster{
-		logger: logger,
-		rooms:  rooms,
-		games:  games,
-		potatoes: []Potato{
-			RawPotato{},
-			BakedPotato{},
-			HotPotato{},
-			BurntPotato{},
-		},
+		logger:      logger,
+		rooms:       rooms,
+		games:       games,
+		tx:          tx,
+		fuse:        fuse,
+		cooldowns:   cooldowns,
+		detonations: make(chan *Detonation, detonationsBuffer),
+		potatoes:    potatoes,
+		random:      random,
+
+		achievements: DefaultAchievements,
+		items:        DefaultItems,
 	}
 }
 
-func (gm *GameMaster) Toss(ctx context.Context, req *TossRequest) (*TossResponse, error) {
+func (gm *GameMaster) Toss(ctx context.Context, req *TossRequest) (rsp *TossResponse, err error) {
 	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
 
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
+	release, err := gm.cooldowns.Allow(req.Namespace, req.RoomID, req.ActorUserID, game.ActionToss)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
 	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
 	if err != nil {
 		if !errors.Is(err, room.ErrRoomNotFound) {
@@ -53,64 +77,108 @@ func (gm *GameMaster) Toss(ctx context.Context, req *TossRequest) (*TossResponse
 		level.Info(logger).Log("event", "room.created")
 	}
 
-	g, err := gm.games.GetGame(ctx, r.Namespace, req.ChannelID)
-	if err != nil && !errors.Is(err, game.ErrGameNotFound) {
-		return nil, fmt.Errorf("error getting game: %w", err)
+	_, heat, err := gm.liveHeat(ctx, r.Namespace, r.ID, req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	games, err := gm.listGames(ctx, logger, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
 	}
 
-	if errors.Is(err, game.ErrGameNotFound) || g.Finished {
-		potato := gm.RandomPotato()
-		g, err = gm.games.CreateNewGame(ctx, r.Namespace, r.ID, req.ChannelID, potato.Kind(), req.ActorUserID)
+	targetUserID := req.TargetUserID
+	if req.Random {
+		targetUserID, err = gm.pickTarget(ctx, logger, r, heat, games, req.ChannelID, req.PotatoID, req.ActorUserID)
 		if err != nil {
-			return nil, fmt.Errorf("error creating game: %w", err)
+			return nil, err
 		}
-		level.Info(logger).Log("event", "game.created")
 	}
 
-	if req.ActorUserID != g.HolderUserID {
-		return nil, &NotHolderError{g.HolderUserID}
+	if err := checkHeatPlayers(heat, req.ActorUserID, targetUserID); err != nil {
+		return nil, err
 	}
 
-	potato, err := gm.GetPotato(g.PotatoKind)
-	if err != nil {
-		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	// Checked before any new game is started, so that a rejected toss does not
+	// leave a lit potato behind.
+	if err := gm.checkOptedIn(ctx, r, heat, games, targetUserID); err != nil {
+		return nil, err
 	}
 
-	g, err = gm.games.NextTurn(ctx, r.Namespace, req.ChannelID, req.TargetUserID)
+	g, err := gm.findGame(logger, games, req.PotatoID, req.ActorUserID)
 	if err != nil {
-		return nil, fmt.Errorf("error handling turn: %w", err)
-	}
-	level.Info(logger).Log("event", "turn.handled")
-
-	explode := gm.DecideExplode(potato, g.Turns, g.HeatLevel)
-	if explode {
-		g, err = gm.games.EndGame(ctx, r.Namespace, req.ChannelID)
-		if err != nil {
-			return nil, fmt.Errorf("error ending game: %w", err)
+		// Heats are only ever played with the potato they were opened with.
+		potatoID, ok := gm.freePotatoID(logger, r.Settings, games, req.PotatoID)
+		if !ok || heat != nil {
+			return nil, err
 		}
-		level.Info(logger).Log("event", "game.ended")
 
-		err = gm.rooms.IncrementDeaths(ctx, r.Namespace, r.ID, req.TargetUserID)
+		g, err = gm.startGame(ctx, logger, r, games, req.ChannelID, potatoID, req.ActorUserID)
 		if err != nil {
-			return nil, fmt.Errorf("error incrementing death count: %w", err)
+			if errors.Is(err, game.ErrGameAlreadyExists) {
+				return nil, gm.turnConflict(ctx, r.Namespace, req.ChannelID, potatoID)
+			}
+			return nil, err
 		}
 	}
 
+	if err := checkTeamToss(g, req.ActorUserID, targetUserID); err != nil {
+		return nil, err
+	}
+
+	if err := checkEliminationToss(g, targetUserID); err != nil {
+		return nil, err
+	}
+
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	}
+
+	turn := &game.Turn{
+		Action:       game.ActionToss,
+		ActorUserID:  req.ActorUserID,
+		TargetUserID: targetUserID,
+	}
+
+	g, after, err := gm.playTurn(ctx, logger, r, g, potato, targetUserID, 0, turn)
+	if err != nil {
+		return nil, err
+	}
+
 	return &TossResponse{
+		PotatoID:     g.PotatoID,
 		Turn:         g.Turns,
 		Potato:       potato,
+		TargetUserID: targetUserID,
 		HolderUserID: g.HolderUserID,
-		Exploded:     explode,
+		Dodged:       after.dodged,
+		Exploded:     g.Finished,
+		LosingTeam:   losingTeam(g),
+		Achievements: after.unlocks,
+		Tournament:   after.tournament,
+		Elimination:  after.elimination,
+		Items:        after.items,
 	}, nil
 }
 
-func (gm *GameMaster) Steal(ctx context.Context, req *StealRequest) (*StealResponse, error) {
+func (gm *GameMaster) Steal(ctx context.Context, req *StealRequest) (rsp *StealResponse, err error) {
 	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
 
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
+	release, err := gm.cooldowns.Allow(req.Namespace, req.RoomID, req.ActorUserID, game.ActionSteal)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
 	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
 	if err != nil {
 		if !errors.Is(err, room.ErrRoomNotFound) {
@@ -124,63 +192,90 @@ func (gm *GameMaster) Steal(ctx context.Context, req *StealRequest) (*StealRespo
 		level.Info(logger).Log("event", "room.created")
 	}
 
-	g, err := gm.games.GetGame(ctx, r.Namespace, req.ChannelID)
-	if err != nil && !errors.Is(err, game.ErrGameNotFound) {
-		return nil, fmt.Errorf("error getting game: %w", err)
+	games, err := gm.listGames(ctx, logger, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
 	}
 
-	if errors.Is(err, game.ErrGameNotFound) || g.Finished {
-		return nil, ErrNoOngoingGame
+	g, err := gm.findGame(logger, games, req.PotatoID, req.TargetUserID)
+	if errors.Is(err, ErrNoOngoingGame) {
+		return nil, err
+	}
+
+	if !r.Settings.StealEnabled {
+		return nil, ErrStealDisabled
 	}
 
 	if req.TargetUserID == req.ActorUserID {
 		return nil, ErrSelfStealUnallowed
 	}
 
-	if req.TargetUserID != g.HolderUserID {
-		return nil, &NotHolderError{g.HolderUserID}
+	if err != nil {
+		return nil, err
 	}
 
-	potato, err := gm.GetPotato(g.PotatoKind)
-	if err != nil {
-		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	if _, ok := g.Teams[req.ActorUserID]; g.TeamGame() && !ok {
+		return nil, &NotOnTeamError{req.ActorUserID}
+	}
+
+	if g.EliminationGame() && !g.Playing(req.ActorUserID) {
+		return nil, &NotPlayingError{req.ActorUserID}
 	}
 
-	g, err = gm.games.NextTurn(ctx, r.Namespace, req.ChannelID, req.ActorUserID)
+	_, heat, err := gm.liveHeat(ctx, r.Namespace, r.ID, req.ChannelID)
 	if err != nil {
-		return nil, fmt.Errorf("error making turn: %w", err)
+		return nil, err
 	}
-	level.Info(logger).Log("event", "turn.handled")
 
-	explode := gm.DecideExplode(potato, g.Turns, g.HeatLevel)
-	if explode {
-		g, err = gm.games.EndGame(ctx, r.Namespace, req.ChannelID)
-		if err != nil {
-			return nil, fmt.Errorf("error ending game: %w", err)
-		}
-		level.Info(logger).Log("event", "game.ended")
+	if err := checkHeatPlayers(heat, req.ActorUserID); err != nil {
+		return nil, err
+	}
 
-		err = gm.rooms.IncrementDeaths(ctx, r.Namespace, r.ID, req.ActorUserID)
-		if err != nil {
-			return nil, fmt.Errorf("error incrementing death count: %w", err)
-		}
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	}
+
+	g, after, err := gm.playTurn(ctx, logger, r, g, potato, req.ActorUserID, 0, &game.Turn{
+		Action:       game.ActionSteal,
+		ActorUserID:  req.ActorUserID,
+		TargetUserID: req.TargetUserID,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &StealResponse{
+		PotatoID:     g.PotatoID,
 		Turn:         g.Turns,
 		Potato:       potato,
 		HolderUserID: g.HolderUserID,
-		Exploded:     explode,
+		Exploded:     g.Finished,
+		LosingTeam:   losingTeam(g),
+		Achievements: after.unlocks,
+		Tournament:   after.tournament,
+		Elimination:  after.elimination,
+		Items:        after.items,
 	}, nil
 }
 
-func (gm *GameMaster) Cook(ctx context.Context, req *CookRequest) (*CookResponse, error) {
+func (gm *GameMaster) Cook(ctx context.Context, req *CookRequest) (rsp *CookResponse, err error) {
 	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
 
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
+	release, err := gm.cooldowns.Allow(req.Namespace, req.RoomID, req.ActorUserID, game.ActionCook)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
 	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
 	if err != nil {
 		if !errors.Is(err, room.ErrRoomNotFound) {
@@ -194,17 +289,18 @@ func (gm *GameMaster) Cook(ctx context.Context, req *CookRequest) (*CookResponse
 		level.Info(logger).Log("event", "room.created")
 	}
 
-	g, err := gm.games.GetGame(ctx, r.Namespace, req.ChannelID)
-	if err != nil && !errors.Is(err, game.ErrGameNotFound) {
-		return nil, fmt.Errorf("error getting game: %w", err)
+	games, err := gm.listGames(ctx, logger, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
 	}
 
-	if errors.Is(err, game.ErrGameNotFound) || g.Finished {
-		return nil, ErrNoOngoingGame
+	g, err := gm.findGame(logger, games, req.PotatoID, req.ActorUserID)
+	if err != nil {
+		return nil, err
 	}
 
-	if req.ActorUserID != g.HolderUserID {
-		return nil, &NotHolderError{g.HolderUserID}
+	if r.Settings.CookCap > 0 && g.HeatLevel >= r.Settings.CookCap {
+		return nil, ErrCookCapReached
 	}
 
 	potato, err := gm.GetPotato(g.PotatoKind)
@@ -212,37 +308,27 @@ func (gm *GameMaster) Cook(ctx context.Context, req *CookRequest) (*CookResponse
 		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
 	}
 
-	g, err = gm.games.IncrementHeatLevel(ctx, r.Namespace, req.ChannelID)
-	if err != nil {
-		return nil, fmt.Errorf("error incrementing heat level: %w", err)
-	}
-
-	g, err = gm.games.NextTurn(ctx, r.Namespace, req.ChannelID, req.ActorUserID)
+	g, after, err := gm.playTurn(ctx, logger, r, g, potato, req.ActorUserID, 1, &game.Turn{
+		Action:       game.ActionCook,
+		ActorUserID:  req.ActorUserID,
+		TargetUserID: req.ActorUserID,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error making turn: %w", err)
-	}
-	level.Info(logger).Log("event", "turn.handled")
-
-	explode := gm.DecideExplode(potato, g.Turns, g.HeatLevel)
-	if explode {
-		g, err = gm.games.EndGame(ctx, r.Namespace, req.ChannelID)
-		if err != nil {
-			return nil, fmt.Errorf("error ending game: %w", err)
-		}
-		level.Info(logger).Log("event", "game.ended")
-
-		err = gm.rooms.IncrementDeaths(ctx, r.Namespace, r.ID, req.ActorUserID)
-		if err != nil {
-			return nil, fmt.Errorf("error incrementing death count: %w", err)
-		}
+		return nil, err
 	}
 
 	return &CookResponse{
+		PotatoID:     g.PotatoID,
 		Turn:         g.Turns,
 		HeatLevel:    g.HeatLevel,
 		Potato:       potato,
 		HolderUserID: g.HolderUserID,
-		Exploded:     explode,
+		Exploded:     g.Finished,
+		LosingTeam:   losingTeam(g),
+		Achievements: after.unlocks,
+		Tournament:   after.tournament,
+		Elimination:  after.elimination,
+		Items:        after.items,
 	}, nil
 }
 
@@ -266,28 +352,38 @@ func (gm *GameMaster) GetHolder(ctx context.Context, req *GetHolderRequest) (*Ge
 		level.Info(logger).Log("event", "room.created")
 	}
 
-	g, err := gm.games.GetGame(ctx, r.Namespace, req.ChannelID)
-	if err != nil && !errors.Is(err, game.ErrGameNotFound) {
-		return nil, fmt.Errorf("error getting game: %w", err)
+	games, err := gm.games.ListGames(ctx, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
 	}
 
-	if errors.Is(err, game.ErrGameNotFound) || g.Finished {
-		return nil, ErrNoOngoingGame
+	rsp := new(GetHolderResponse)
+	for _, g := range games {
+		if !gm.isOngoing(logger, g) {
+			continue
+		}
+
+		potato, err := gm.GetPotato(g.PotatoKind)
+		if err != nil {
+			return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+		}
+
+		rsp.Potatoes = append(rsp.Potatoes, &LivePotato{
+			ID:           g.PotatoID,
+			Potato:       potato,
+			HolderUserID: g.HolderUserID,
+		})
 	}
 
-	potato, err := gm.GetPotato(g.PotatoKind)
-	if err != nil {
-		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	if len(rsp.Potatoes) == 0 {
+		return nil, ErrNoOngoingGame
 	}
 
-	return &GetHolderResponse{
-		Potato:       potato,
-		HolderUserID: g.HolderUserID,
-	}, nil
+	return rsp, nil
 }
 
-func (gm *GameMaster) GetLeaderboard(ctx context.Context, req *GetLeaderboardRequest) (*GetLeaderboardResponse, error) {
-	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+func (gm *GameMaster) GetHistory(ctx context.Context, req *GetHistoryRequest) (*GetHistoryResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
 
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
@@ -306,14 +402,1218 @@ func (gm *GameMaster) GetLeaderboard(ctx context.Context, req *GetLeaderboardReq
 		level.Info(logger).Log("event", "room.created")
 	}
 
-	var leaderboard Scoreboard
-	if req.Top > 0 {
-		leaderboard = BuildLeaderboard(r.DeathCount, req.Top)
-	} else {
-		leaderboard = BuildLeaderboard(r.DeathCount)
+	g, err := gm.lastGame(ctx, r.Namespace, req.ChannelID, req.PotatoID)
+	if err != nil {
+		if errors.Is(err, game.ErrGameNotFound) {
+			return nil, ErrNoGameHistory
+		}
+		return nil, fmt.Errorf("error getting game: %w", err)
 	}
 
-	return &GetLeaderboardResponse{
-		Leaderboard: leaderboard,
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPotatoKind) {
+			return nil, ErrNoGameHistory
+		}
+		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	}
+
+	turns, err := gm.games.ListTurns(ctx, r.Namespace, req.ChannelID, g.PotatoID, g.Round)
+	if err != nil {
+		return nil, fmt.Errorf("error listing turns: %w", err)
+	}
+
+	return &GetHistoryResponse{
+		PotatoID:     g.PotatoID,
+		Potato:       potato,
+		HolderUserID: g.HolderUserID,
+		Finished:     g.Finished,
+		Turns:        turns,
 	}, nil
 }
+
+// aftermath is what came of a turn besides the change to the game it was
+// played on.
+type aftermath struct {
+	unlocks     []Unlock
+	tournament  *TournamentUpdate
+	elimination *EliminationUpdate
+	items       *ItemUpdate
+	dodged      bool
+}
+
+// playTurn plays a turn on the game, handing the potato to the given holder
+// and deciding whether it explodes in their hands.
+func (gm *GameMaster) playTurn(ctx context.Context, logger log.Logger, r *room.Room, g *game.Game, potato Potato, holderUserID string, heatIncrease int, turn *game.Turn) (*game.Game, *aftermath, error) {
+	turn.Turn = g.Turns + 1
+	turn.HeatLevel = g.HeatLevel + heatIncrease
+	turn.ExplodeChance = gm.ExplodeChance(potato, turn.Turn, turn.HeatLevel, r.Settings.ExplodeMultiplier)
+
+	var err error
+	var next, fresh *game.Game
+	var after *aftermath
+	for {
+		played := *turn
+		after = new(aftermath)
+		err = gm.tx.InTx(ctx, func(ctx context.Context) error {
+			var err error
+			next, fresh, err = gm.settleTurn(ctx, logger, r, g, holderUserID, heatIncrease, &played, after)
+			return err
+		})
+		if !errors.Is(err, errItemConflict) {
+			*turn = played
+			break
+		}
+	}
+	if err != nil {
+		if errors.Is(err, game.ErrTurnConflict) || errors.Is(err, game.ErrGameAlreadyExists) {
+			return nil, nil, gm.turnConflict(ctx, g.Namespace, g.ChannelID, g.PotatoID)
+		}
+		return nil, nil, err
+	}
+	level.Info(logger).Log("event", "turn.handled")
+
+	if next.Finished {
+		level.Info(logger).Log("event", "game.ended")
+	}
+
+	gm.lightFuse(r.Settings, next)
+
+	after.unlocks, err = gm.unlockAchievements(ctx, next, turn)
+	if err != nil {
+		level.Error(logger).Log("event", "achievements.unlock.failure", "err", err)
+	}
+
+	if fresh != nil {
+		gm.lightFuse(r.Settings, fresh)
+	}
+
+	return next, after, nil
+}
+
+func (gm *GameMaster) settleTurn(ctx context.Context, logger log.Logger, r *room.Room, g *game.Game, holderUserID string, heatIncrease int, turn *game.Turn, after *aftermath) (*game.Game, *game.Game, error) {
+	if turn.Action == game.ActionToss {
+		dodged, err := gm.dodges(ctx, r, turn.ActorUserID, turn.TargetUserID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if dodged {
+			if _, err := gm.useItems(ctx, logger, r.Namespace, r.ID, turn.TargetUserID, []string{ItemDodge}); err != nil {
+				return nil, nil, err
+			}
+			turn.ActorUserID, turn.TargetUserID = turn.TargetUserID, turn.ActorUserID
+			holderUserID = turn.TargetUserID
+			after.dodged = true
+		}
+	}
+
+	armed, err := gm.armedItems(ctx, g.Namespace, g.RoomID, holderUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var used []string
+	turn.Exploded, used = gm.DecideExplode(turnRandomizer(g.Seed, turn.Turn), turn.ExplodeChance, armed)
+
+	next, err := gm.games.PlayTurn(ctx, g.Namespace, g.ChannelID, g.PotatoID, &game.Play{
+		ExpectedHolderUserID: g.HolderUserID,
+		ExpectedTurns:        g.Turns,
+		HolderUserID:         holderUserID,
+		HeatIncrease:         heatIncrease,
+		Turn:                 turn,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error playing turn: %w", err)
+	}
+
+	after.items, err = gm.settleItems(ctx, logger, next, used)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if next.Finished {
+		after.tournament, err = gm.settleGame(ctx, logger, next)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var fresh *game.Game
+	after.elimination, fresh, err = gm.eliminate(ctx, logger, r, next)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return next, fresh, nil
+}
+
+// settleGame counts the death of whoever was left holding the potato when the
+// game ended, and settles the team, stats and tournament results that follow.
+func (gm *GameMaster) settleGame(ctx context.Context, logger log.Logger, g *game.Game) (*TournamentUpdate, error) {
+	err := gm.rooms.IncrementDeaths(ctx, g.Namespace, g.RoomID, g.HolderUserID)
+	if err != nil {
+		return nil, fmt.Errorf("error incrementing death count: %w", err)
+	}
+
+	if err := gm.creditTeamLoss(ctx, g); err != nil {
+		return nil, err
+	}
+
+	if err := gm.recordStats(ctx, g); err != nil {
+		return nil, err
+	}
+
+	return gm.advanceTournament(ctx, logger, g)
+}
+
+// turnConflict explains why a turn lost the race against another turn played
+// on the same game at the same time.
+func (gm *GameMaster) turnConflict(ctx context.Context, namespace, channelID string, potatoID int) error {
+	g, err := gm.games.GetGame(ctx, namespace, channelID, potatoID)
+	if err != nil {
+		return fmt.Errorf("error getting game: %w", err)
+	}
+
+	if g.Finished {
+		return ErrNoOngoingGame
+	}
+
+	return &NotHolderError{g.HolderUserID}
+}
+
+func (gm *GameMaster) findGame(logger log.Logger, games []*game.Game, potatoID int, holderUserID string) (*game.Game, error) {
+	var live []*game.Game
+	for _, g := range games {
+		if gm.isOngoing(logger, g) {
+			live = append(live, g)
+		}
+	}
+
+	if len(live) == 0 {
+		return nil, ErrNoOngoingGame
+	}
+
+	for _, g := range live {
+		if potatoID != 0 && g.PotatoID != potatoID {
+			continue
+		}
+
+		if g.HolderUserID == holderUserID {
+			return g, nil
+		}
+
+		if potatoID != 0 {
+			return nil, &NotHolderError{g.HolderUserID}
+		}
+	}
+
+	if potatoID != 0 {
+		return nil, ErrPotatoNotFound
+	}
+
+	return nil, &NotHolderError{live[0].HolderUserID}
+}
+
+func (gm *GameMaster) freePotatoID(logger log.Logger, settings *room.Settings, games []*game.Game, potatoID int) (int, bool) {
+	busy := make(map[int]bool)
+	for _, g := range games {
+		if !gm.isOngoing(logger, g) {
+			continue
+		}
+
+		if g.TeamGame() || g.EliminationGame() {
+			return 0, false
+		}
+		busy[g.PotatoID] = true
+	}
+
+	if potatoID != 0 {
+		return potatoID, potatoID <= settings.PotatoLimit && !busy[potatoID]
+	}
+
+	for id := 1; id <= settings.PotatoLimit; id++ {
+		if !busy[id] {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+func (gm *GameMaster) startGame(ctx context.Context, logger log.Logger, r *room.Room, games []*game.Game, channelID string, potatoID int, startUserID string) (*game.Game, error) {
+	var last *game.Game
+	for _, g := range games {
+		if g.PotatoID == potatoID {
+			last = g
+		}
+	}
+
+	potato := gm.RandomPotato(r.Settings.AllowedPotatoKinds...)
+	seed := gm.random.Int63()
+
+	var g *game.Game
+	err := gm.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if last == nil {
+			g, err = gm.games.CreateNewGame(ctx, r.Namespace, r.ID, channelID, potatoID, potato.Kind(), startUserID, seed)
+		} else {
+			g, err = gm.games.RestartGame(ctx, r.Namespace, channelID, potatoID, last.Round, potato.Kind(), startUserID, seed)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating game: %w", err)
+	}
+	level.Info(logger).Log("event", "game.created", "potato", potatoID, "seed", seed)
+
+	return g, nil
+}
+
+func (gm *GameMaster) lastGame(ctx context.Context, namespace, channelID string, potatoID int) (*game.Game, error) {
+	if potatoID != 0 {
+		return gm.games.GetGame(ctx, namespace, channelID, potatoID)
+	}
+
+	games, err := gm.games.ListGames(ctx, namespace, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var last *game.Game
+	for _, g := range games {
+		if last == nil || g.UpdatedAt.After(last.UpdatedAt) {
+			last = g
+		}
+	}
+
+	if last == nil {
+		return nil, game.ErrGameNotFound
+	}
+
+	return last, nil
+}
+
+func (gm *GameMaster) listGames(ctx context.Context, logger log.Logger, namespace, channelID string) ([]*game.Game, error) {
+	games, err := gm.games.ListGames(ctx, namespace, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gm.retireGames(ctx, logger, games); err != nil {
+		return nil, err
+	}
+
+	return games, nil
+}
+
+func (gm *GameMaster) retireGames(ctx context.Context, logger log.Logger, games []*game.Game) error {
+	for _, g := range games {
+		if g.Finished || gm.potatoes.Has(g.PotatoKind) {
+			continue
+		}
+
+		if err := gm.games.RetireGame(ctx, g.Namespace, g.ChannelID, g.PotatoID); err != nil {
+			return fmt.Errorf("error retiring game: %w", err)
+		}
+		g.Finished = true
+		level.Info(logger).Log("event", "game.retired", "potato", g.PotatoID, "kind", g.PotatoKind)
+	}
+
+	return nil
+}
+
+func (gm *GameMaster) isOngoing(logger log.Logger, g *game.Game) bool {
+	if g.Finished {
+		return false
+	}
+
+	if !gm.potatoes.Has(g.PotatoKind) {
+		level.Warn(logger).Log("event", "game.potato.retired", "kind", g.PotatoKind)
+		return false
+	}
+
+	return true
+}
+
+func (gm *GameMaster) GetLeaderboard(ctx context.Context, req *GetLeaderboardRequest) (*GetLeaderboardResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	top := req.Top
+	if top == 0 {
+		top = r.Settings.LeaderboardSize
+	}
+
+	metric := req.Metric
+	if metric == "" {
+		metric = MetricDeaths
+	}
+
+	number := req.Season
+	if number == 0 {
+		number = r.Season
+	}
+
+	rsp := &GetLeaderboardResponse{
+		Metric: metric,
+		Season: number,
+	}
+
+	var leaderboard Scoreboard
+	if metric == MetricDeaths && number == r.Season {
+		// Death counts of the active season are already loaded with the room.
+		leaderboard = BuildLeaderboard(metric, r.DeathCount, nil)
+	} else {
+		season, err := gm.rooms.GetSeason(ctx, r.Namespace, r.ID, number)
+		if err != nil {
+			if errors.Is(err, room.ErrSeasonNotFound) {
+				return nil, ErrSeasonNotFound
+			}
+			return nil, fmt.Errorf("error getting season: %w", err)
+		}
+
+		rsp.Ended = season.Ended()
+		leaderboard = BuildLeaderboard(metric, season.Standings, season.Stats)
+	}
+
+	rsp.Leaderboard, rsp.Page, rsp.Pages = leaderboard.Paginate(req.Page, top)
+	rsp.Offset = rsp.Page * top
+
+	return rsp, nil
+}
+
+func (gm *GameMaster) GetProfile(ctx context.Context, req *GetProfileRequest) (*GetProfileResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	record, err := gm.games.GetRecord(ctx, r.Namespace, r.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting record: %w", err)
+	}
+
+	rsp := &GetProfileResponse{
+		Season:                r.Season,
+		GamesPlayed:           record.GamesPlayed,
+		FavouriteTargetUserID: record.FavouriteTargetUserID,
+		FavouriteTargetTosses: record.FavouriteTargetTosses,
+		NemesisUserID:         record.NemesisUserID,
+		NemesisKills:          record.NemesisKills,
+	}
+
+	for i, entry := range BuildLeaderboard(MetricDeaths, r.DeathCount, nil) {
+		if entry.UserID == req.UserID {
+			rsp.Deaths = int(entry.Value)
+			rsp.Rank = i + 1
+			break
+		}
+	}
+
+	wins, err := gm.rooms.ListWins(ctx, r.Namespace, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing wins: %w", err)
+	}
+
+	for _, counter := range wins {
+		if counter.UserID == req.UserID {
+			rsp.Wins = counter.Count
+			break
+		}
+	}
+
+	items, err := gm.rooms.ListItems(ctx, r.Namespace, r.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items: %w", err)
+	}
+
+	for _, held := range items {
+		item, ok := gm.item(held.ID)
+		if !ok || held.Count == 0 {
+			continue
+		}
+		rsp.Items = append(rsp.Items, &HeldItem{Item: item, Count: held.Count})
+	}
+
+	return rsp, nil
+}
+
+func (gm *GameMaster) ListAchievements(ctx context.Context, req *ListAchievementsRequest) (*ListAchievementsResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	unlocked, err := gm.rooms.ListAchievements(ctx, r.Namespace, r.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing achievements: %w", err)
+	}
+
+	unlockedAt := make(map[string]time.Time, len(unlocked))
+	for _, achievement := range unlocked {
+		unlockedAt[achievement.ID] = achievement.UnlockedAt
+	}
+
+	achievements := make([]*AchievementProgress, len(gm.achievements))
+	for i, achievement := range gm.achievements {
+		at, ok := unlockedAt[achievement.ID]
+		achievements[i] = &AchievementProgress{
+			Achievement: achievement,
+			Unlocked:    ok,
+			UnlockedAt:  at,
+		}
+	}
+
+	return &ListAchievementsResponse{
+		Achievements: achievements,
+	}, nil
+}
+
+func (gm *GameMaster) GetSettings(ctx context.Context, req *GetSettingsRequest) (*GetSettingsResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	return &GetSettingsResponse{
+		Settings: r.Settings,
+	}, nil
+}
+
+func (gm *GameMaster) UpdateSettings(ctx context.Context, req *UpdateSettingsRequest) (*UpdateSettingsResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if err := req.Settings.Validate(); err != nil {
+		return nil, &InvalidSettingsError{err}
+	}
+
+	for _, kind := range req.Settings.AllowedPotatoKinds {
+		if !gm.potatoes.Has(kind) {
+			return nil, ErrInvalidPotatoKind
+		}
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	r, err = gm.rooms.UpdateSettings(ctx, r.Namespace, r.ID, req.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("error updating settings: %w", err)
+	}
+	level.Info(logger).Log("event", "room.settings.updated")
+
+	return &UpdateSettingsResponse{
+		Settings: r.Settings,
+	}, nil
+}
+
+func (gm *GameMaster) EndSeason(ctx context.Context, req *EndSeasonRequest) (*EndSeasonResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	season, err := gm.rooms.EndSeason(ctx, r.Namespace, r.ID, r.Season)
+	if err != nil {
+		if errors.Is(err, room.ErrSeasonAlreadyEnded) {
+			return nil, ErrSeasonAlreadyEnded
+		}
+		return nil, fmt.Errorf("error ending season: %w", err)
+	}
+	level.Info(logger).Log("event", "season.ended", "season", season.Number)
+
+	return &EndSeasonResponse{
+		Season:      season.Number,
+		NextSeason:  season.Number + 1,
+		Leaderboard: BuildLeaderboard(MetricDeaths, season.Standings, nil, r.Settings.LeaderboardSize),
+	}, nil
+}
+
+func (gm *GameMaster) StartTeamGame(ctx context.Context, req *StartTeamGameRequest) (*StartTeamGameResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	games, err := gm.listGames(ctx, logger, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
+	}
+
+	var last *game.Game
+	for _, g := range games {
+		if gm.isOngoing(logger, g) {
+			return nil, ErrGameInProgress
+		}
+
+		if last == nil || g.UpdatedAt.After(last.UpdatedAt) {
+			last = g
+		}
+	}
+
+	teams := req.Teams
+	if len(teams) == 0 {
+		var turns []*game.Turn
+		if last != nil {
+			turns, err = gm.games.ListTurns(ctx, r.Namespace, req.ChannelID, last.PotatoID, last.Round)
+			if err != nil {
+				return nil, fmt.Errorf("error listing turns: %w", err)
+			}
+		}
+		teams = gm.splitTeams(participants(req.ActorUserID, turns))
+	}
+
+	if len(teams) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	assignments := make(map[string]string)
+	for team, players := range teams {
+		if len(players) == 0 {
+			return nil, ErrNotEnoughPlayers
+		}
+		for _, userID := range players {
+			assignments[userID] = team
+		}
+	}
+
+	if _, ok := assignments[req.ActorUserID]; !ok {
+		return nil, &NotOnTeamError{req.ActorUserID}
+	}
+
+	g, err := gm.startGame(ctx, logger, r, games, req.ChannelID, 1, req.ActorUserID)
+	if err != nil {
+		if errors.Is(err, game.ErrGameAlreadyExists) {
+			return nil, ErrGameInProgress
+		}
+		return nil, err
+	}
+
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	}
+
+	g, err = gm.games.AssignTeams(ctx, r.Namespace, req.ChannelID, g.PotatoID, g.Round, assignments)
+	if err != nil {
+		if errors.Is(err, game.ErrTurnConflict) {
+			return nil, ErrGameInProgress
+		}
+		return nil, fmt.Errorf("error assigning teams: %w", err)
+	}
+	level.Info(logger).Log("event", "game.teams.assigned", "teams", len(teams), "players", len(assignments))
+
+	gm.lightFuse(r.Settings, g)
+
+	return &StartTeamGameResponse{
+		PotatoID:     g.PotatoID,
+		Potato:       potato,
+		HolderUserID: g.HolderUserID,
+		Teams:        teams,
+	}, nil
+}
+
+func (gm *GameMaster) OptIn(ctx context.Context, req *OptInRequest) (*OptInResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	added, err := gm.rooms.OptIn(ctx, r.Namespace, r.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error opting in: %w", err)
+	}
+	level.Info(logger).Log("event", "participant.opted_in", "added", added)
+
+	return &OptInResponse{
+		Added: added,
+	}, nil
+}
+
+func (gm *GameMaster) OptOut(ctx context.Context, req *OptOutRequest) (*OptOutResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	removed, err := gm.rooms.OptOut(ctx, r.Namespace, r.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error opting out: %w", err)
+	}
+	level.Info(logger).Log("event", "participant.opted_out", "removed", removed)
+
+	return &OptOutResponse{
+		Removed: removed,
+	}, nil
+}
+
+func (gm *GameMaster) UseItem(ctx context.Context, req *UseItemRequest) (*UseItemResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	item, ok := gm.item(req.ItemID)
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	armed, err := gm.rooms.ArmItem(ctx, r.Namespace, r.ID, req.UserID, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error arming item: %w", err)
+	}
+
+	items, err := gm.rooms.ListItems(ctx, r.Namespace, r.ID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items: %w", err)
+	}
+
+	var held *room.Item
+	for _, i := range items {
+		if i.ID == item.ID {
+			held = i
+		}
+	}
+
+	if !armed {
+		if held != nil && held.Armed {
+			return nil, ErrItemAlreadyArmed
+		}
+		return nil, ErrItemNotHeld
+	}
+	level.Info(logger).Log("event", "item.armed", "item", item.ID)
+
+	return &UseItemResponse{
+		Item:      item,
+		Remaining: held.Count,
+	}, nil
+}
+
+func (gm *GameMaster) JoinLobby(ctx context.Context, req *JoinLobbyRequest) (*JoinLobbyResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	// Joining a lobby is as good as opting in to play.
+	if _, err := gm.rooms.OptIn(ctx, r.Namespace, r.ID, req.UserID); err != nil {
+		return nil, fmt.Errorf("error opting in: %w", err)
+	}
+
+	players, err := gm.games.JoinLobby(ctx, r.Namespace, r.ID, req.ChannelID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error joining lobby: %w", err)
+	}
+	level.Info(logger).Log("event", "lobby.joined", "players", len(players))
+
+	return &JoinLobbyResponse{
+		Players: players,
+	}, nil
+}
+
+func (gm *GameMaster) StartElimination(ctx context.Context, req *StartEliminationRequest) (*StartEliminationResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	games, err := gm.listGames(ctx, logger, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
+	}
+
+	for _, g := range games {
+		if gm.isOngoing(logger, g) {
+			return nil, ErrGameInProgress
+		}
+	}
+
+	players, err := gm.games.ListLobby(ctx, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing lobby: %w", err)
+	}
+
+	if len(players) < 2 {
+		return nil, ErrLobbyTooSmall
+	}
+
+	// Elimination games are always played with the first potato in the
+	// channel.
+	g, err := gm.startGame(ctx, logger, r, games, req.ChannelID, 1, players[gm.random.Intn(len(players))])
+	if err != nil {
+		if errors.Is(err, game.ErrGameAlreadyExists) {
+			return nil, ErrGameInProgress
+		}
+		return nil, err
+	}
+
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	}
+
+	g, err = gm.games.AssignPlayers(ctx, r.Namespace, req.ChannelID, g.PotatoID, g.Round, players)
+	if err != nil {
+		if errors.Is(err, game.ErrTurnConflict) {
+			return nil, ErrGameInProgress
+		}
+		return nil, fmt.Errorf("error assigning players: %w", err)
+	}
+	level.Info(logger).Log("event", "game.players.assigned", "players", len(players))
+
+	if err := gm.games.ClearLobby(ctx, r.Namespace, req.ChannelID); err != nil {
+		return nil, fmt.Errorf("error clearing lobby: %w", err)
+	}
+
+	gm.lightFuse(r.Settings, g)
+
+	return &StartEliminationResponse{
+		Potato:       potato,
+		HolderUserID: g.HolderUserID,
+		Players:      g.Players,
+	}, nil
+}
+
+func (gm *GameMaster) GetTeamLeaderboard(ctx context.Context, req *GetTeamLeaderboardRequest) (*GetTeamLeaderboardResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	leaderboard, err := gm.rooms.ListTeamLosses(ctx, r.Namespace, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing team losses: %w", err)
+	}
+
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Count != leaderboard[j].Count {
+			return leaderboard[i].Count > leaderboard[j].Count
+		}
+		return leaderboard[i].Team < leaderboard[j].Team
+	})
+
+	return &GetTeamLeaderboardResponse{
+		Leaderboard: leaderboard,
+	}, nil
+}
+
+func (gm *GameMaster) RegisterTournamentPlayers(ctx context.Context, req *RegisterTournamentPlayersRequest) (*RegisterTournamentPlayersResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if !errors.Is(err, room.ErrRoomNotFound) {
+			return nil, fmt.Errorf("error getting room: %w", err)
+		}
+
+		r, err = gm.rooms.CreateRoom(ctx, string(req.Namespace), req.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating room: %w", err)
+		}
+		level.Info(logger).Log("event", "room.created")
+	}
+
+	t, err := gm.rooms.GetTournament(ctx, r.Namespace, r.ID)
+	if err != nil {
+		if !errors.Is(err, room.ErrTournamentNotFound) {
+			return nil, fmt.Errorf("error getting tournament: %w", err)
+		}
+
+		t, err = gm.rooms.CreateTournament(ctx, r.Namespace, r.ID, req.ChannelID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating tournament: %w", err)
+		}
+		level.Info(logger).Log("event", "tournament.created", "tournament", t.Number)
+	}
+
+	if t.Started() {
+		return nil, ErrTournamentStarted
+	}
+
+	registered := len(t.Players)
+	t, err = gm.rooms.RegisterPlayers(ctx, r.Namespace, r.ID, t.Number, req.UserIDs)
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentConflict) {
+			return nil, ErrTournamentStarted
+		}
+		return nil, fmt.Errorf("error registering players: %w", err)
+	}
+	level.Info(logger).Log("event", "tournament.players.registered", "tournament", t.Number, "players", len(t.Players))
+
+	return &RegisterTournamentPlayersResponse{
+		Tournament: t,
+		Registered: len(t.Players) - registered,
+	}, nil
+}
+
+func (gm *GameMaster) StartTournament(ctx context.Context, req *StartTournamentRequest) (*StartTournamentResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	t, err := gm.rooms.GetTournament(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentNotFound) || errors.Is(err, room.ErrRoomNotFound) {
+			return nil, ErrNoTournament
+		}
+		return nil, fmt.Errorf("error getting tournament: %w", err)
+	}
+
+	if t.Started() {
+		return nil, ErrTournamentStarted
+	}
+
+	if len(t.Players) < HeatSize {
+		return nil, ErrNotEnoughEntrants
+	}
+
+	t, err = gm.rooms.StartRound(ctx, string(req.Namespace), req.RoomID, t.Number, 1, gm.drawHeats(t.Players))
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentConflict) {
+			return nil, ErrTournamentStarted
+		}
+		return nil, fmt.Errorf("error starting round: %w", err)
+	}
+	level.Info(logger).Log("event", "tournament.round.started", "tournament", t.Number, "round", t.Round)
+
+	return &StartTournamentResponse{
+		Update: &TournamentUpdate{
+			Tournament: t,
+			Heats:      t.RoundHeats(t.Round),
+		},
+	}, nil
+}
+
+func (gm *GameMaster) OpenHeat(ctx context.Context, req *OpenHeatRequest) (*OpenHeatResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID, "channel", req.ChannelID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	r, err := gm.rooms.GetRoom(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if errors.Is(err, room.ErrRoomNotFound) {
+			return nil, ErrNoTournament
+		}
+		return nil, fmt.Errorf("error getting room: %w", err)
+	}
+
+	games, err := gm.listGames(ctx, logger, r.Namespace, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing games: %w", err)
+	}
+
+	for _, g := range games {
+		if gm.isOngoing(logger, g) {
+			return nil, ErrGameInProgress
+		}
+	}
+
+	t, err := gm.rooms.OpenHeat(ctx, r.Namespace, r.ID, req.Tournament, req.Round, req.Heat, req.ChannelID)
+	if err != nil {
+		switch {
+		case errors.Is(err, room.ErrTournamentNotFound):
+			return nil, ErrNoTournament
+		case errors.Is(err, room.ErrTournamentConflict):
+			return nil, ErrHeatNotFound
+		}
+		return nil, fmt.Errorf("error opening heat: %w", err)
+	}
+
+	heat := t.LiveHeat(req.ChannelID)
+	if heat == nil || len(heat.Players) == 0 {
+		return nil, ErrHeatNotFound
+	}
+	level.Info(logger).Log("event", "tournament.heat.opened", "tournament", t.Number, "round", heat.Round, "heat", heat.Number)
+
+	g, err := gm.startGame(ctx, logger, r, games, req.ChannelID, 1, heat.Players[0])
+	if err != nil {
+		if errors.Is(err, game.ErrGameAlreadyExists) {
+			return nil, ErrGameInProgress
+		}
+		return nil, err
+	}
+
+	potato, err := gm.GetPotato(g.PotatoKind)
+	if err != nil {
+		return nil, fmt.Errorf("error getting potato of kind '%s': %w", g.PotatoKind, err)
+	}
+
+	gm.lightFuse(r.Settings, g)
+
+	return &OpenHeatResponse{
+		Heat:         heat,
+		PotatoID:     g.PotatoID,
+		Potato:       potato,
+		HolderUserID: g.HolderUserID,
+	}, nil
+}
+
+func (gm *GameMaster) GetTournament(ctx context.Context, req *GetTournamentRequest) (*GetTournamentResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	t, err := gm.rooms.GetTournament(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentNotFound) || errors.Is(err, room.ErrRoomNotFound) {
+			return nil, ErrNoTournament
+		}
+		return nil, fmt.Errorf("error getting tournament: %w", err)
+	}
+
+	return &GetTournamentResponse{
+		Tournament: t,
+	}, nil
+}
+
+func (gm *GameMaster) CancelTournament(ctx context.Context, req *CancelTournamentRequest) (*CancelTournamentResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	t, err := gm.rooms.GetTournament(ctx, string(req.Namespace), req.RoomID)
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentNotFound) || errors.Is(err, room.ErrRoomNotFound) {
+			return nil, ErrNoTournament
+		}
+		return nil, fmt.Errorf("error getting tournament: %w", err)
+	}
+
+	t, err = gm.rooms.EndTournament(ctx, string(req.Namespace), req.RoomID, t.Number, "")
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentConflict) {
+			return nil, ErrNoTournament
+		}
+		return nil, fmt.Errorf("error ending tournament: %w", err)
+	}
+	level.Info(logger).Log("event", "tournament.cancelled", "tournament", t.Number)
+
+	return &CancelTournamentResponse{
+		Tournament: t,
+	}, nil
+}
+
+func (gm *GameMaster) ListClosingHeats(ctx context.Context, req *ListClosingHeatsRequest) (*ListClosingHeatsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	heats, err := gm.rooms.ListClosingHeats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing closing heats: %w", err)
+	}
+
+	return &ListClosingHeatsResponse{
+		Heats: heats,
+	}, nil
+}
+
+func (gm *GameMaster) CloseHeat(ctx context.Context, req *CloseHeatRequest) (*CloseHeatResponse, error) {
+	logger := log.WithSuffix(gm.logger, "namespace", req.Namespace, "room", req.RoomID)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if err := gm.rooms.CloseHeat(ctx, req.Namespace, req.RoomID, req.Tournament, req.Round, req.Heat); err != nil {
+		return nil, fmt.Errorf("error closing heat: %w", err)
+	}
+	level.Info(logger).Log("event", "tournament.heat.closed", "tournament", req.Tournament, "round", req.Round, "heat", req.Heat)
+
+	return &CloseHeatResponse{}, nil
+}
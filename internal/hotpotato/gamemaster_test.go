@@ -0,0 +1,645 @@
+package hotpotato
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/jace-ys/hot-potato-discord/db"
+	"github.com/jace-ys/hot-potato-discord/internal/database"
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+const (
+	testNamespace = "guild"
+	testRoomID    = "room"
+	testChannelID = "channel"
+)
+
+type testStorage struct {
+	name string
+	open func(t *testing.T) (room.RoomRepository, game.GameRepository, database.Transactor)
+}
+
+var (
+	memoryStorage = testStorage{name: "memory", open: openMemoryStorage}
+	sqliteStorage = testStorage{name: "sqlite", open: openSQLiteStorage}
+
+	testStorages = []testStorage{memoryStorage, sqliteStorage}
+)
+
+func openMemoryStorage(t *testing.T) (room.RoomRepository, game.GameRepository, database.Transactor) {
+	rooms, games := room.NewMemoryRepository(), game.NewMemoryRepository()
+	return rooms, games, database.NewMutexTransactor(rooms, games)
+}
+
+func openSQLiteStorage(t *testing.T) (room.RoomRepository, game.GameRepository, database.Transactor) {
+	sqldb, dialect, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "hotpotato.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqldb.Close() })
+
+	migrator := database.NewMigrator(log.NewNopLogger(), sqldb, dialect, db.Migrations(string(dialect)))
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	return room.NewRepository(sqldb), game.NewRepository(sqldb), database.NewTransactor(sqldb)
+}
+
+func newTestGameMaster(t *testing.T, storage testStorage) *GameMaster {
+	rooms, games, tx := storage.open(t)
+	return NewGameMaster(log.NewNopLogger(), rooms, games, tx, DefaultPotatoRegistry(), NewRandomizer(1), NewFuse(0), NewCooldowns(nil))
+}
+
+func updateSettings(t *testing.T, gm *GameMaster, update func(settings *room.Settings)) {
+	settings := room.DefaultSettings()
+	update(settings)
+
+	_, err := gm.UpdateSettings(context.Background(), &UpdateSettingsRequest{Namespace: testNamespace, RoomID: testRoomID, Settings: settings})
+	if err != nil {
+		t.Fatalf("failed to update settings: %v", err)
+	}
+}
+
+func optIn(t *testing.T, gm *GameMaster, userIDs ...string) {
+	for _, userID := range userIDs {
+		_, err := gm.OptIn(context.Background(), &OptInRequest{Namespace: testNamespace, RoomID: testRoomID, UserID: userID})
+		if err != nil {
+			t.Fatalf("failed to opt in %s: %v", userID, err)
+		}
+	}
+}
+
+func toss(t *testing.T, gm *GameMaster, actorUserID, targetUserID string) *TossResponse {
+	rsp, err := gm.Toss(context.Background(), &TossRequest{
+		Namespace:    testNamespace,
+		RoomID:       testRoomID,
+		ChannelID:    testChannelID,
+		ActorUserID:  actorUserID,
+		TargetUserID: targetUserID,
+	})
+	if err != nil {
+		t.Fatalf("failed to toss from %s to %s: %v", actorUserID, targetUserID, err)
+	}
+	return rsp
+}
+
+func TestGameMasterToss(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	optIn(t, gm, "holder")
+	rsp := toss(t, gm, "starter", "holder")
+	if rsp.HolderUserID != "holder" || rsp.TargetUserID != "holder" || rsp.Turn != 1 || rsp.Exploded {
+		t.Fatalf("unexpected toss response: %+v", rsp)
+	}
+
+	holder, err := gm.GetHolder(ctx, &GetHolderRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID})
+	if err != nil {
+		t.Fatalf("failed to get holder: %v", err)
+	}
+	if len(holder.Potatoes) != 1 || holder.Potatoes[0].HolderUserID != "holder" {
+		t.Errorf("unexpected potatoes in play: %+v", holder.Potatoes)
+	}
+
+	_, err = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "starter", TargetUserID: "holder"})
+	var notHolder *NotHolderError
+	if !errors.As(err, &notHolder) || notHolder.HolderUserID != "holder" {
+		t.Errorf("tossing without holding the potato returned %v, want NotHolderError", err)
+	}
+
+	_, err = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "holder", TargetUserID: "stranger"})
+	if !errors.As(err, new(*NotOptedInError)) {
+		t.Errorf("tossing to a user who has not opted in returned %v, want NotOptedInError", err)
+	}
+}
+
+func TestGameMasterGetProfile(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	optIn(t, gm, "a", "b", "c")
+	for _, userID := range []string{"b", "a", "b"} {
+		if err := gm.rooms.IncrementDeaths(ctx, testNamespace, testRoomID, userID); err != nil {
+			t.Fatalf("failed to increment deaths of %s: %v", userID, err)
+		}
+	}
+	toss(t, gm, "a", "b")
+
+	tests := []struct {
+		userID string
+		want   GetProfileResponse
+	}{
+		{userID: "a", want: GetProfileResponse{Season: 1, Deaths: 1, Rank: 2, GamesPlayed: 1, FavouriteTargetUserID: "b", FavouriteTargetTosses: 1}},
+		{userID: "b", want: GetProfileResponse{Season: 1, Deaths: 2, Rank: 1, GamesPlayed: 1}},
+		{userID: "c", want: GetProfileResponse{Season: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			rsp, err := gm.GetProfile(ctx, &GetProfileRequest{Namespace: testNamespace, RoomID: testRoomID, UserID: tt.userID})
+			if err != nil {
+				t.Fatalf("failed to get profile: %v", err)
+			}
+			if !reflect.DeepEqual(*rsp, tt.want) {
+				t.Errorf("got profile %+v, want %+v", *rsp, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreePotatoID(t *testing.T) {
+	ongoing := func(potatoID int) *game.Game {
+		return &game.Game{PotatoID: potatoID, PotatoKind: "hot"}
+	}
+	finished := func(potatoID int) *game.Game {
+		return &game.Game{PotatoID: potatoID, PotatoKind: "hot", Finished: true}
+	}
+	retired := func(potatoID int) *game.Game {
+		return &game.Game{PotatoID: potatoID, PotatoKind: "retired"}
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		games    []*game.Game
+		potatoID int
+		want     int
+		ok       bool
+	}{
+		{name: "no games", limit: 3, want: 1, ok: true},
+		{name: "lowest free", limit: 3, games: []*game.Game{ongoing(1), ongoing(3)}, want: 2, ok: true},
+		{name: "finished games are free", limit: 3, games: []*game.Game{finished(1), ongoing(2)}, want: 1, ok: true},
+		{name: "retired potatoes are free", limit: 1, games: []*game.Game{retired(1)}, want: 1, ok: true},
+		{name: "all in play", limit: 2, games: []*game.Game{ongoing(1), ongoing(2)}},
+		{name: "given free", limit: 3, games: []*game.Game{ongoing(1)}, potatoID: 3, want: 3, ok: true},
+		{name: "given in play", limit: 3, games: []*game.Game{ongoing(1)}, potatoID: 1, want: 1},
+		{name: "given over limit", limit: 3, potatoID: 4, want: 4},
+		{name: "team game in play", limit: 3, games: []*game.Game{{PotatoID: 1, PotatoKind: "hot", Teams: map[string]string{"a": "Red", "b": "Blue"}}}},
+		{name: "elimination game in play", limit: 3, games: []*game.Game{{PotatoID: 1, PotatoKind: "hot", Players: []string{"a", "b"}}}, potatoID: 2},
+	}
+
+	gm := &GameMaster{potatoes: DefaultPotatoRegistry()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := gm.freePotatoID(log.NewNopLogger(), &room.Settings{PotatoLimit: tt.limit}, tt.games, tt.potatoID)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("freePotatoID() = %d, %t, want %d, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGameMasterMultiplePotatoes(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, storage)
+			ctx := context.Background()
+
+			optIn(t, gm, "a", "b", "c", "d")
+			updateSettings(t, gm, func(settings *room.Settings) {
+				settings.ExplodeMultiplier = 0
+				settings.PotatoLimit = 2
+			})
+
+			if rsp := toss(t, gm, "a", "b"); rsp.PotatoID != 1 {
+				t.Errorf("first potato has ID %d, want 1", rsp.PotatoID)
+			}
+			if rsp := toss(t, gm, "c", "d"); rsp.PotatoID != 2 {
+				t.Errorf("second potato has ID %d, want 2", rsp.PotatoID)
+			}
+
+			_, err := gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "a", TargetUserID: "c"})
+			if !errors.As(err, new(*NotHolderError)) {
+				t.Errorf("tossing with every potato in play returned %v, want NotHolderError", err)
+			}
+
+			holder, err := gm.GetHolder(ctx, &GetHolderRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID})
+			if err != nil {
+				t.Fatalf("failed to get holder: %v", err)
+			}
+			if len(holder.Potatoes) != 2 {
+				t.Errorf("%d potatoes in play, want 2", len(holder.Potatoes))
+			}
+		})
+	}
+}
+
+func TestGameMasterRetiredPotato(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	optIn(t, gm, "holder")
+	if _, err := gm.games.CreateNewGame(ctx, testNamespace, testRoomID, testChannelID, 1, "unregistered", "starter", 1); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+
+	_, err := gm.GetHolder(ctx, &GetHolderRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID})
+	if !errors.Is(err, ErrNoOngoingGame) {
+		t.Fatalf("getting the holder of a retired potato returned %v, want ErrNoOngoingGame", err)
+	}
+
+	g, err := gm.games.GetGame(ctx, testNamespace, testChannelID, 1)
+	if err != nil {
+		t.Fatalf("failed to get game: %v", err)
+	}
+	if g.Finished {
+		t.Error("getting the holder wrote to the game")
+	}
+
+	if rsp := toss(t, gm, "starter", "holder"); rsp.PotatoID != 1 || rsp.Potato.Kind() == "unregistered" {
+		t.Errorf("toss did not start a fresh game in place of the retired one: %+v", rsp)
+	}
+}
+
+func TestGameMasterSteal(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	optIn(t, gm, "holder")
+	toss(t, gm, "starter", "holder")
+
+	steal := func(actorUserID, targetUserID string) (*StealResponse, error) {
+		return gm.Steal(ctx, &StealRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: actorUserID, TargetUserID: targetUserID})
+	}
+
+	rsp, err := steal("thief", "holder")
+	if err != nil {
+		t.Fatalf("failed to steal: %v", err)
+	}
+	if rsp.HolderUserID != "thief" || rsp.Turn != 2 || rsp.Exploded {
+		t.Fatalf("unexpected steal response: %+v", rsp)
+	}
+
+	if _, err := steal("thief", "thief"); !errors.Is(err, ErrSelfStealUnallowed) {
+		t.Errorf("stealing from self returned %v, want ErrSelfStealUnallowed", err)
+	}
+
+	if _, err := steal("starter", "holder"); !errors.As(err, new(*NotHolderError)) {
+		t.Errorf("stealing from a user without the potato returned %v, want NotHolderError", err)
+	}
+
+	updateSettings(t, gm, func(settings *room.Settings) {
+		settings.StealEnabled = false
+	})
+	if _, err := steal("holder", "thief"); !errors.Is(err, ErrStealDisabled) {
+		t.Errorf("stealing with stealing disabled returned %v, want ErrStealDisabled", err)
+	}
+}
+
+func TestGameMasterCook(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	optIn(t, gm, "holder")
+	toss(t, gm, "starter", "holder")
+
+	cook := func(actorUserID string) (*CookResponse, error) {
+		return gm.Cook(ctx, &CookRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: actorUserID})
+	}
+
+	rsp, err := cook("holder")
+	if err != nil {
+		t.Fatalf("failed to cook: %v", err)
+	}
+	if rsp.HolderUserID != "holder" || rsp.HeatLevel != 2 || rsp.Turn != 2 || rsp.Exploded {
+		t.Fatalf("unexpected cook response: %+v", rsp)
+	}
+
+	if _, err := cook("starter"); !errors.As(err, new(*NotHolderError)) {
+		t.Errorf("cooking without holding the potato returned %v, want NotHolderError", err)
+	}
+
+	updateSettings(t, gm, func(settings *room.Settings) {
+		settings.CookCap = 2
+	})
+	if _, err := cook("holder"); !errors.Is(err, ErrCookCapReached) {
+		t.Errorf("cooking past the cook cap returned %v, want ErrCookCapReached", err)
+	}
+}
+
+func TestGameMasterExplode(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	optIn(t, gm, "a", "b")
+	updateSettings(t, gm, func(settings *room.Settings) {
+		settings.ExplodeMultiplier = room.MaxExplodeMultiplier
+	})
+
+	var rsp *TossResponse
+	actor, target := "a", "b"
+	for i := 0; i < 100; i++ {
+		rsp = toss(t, gm, actor, target)
+		if rsp.Exploded {
+			break
+		}
+		actor, target = target, actor
+	}
+	if !rsp.Exploded {
+		t.Fatal("potato never exploded")
+	}
+
+	profile, err := gm.GetProfile(ctx, &GetProfileRequest{Namespace: testNamespace, RoomID: testRoomID, UserID: rsp.HolderUserID})
+	if err != nil {
+		t.Fatalf("failed to get profile: %v", err)
+	}
+	if profile.Deaths != 1 {
+		t.Errorf("holder has %d deaths, want 1", profile.Deaths)
+	}
+
+	_, err = gm.GetHolder(ctx, &GetHolderRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID})
+	if !errors.Is(err, ErrNoOngoingGame) {
+		t.Errorf("getting the holder after the explosion returned %v, want ErrNoOngoingGame", err)
+	}
+
+	_, err = gm.Steal(ctx, &StealRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "thief", TargetUserID: rsp.HolderUserID})
+	if !errors.Is(err, ErrNoOngoingGame) {
+		t.Errorf("stealing after the explosion returned %v, want ErrNoOngoingGame", err)
+	}
+}
+
+func TestGameMasterFuseDetonation(t *testing.T) {
+	rooms, games, tx := openMemoryStorage(t)
+	gm := NewGameMaster(log.NewNopLogger(), rooms, games, tx, DefaultPotatoRegistry(), NewRandomizer(1), NewFuse(10*time.Millisecond), NewCooldowns(nil))
+	ctx := context.Background()
+
+	optIn(t, gm, "holder")
+	rsp := toss(t, gm, "starter", "holder")
+
+	var d *Detonation
+	select {
+	case d = <-gm.Detonations():
+	case <-time.After(time.Second):
+		t.Fatal("fuse never detonated")
+	}
+	if d.HolderUserID != "holder" || d.PotatoID != rsp.PotatoID {
+		t.Fatalf("unexpected detonation: %+v", d)
+	}
+
+	profile, err := gm.GetProfile(ctx, &GetProfileRequest{Namespace: testNamespace, RoomID: testRoomID, UserID: "holder"})
+	if err != nil {
+		t.Fatalf("failed to get profile: %v", err)
+	}
+	if profile.Deaths != 1 {
+		t.Errorf("holder has %d deaths, want 1", profile.Deaths)
+	}
+
+	g, err := gm.games.GetGame(ctx, testNamespace, testChannelID, d.PotatoID)
+	if err != nil {
+		t.Fatalf("failed to get game: %v", err)
+	}
+
+	turns, err := gm.games.ListTurns(ctx, testNamespace, testChannelID, g.PotatoID, g.Round)
+	if err != nil {
+		t.Fatalf("failed to list turns: %v", err)
+	}
+	if last := turns[len(turns)-1]; last.Action != game.ActionExplode || !last.Exploded || last.ActorUserID != "holder" {
+		t.Errorf("unexpected last turn: %+v", last)
+	}
+}
+
+func TestGameMasterCooldowns(t *testing.T) {
+	rooms, games, tx := openMemoryStorage(t)
+	cooldowns := NewCooldowns(map[game.Action]time.Duration{game.ActionToss: time.Minute})
+	gm := NewGameMaster(log.NewNopLogger(), rooms, games, tx, DefaultPotatoRegistry(), NewRandomizer(1), NewFuse(0), cooldowns)
+	ctx := context.Background()
+
+	optIn(t, gm, "starter", "holder")
+
+	_, err := gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "starter", TargetUserID: "stranger"})
+	if !errors.As(err, new(*NotOptedInError)) {
+		t.Fatalf("tossing to a user who has not opted in returned %v, want NotOptedInError", err)
+	}
+
+	toss(t, gm, "starter", "holder")
+	toss(t, gm, "holder", "starter")
+
+	_, err = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "starter", TargetUserID: "holder"})
+	if !errors.As(err, new(*CooldownError)) {
+		t.Errorf("tossing again within the cooldown returned %v, want CooldownError", err)
+	}
+}
+
+func TestGameMasterStealConcurrently(t *testing.T) {
+	const stealers = 8
+
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, storage)
+			ctx := context.Background()
+
+			optIn(t, gm, "holder")
+			tossed := toss(t, gm, "starter", "holder")
+			if tossed.Exploded {
+				t.Fatal("potato exploded before it could be stolen")
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, stealers)
+			for i := range errs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, errs[i] = gm.Steal(ctx, &StealRequest{
+						Namespace:    testNamespace,
+						RoomID:       testRoomID,
+						ChannelID:    testChannelID,
+						ActorUserID:  fmt.Sprintf("stealer-%d", i),
+						TargetUserID: "holder",
+					})
+				}(i)
+			}
+			wg.Wait()
+
+			var won int
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					won++
+				case errors.As(err, new(*NotHolderError)), errors.Is(err, ErrNoOngoingGame):
+				default:
+					t.Errorf("unexpected error stealing: %v", err)
+				}
+			}
+			if won != 1 {
+				t.Fatalf("%d stealers won the potato, want 1", won)
+			}
+
+			g, err := gm.games.GetGame(ctx, testNamespace, testChannelID, tossed.PotatoID)
+			if err != nil {
+				t.Fatalf("failed to get game: %v", err)
+			}
+			if g.Turns != 2 {
+				t.Errorf("game has %d turns, want 2", g.Turns)
+			}
+
+			turns, err := gm.games.ListTurns(ctx, testNamespace, testChannelID, g.PotatoID, g.Round)
+			if err != nil {
+				t.Fatalf("failed to list turns: %v", err)
+			}
+			if len(turns) != 2 {
+				t.Errorf("%d turns recorded, want 2", len(turns))
+			}
+		})
+	}
+}
+
+// failingRooms is a room.RoomRepository that fails to record stats, which
+// happens after an exploded turn has been played and its death counted.
+type failingRooms struct {
+	room.RoomRepository
+}
+
+var errInjected = errors.New("injected failure")
+
+func (r *failingRooms) RecordStats(ctx context.Context, namespace, roomID string, stats []room.UserStats) error {
+	return errInjected
+}
+
+func TestGameMasterExplodeRollback(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			rooms, games, tx := storage.open(t)
+			gm := NewGameMaster(log.NewNopLogger(), &failingRooms{rooms}, games, tx, DefaultPotatoRegistry(), NewRandomizer(1), NewFuse(0), NewCooldowns(nil))
+			ctx := context.Background()
+
+			optIn(t, gm, "a", "b")
+			updateSettings(t, gm, func(settings *room.Settings) {
+				settings.ExplodeMultiplier = room.MaxExplodeMultiplier
+			})
+
+			var err error
+			var before *game.Game
+			actor, target := "a", "b"
+			for i := 0; i < 100; i++ {
+				_, err = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: actor, TargetUserID: target})
+				if err != nil {
+					break
+				}
+
+				before, err = games.GetGame(ctx, testNamespace, testChannelID, 1)
+				if err != nil {
+					t.Fatalf("failed to get game: %v", err)
+				}
+				actor, target = target, actor
+			}
+			if !errors.Is(err, errInjected) {
+				t.Fatalf("exploding returned %v, want the injected failure", err)
+			}
+
+			after, err := games.GetGame(ctx, testNamespace, testChannelID, 1)
+			if err != nil {
+				t.Fatalf("failed to get game: %v", err)
+			}
+			if after.Finished || after.Turns != before.Turns || after.HolderUserID != before.HolderUserID {
+				t.Errorf("game changed by a failed explosion: got %+v, want %+v", after, before)
+			}
+
+			turns, err := games.ListTurns(ctx, testNamespace, testChannelID, 1, after.Round)
+			if err != nil {
+				t.Fatalf("failed to list turns: %v", err)
+			}
+			if len(turns) != before.Turns {
+				t.Errorf("%d turns recorded, want %d", len(turns), before.Turns)
+			}
+
+			r, err := rooms.GetRoom(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to get room: %v", err)
+			}
+			if len(r.DeathCount) != 0 {
+				t.Errorf("deaths counted by a failed explosion: %+v", r.DeathCount)
+			}
+		})
+	}
+}
+
+func TestGameMasterEliminationWithIntruder(t *testing.T) {
+	players := []string{"a", "b", "c", "d"}
+
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, storage)
+			ctx := context.Background()
+
+			updateSettings(t, gm, func(settings *room.Settings) {
+				settings.ExplodeMultiplier = room.MaxExplodeMultiplier
+			})
+
+			for _, userID := range players {
+				_, err := gm.JoinLobby(ctx, &JoinLobbyRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, UserID: userID})
+				if err != nil {
+					t.Fatalf("failed to join lobby: %v", err)
+				}
+			}
+
+			started, err := gm.StartElimination(ctx, &StartEliminationRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID})
+			if err != nil {
+				t.Fatalf("failed to start elimination: %v", err)
+			}
+
+			// The intruder keeps trying to start a game of their own with the
+			// potato, which must never get in between an explosion and the
+			// fresh potato being handed to the players left standing.
+			done := make(chan struct{})
+			intruded := make(chan struct{})
+			go func() {
+				defer close(intruded)
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "intruder", TargetUserID: "a", PotatoID: 1})
+				}
+			}()
+			defer func() {
+				close(done)
+				<-intruded
+			}()
+
+			holder, standing := started.HolderUserID, started.Players
+			for i := 0; i < 500; i++ {
+				var target string
+				for _, userID := range standing {
+					if userID != holder {
+						target = userID
+						break
+					}
+				}
+
+				rsp := toss(t, gm, holder, target)
+				if !rsp.Exploded {
+					holder = rsp.HolderUserID
+					continue
+				}
+
+				update := rsp.Elimination
+				if update == nil {
+					t.Fatal("explosion made no progress in the elimination game")
+				}
+				if update.WinnerUserID != "" {
+					return
+				}
+				if update.HolderUserID == "" {
+					t.Fatalf("no fresh potato handed out with %d players left standing", len(update.Players))
+				}
+				holder, standing = update.HolderUserID, update.Players
+			}
+
+			t.Fatal("elimination game was never won")
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package hotpotato
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+const (
+	ItemShield   = "shield"
+	ItemDodge    = "dodge"
+	ItemOvenMitt = "oven-mitt"
+)
+
+const itemDropChance = 10
+
+var errItemConflict = errors.New("item was used by a concurrent turn")
+
+// Item is a power-up that players earn by surviving turns.
+type Item struct {
+	ID          string
+	Name        string
+	Emoji       string
+	Description string
+}
+
+func (i Item) String() string {
+	return fmt.Sprintf("%s %s", i.Emoji, i.Name)
+}
+
+var DefaultItems = []Item{
+	{ID: ItemShield, Name: "Shield", Emoji: "🛡️", Description: "Absorbs the next explosion in your hands"},
+	{ID: ItemDodge, Name: "Dodge", Emoji: "💨", Description: "Bounces the next potato tossed to you back to whoever tossed it"},
+	{ID: ItemOvenMitt, Name: "Oven Mitt", Emoji: "🧤", Description: "Halves the odds of the next potato you're handed exploding"},
+}
+
+// ItemUpdate is the items used up and earned on a turn by whoever was left
+// holding the potato.
+type ItemUpdate struct {
+	UserID string
+	Used   []Item
+	Earned *Item
+}
+
+func (gm *GameMaster) item(id string) (Item, bool) {
+	for _, item := range gm.items {
+		if item.ID == id {
+			return item, true
+		}
+	}
+
+	return Item{}, false
+}
+
+func (gm *GameMaster) armedItems(ctx context.Context, namespace, roomID, userID string) (map[string]bool, error) {
+	items, err := gm.rooms.ListItems(ctx, namespace, roomID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items: %w", err)
+	}
+
+	armed := make(map[string]bool)
+	for _, item := range items {
+		if item.Armed {
+			armed[item.ID] = true
+		}
+	}
+
+	return armed, nil
+}
+
+func (gm *GameMaster) useItems(ctx context.Context, logger log.Logger, namespace, roomID, userID string, itemIDs []string) ([]Item, error) {
+	var used []Item
+	for _, id := range itemIDs {
+		disarmed, err := gm.rooms.DisarmItem(ctx, namespace, roomID, userID, id)
+		if err != nil {
+			return used, fmt.Errorf("error disarming item '%s': %w", id, err)
+		}
+		if !disarmed {
+			return used, errItemConflict
+		}
+
+		if item, ok := gm.item(id); ok {
+			level.Info(logger).Log("event", "item.used", "item", id)
+			used = append(used, item)
+		}
+	}
+
+	return used, nil
+}
+
+func (gm *GameMaster) earnItem(ctx context.Context, logger log.Logger, g *game.Game) (*Item, error) {
+	if g.Finished || len(gm.items) == 0 || gm.random.Intn(100) >= itemDropChance {
+		return nil, nil
+	}
+
+	item := gm.items[gm.random.Intn(len(gm.items))]
+	if err := gm.rooms.AddItem(ctx, g.Namespace, g.RoomID, g.HolderUserID, item.ID); err != nil {
+		return nil, fmt.Errorf("error adding item: %w", err)
+	}
+	level.Info(logger).Log("event", "item.earned", "item", item.ID)
+
+	return &item, nil
+}
+
+func (gm *GameMaster) settleItems(ctx context.Context, logger log.Logger, g *game.Game, itemIDs []string) (*ItemUpdate, error) {
+	used, err := gm.useItems(ctx, logger, g.Namespace, g.RoomID, g.HolderUserID, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	earned, err := gm.earnItem(ctx, logger, g)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(used) == 0 && earned == nil {
+		return nil, nil
+	}
+
+	return &ItemUpdate{
+		UserID: g.HolderUserID,
+		Used:   used,
+		Earned: earned,
+	}, nil
+}
+
+func (gm *GameMaster) dodges(ctx context.Context, r *room.Room, actorUserID, targetUserID string) (bool, error) {
+	if actorUserID == targetUserID {
+		return false, nil
+	}
+
+	armed, err := gm.armedItems(ctx, r.Namespace, r.ID, targetUserID)
+	if err != nil {
+		return false, err
+	}
+
+	return armed[ItemDodge], nil
+}
@@ -0,0 +1,152 @@
+package hotpotato
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// giveItem adds an item for the user and arms it.
+func giveItem(t *testing.T, gm *GameMaster, userID, itemID string) {
+	ctx := context.Background()
+
+	if err := gm.rooms.AddItem(ctx, testNamespace, testRoomID, userID, itemID); err != nil {
+		t.Fatalf("failed to add item: %v", err)
+	}
+
+	_, err := gm.UseItem(ctx, &UseItemRequest{Namespace: testNamespace, RoomID: testRoomID, UserID: userID, ItemID: itemID})
+	if err != nil {
+		t.Fatalf("failed to use item: %v", err)
+	}
+}
+
+func TestEarnItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		rolls    []int
+		finished bool
+		want     string
+	}{
+		{name: "dropped", rolls: []int{0, 0}, want: DefaultItems[0].ID},
+		{name: "dropped at chance", rolls: []int{itemDropChance - 1, 2}, want: DefaultItems[2].ID},
+		{name: "not dropped", rolls: []int{itemDropChance}},
+		{name: "exploded", rolls: []int{0, 0}, finished: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, memoryStorage)
+			gm.random = &sequenceRandomizer{values: tt.rolls}
+			ctx := context.Background()
+
+			optIn(t, gm, "holder")
+
+			g := &game.Game{Namespace: testNamespace, RoomID: testRoomID, HolderUserID: "holder", Finished: tt.finished}
+			earned, err := gm.earnItem(ctx, log.NewNopLogger(), g)
+			if err != nil {
+				t.Fatalf("failed to earn item: %v", err)
+			}
+
+			var got string
+			if earned != nil {
+				got = earned.ID
+			}
+			if got != tt.want {
+				t.Fatalf("earned item %q, want %q", got, tt.want)
+			}
+
+			items, err := gm.rooms.ListItems(ctx, testNamespace, testRoomID, "holder")
+			if err != nil {
+				t.Fatalf("failed to list items: %v", err)
+			}
+			if tt.want == "" && len(items) != 0 || tt.want != "" && (len(items) != 1 || items[0].ID != tt.want || items[0].Count != 1) {
+				t.Errorf("unexpected items held: %+v", items)
+			}
+		})
+	}
+}
+
+func TestGameMasterDodge(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, storage)
+			ctx := context.Background()
+
+			optIn(t, gm, "holder", "dodger")
+			toss(t, gm, "starter", "holder")
+			giveItem(t, gm, "dodger", ItemDodge)
+
+			rsp := toss(t, gm, "holder", "dodger")
+			if !rsp.Dodged || rsp.TargetUserID != "dodger" || rsp.HolderUserID != "holder" {
+				t.Fatalf("unexpected toss response: %+v", rsp)
+			}
+
+			turns, err := gm.games.ListTurns(ctx, testNamespace, testChannelID, rsp.PotatoID, 0)
+			if err != nil {
+				t.Fatalf("failed to list turns: %v", err)
+			}
+			if last := turns[len(turns)-1]; last.ActorUserID != "dodger" || last.TargetUserID != "holder" {
+				t.Errorf("dodged toss recorded as %+v", last)
+			}
+
+			armed, err := gm.armedItems(ctx, testNamespace, testRoomID, "dodger")
+			if err != nil {
+				t.Fatalf("failed to list armed items: %v", err)
+			}
+			if armed[ItemDodge] {
+				t.Error("dodge still armed after it was used")
+			}
+
+			if rsp := toss(t, gm, "holder", "dodger"); rsp.Dodged || rsp.HolderUserID != "dodger" {
+				t.Errorf("toss after the dodge was used up: %+v", rsp)
+			}
+		})
+	}
+}
+
+func TestGameMasterDodgeConcurrently(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, storage)
+			ctx := context.Background()
+
+			updateSettings(t, gm, func(settings *room.Settings) {
+				settings.PotatoLimit = 2
+			})
+			optIn(t, gm, "a", "b", "dodger")
+			toss(t, gm, "starter", "a")
+			toss(t, gm, "starter", "b")
+			giveItem(t, gm, "dodger", ItemDodge)
+
+			var wg sync.WaitGroup
+			rsps := make([]*TossResponse, 2)
+			errs := make([]error, 2)
+			for i, actor := range []string{"a", "b"} {
+				wg.Add(1)
+				go func(i int, actor string) {
+					defer wg.Done()
+					rsps[i], errs[i] = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: actor, TargetUserID: "dodger"})
+				}(i, actor)
+			}
+			wg.Wait()
+
+			var dodged int
+			for i, rsp := range rsps {
+				if errs[i] != nil {
+					t.Fatalf("failed to toss: %v", errs[i])
+				}
+				if rsp.Dodged {
+					dodged++
+				}
+			}
+			if dodged != 1 {
+				t.Errorf("one dodge bounced %d tosses, want 1", dodged)
+			}
+		})
+	}
+}
@@ -6,21 +6,97 @@ import (
 	"github.com/jace-ys/hot-potato-discord/internal/room"
 )
 
-type UserDeaths struct {
+// Metric is a stat that users can be ranked by on the leaderboard.
+type Metric string
+
+const (
+	MetricDeaths            Metric = "deaths"
+	MetricGamesPlayed       Metric = "games-played"
+	MetricTosses            Metric = "tosses"
+	MetricSteals            Metric = "steals"
+	MetricCooks             Metric = "cooks"
+	MetricSurvivalRate      Metric = "survival-rate"
+	MetricLongestHoldStreak Metric = "longest-hold-streak"
+	MetricKills             Metric = "kills"
+)
+
+var Metrics = []Metric{
+	MetricDeaths,
+	MetricGamesPlayed,
+	MetricTosses,
+	MetricSteals,
+	MetricCooks,
+	MetricSurvivalRate,
+	MetricLongestHoldStreak,
+	MetricKills,
+}
+
+func (m Metric) Valid() bool {
+	for _, metric := range Metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// UserScore is a user's value for the metric a leaderboard is ranked by.
+// Survival rates are given as a percentage.
+type UserScore struct {
 	UserID string
-	Count  int
+	Value  float64
+}
+
+type Scoreboard []UserScore
+
+func (sb Scoreboard) Len() int      { return len(sb) }
+func (sb Scoreboard) Swap(i, j int) { sb[i], sb[j] = sb[j], sb[i] }
+
+// Less ranks users by their score, breaking ties by user ID so that the order
+// is stable across pages.
+func (sb Scoreboard) Less(i, j int) bool {
+	if sb[i].Value != sb[j].Value {
+		return sb[i].Value > sb[j].Value
+	}
+	return sb[i].UserID < sb[j].UserID
 }
 
-type Scoreboard []UserDeaths
+// Paginate splits the scoreboard into pages of the given size and returns the
+// given page, along with its page number and the total number of pages. Pages
+// past the end of the scoreboard give the last page.
+func (sb Scoreboard) Paginate(page, size int) (Scoreboard, int, int) {
+	pages := (len(sb) + size - 1) / size
+	if pages == 0 {
+		pages = 1
+	}
 
-func (sb Scoreboard) Len() int           { return len(sb) }
-func (sb Scoreboard) Swap(i, j int)      { sb[i], sb[j] = sb[j], sb[i] }
-func (sb Scoreboard) Less(i, j int) bool { return sb[i].Count > sb[j].Count }
+	if page >= pages {
+		page = pages - 1
+	}
 
-func BuildLeaderboard(counters []room.DeathCounter, top ...int) Scoreboard {
-	leaderboard := make(Scoreboard, len(counters))
-	for i, counter := range counters {
-		leaderboard[i] = UserDeaths{counter.UserID, counter.Count}
+	start := page * size
+	end := start + size
+	if end > len(sb) {
+		end = len(sb)
+	}
+
+	return sb[start:end], page, pages
+}
+
+func BuildLeaderboard(metric Metric, counters []room.DeathCounter, stats []room.UserStats, top ...int) Scoreboard {
+	var leaderboard Scoreboard
+	if metric == MetricDeaths {
+		leaderboard = make(Scoreboard, len(counters))
+		for i, counter := range counters {
+			leaderboard[i] = UserScore{counter.UserID, float64(counter.Count)}
+		}
+	} else {
+		leaderboard = make(Scoreboard, 0, len(stats))
+		for _, s := range stats {
+			if score, ok := metricValue(metric, s); ok {
+				leaderboard = append(leaderboard, UserScore{s.UserID, score})
+			}
+		}
 	}
 
 	sort.Sort(leaderboard)
@@ -34,3 +110,27 @@ func BuildLeaderboard(counters []room.DeathCounter, top ...int) Scoreboard {
 
 	return leaderboard
 }
+
+func metricValue(metric Metric, s room.UserStats) (float64, bool) {
+	switch metric {
+	case MetricGamesPlayed:
+		return float64(s.GamesPlayed), true
+	case MetricTosses:
+		return float64(s.Tosses), true
+	case MetricSteals:
+		return float64(s.Steals), true
+	case MetricCooks:
+		return float64(s.Cooks), true
+	case MetricSurvivalRate:
+		if s.GamesPlayed == 0 {
+			return 0, false
+		}
+		return float64(s.GamesSurvived) / float64(s.GamesPlayed) * 100, true
+	case MetricLongestHoldStreak:
+		return float64(s.LongestHoldStreak), true
+	case MetricKills:
+		return float64(s.Kills), true
+	default:
+		return 0, false
+	}
+}
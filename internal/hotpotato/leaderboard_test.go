@@ -0,0 +1,37 @@
+package hotpotato
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScoreboardPaginate(t *testing.T) {
+	scoreboard := Scoreboard{{"a", 5}, {"b", 4}, {"c", 3}, {"d", 2}, {"e", 1}}
+
+	tests := []struct {
+		name       string
+		scoreboard Scoreboard
+		page       int
+		size       int
+		want       Scoreboard
+		wantPage   int
+		wantPages  int
+	}{
+		{name: "first page", scoreboard: scoreboard, page: 0, size: 2, want: scoreboard[0:2], wantPage: 0, wantPages: 3},
+		{name: "middle page", scoreboard: scoreboard, page: 1, size: 2, want: scoreboard[2:4], wantPage: 1, wantPages: 3},
+		{name: "partial last page", scoreboard: scoreboard, page: 2, size: 2, want: scoreboard[4:5], wantPage: 2, wantPages: 3},
+		{name: "past the end", scoreboard: scoreboard, page: 7, size: 2, want: scoreboard[4:5], wantPage: 2, wantPages: 3},
+		{name: "exact pages", scoreboard: scoreboard[:4], page: 1, size: 2, want: scoreboard[2:4], wantPage: 1, wantPages: 2},
+		{name: "single page", scoreboard: scoreboard, page: 0, size: 10, want: scoreboard, wantPage: 0, wantPages: 1},
+		{name: "empty", scoreboard: Scoreboard{}, page: 3, size: 10, want: Scoreboard{}, wantPage: 0, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, page, pages := tt.scoreboard.Paginate(tt.page, tt.size)
+			if !reflect.DeepEqual(got, tt.want) || page != tt.wantPage || pages != tt.wantPages {
+				t.Errorf("Paginate(%d, %d) = %v, %d, %d, want %v, %d, %d", tt.page, tt.size, got, page, pages, tt.want, tt.wantPage, tt.wantPages)
+			}
+		})
+	}
+}
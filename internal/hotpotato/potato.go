@@ -1,90 +1,103 @@
 package hotpotato
 
 import (
+	"errors"
 	"fmt"
 	"math"
-	"math/rand"
 )
 
 type Potato interface {
 	fmt.Stringer
 	Kind() string
 	PercentChance() int
+	HeatMultiplier() float64
 }
 
 func (gm *GameMaster) GetPotato(kind string) (Potato, error) {
-	for _, potato := range gm.potatoes {
-		if potato.Kind() == kind {
-			return potato, nil
-		}
-	}
-
-	return nil, ErrInvalidPotatoKind
+	return gm.potatoes.Get(kind)
 }
 
-func (gm *GameMaster) RandomPotato() Potato {
-	i := rand.Intn(len(gm.potatoes))
-	return gm.potatoes[i]
+func (gm *GameMaster) RandomPotato(kinds ...string) Potato {
+	return gm.potatoes.Random(gm.random, kinds...)
 }
 
-func (gm *GameMaster) DecideExplode(potato Potato, turn, heatLevel int) bool {
-	inc := math.Log10(math.Pow(float64(heatLevel), float64(turn)))
+// ExplodeChance returns the percent chance of the potato exploding on the given
+// turn, rising with the heat level and scaled by the room's explode multiplier.
+func (gm *GameMaster) ExplodeChance(potato Potato, turn, heatLevel int, multiplier float64) int {
+	inc := math.Log10(math.Pow(float64(heatLevel), float64(turn))) * potato.HeatMultiplier()
 	chance := potato.PercentChance() + int(inc)
-	return rand.Intn(100) <= chance
-}
-
-type RawPotato struct{}
-
-func (p RawPotato) String() string {
-	return "raw potato"
-}
-
-func (p RawPotato) Kind() string {
-	return "raw"
-}
-
-func (p RawPotato) PercentChance() int {
-	return 1
+	return int(math.Round(float64(chance) * multiplier))
 }
 
-type BakedPotato struct{}
-
-func (p BakedPotato) String() string {
-	return "baked 🔥 potato 🥔"
-}
+// DecideExplode decides whether the potato explodes in the hands of a holder
+// with the given items armed, returning the IDs of the items that took effect.
+func (gm *GameMaster) DecideExplode(random Randomizer, chance int, armed map[string]bool) (bool, []string) {
+	var used []string
+	if armed[ItemOvenMitt] {
+		chance /= 2
+		used = append(used, ItemOvenMitt)
+	}
 
-func (p BakedPotato) Kind() string {
-	return "baked"
-}
+	exploded := random.Intn(100) <= chance
+	if exploded && armed[ItemShield] {
+		exploded = false
+		used = append(used, ItemShield)
+	}
 
-func (p BakedPotato) PercentChance() int {
-	return 2
+	return exploded, used
+}
+
+type PotatoDefinition struct {
+	Kind           string   `json:"kind" yaml:"kind"`
+	Display        string   `json:"display" yaml:"display"`
+	PercentChance  int      `json:"percentChance" yaml:"percentChance"`
+	SpawnWeight    int      `json:"spawnWeight" yaml:"spawnWeight"`
+	HeatMultiplier *float64 `json:"heatMultiplier,omitempty" yaml:"heatMultiplier,omitempty"`
+}
+
+func (d PotatoDefinition) Validate() error {
+	switch {
+	case d.Kind == "":
+		return errors.New("missing kind")
+	case d.Display == "":
+		return errors.New("missing display string")
+	case d.PercentChance < 0 || d.PercentChance > 100:
+		return errors.New("percent chance must be between 0 and 100")
+	case d.SpawnWeight < 0:
+		return errors.New("spawn weight cannot be negative")
+	case d.HeatMultiplier != nil && *d.HeatMultiplier < 0:
+		return errors.New("heat multiplier cannot be negative")
+	default:
+		return nil
+	}
 }
 
-type HotPotato struct{}
-
-func (p HotPotato) String() string {
-	return "hot 🔥🔥 potato 🥔"
+var DefaultPotatoDefinitions = []PotatoDefinition{
+	{Kind: "raw", Display: "raw potato", PercentChance: 1, SpawnWeight: 1},
+	{Kind: "baked", Display: "baked 🔥 potato 🥔", PercentChance: 2, SpawnWeight: 1},
+	{Kind: "hot", Display: "hot 🔥🔥 potato 🥔", PercentChance: 5, SpawnWeight: 1},
+	{Kind: "burnt", Display: "burnt 🔥🔥🔥 potato 🥔", PercentChance: 10, SpawnWeight: 1},
 }
 
-func (p HotPotato) Kind() string {
-	return "hot"
+type definedPotato struct {
+	definition PotatoDefinition
 }
 
-func (p HotPotato) PercentChance() int {
-	return 5
+func (p definedPotato) String() string {
+	return p.definition.Display
 }
 
-type BurntPotato struct{}
-
-func (p BurntPotato) String() string {
-	return "burnt 🔥🔥🔥 potato 🥔"
+func (p definedPotato) Kind() string {
+	return p.definition.Kind
 }
 
-func (p BurntPotato) Kind() string {
-	return "burnt"
+func (p definedPotato) PercentChance() int {
+	return p.definition.PercentChance
 }
 
-func (p BurntPotato) PercentChance() int {
-	return 10
+func (p definedPotato) HeatMultiplier() float64 {
+	if p.definition.HeatMultiplier == nil {
+		return 1
+	}
+	return *p.definition.HeatMultiplier
 }
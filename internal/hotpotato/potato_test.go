@@ -0,0 +1,108 @@
+package hotpotato
+
+import (
+	"reflect"
+	"testing"
+)
+
+// sequenceRandomizer hands out the given values in order, so that tests can
+// decide the outcome of every roll.
+type sequenceRandomizer struct {
+	values []int
+}
+
+func (r *sequenceRandomizer) Intn(n int) int {
+	v := r.values[0]
+	r.values = r.values[1:]
+	return v % n
+}
+
+func (r *sequenceRandomizer) Int63() int64 {
+	return int64(r.Intn(1 << 31))
+}
+
+func TestExplodeChance(t *testing.T) {
+	potatoes := DefaultPotatoRegistry()
+	scorching, steady := 3.0, 0.0
+	scorchingPotato := definedPotato{PotatoDefinition{Kind: "scorching", Display: "scorching potato", PercentChance: 5, SpawnWeight: 1, HeatMultiplier: &scorching}}
+	steadyPotato := definedPotato{PotatoDefinition{Kind: "steady", Display: "steady potato", PercentChance: 5, SpawnWeight: 1, HeatMultiplier: &steady}}
+
+	tests := []struct {
+		name       string
+		kind       string
+		potato     Potato
+		turn       int
+		heatLevel  int
+		multiplier float64
+		want       int
+	}{
+		{name: "raw", kind: "raw", turn: 1, heatLevel: 1, multiplier: 1, want: 1},
+		{name: "baked", kind: "baked", turn: 1, heatLevel: 1, multiplier: 1, want: 2},
+		{name: "hot", kind: "hot", turn: 1, heatLevel: 1, multiplier: 1, want: 5},
+		{name: "burnt", kind: "burnt", turn: 1, heatLevel: 1, multiplier: 1, want: 10},
+		{name: "heat rises with turns", kind: "hot", turn: 2, heatLevel: 10, multiplier: 1, want: 7},
+		{name: "heat truncates", kind: "raw", turn: 3, heatLevel: 4, multiplier: 1, want: 2},
+		{name: "heat multiplier", potato: scorchingPotato, turn: 2, heatLevel: 10, multiplier: 1, want: 11},
+		{name: "heat multiplier disables", potato: steadyPotato, turn: 2, heatLevel: 10, multiplier: 1, want: 5},
+		{name: "settings multiplier", kind: "hot", turn: 2, heatLevel: 10, multiplier: 2, want: 14},
+		{name: "settings multiplier rounds", kind: "raw", turn: 1, heatLevel: 1, multiplier: 1.5, want: 2},
+		{name: "settings multiplier halves", kind: "burnt", turn: 1, heatLevel: 1, multiplier: 0.5, want: 5},
+		{name: "settings multiplier disables", kind: "burnt", turn: 5, heatLevel: 10, multiplier: 0, want: 0},
+		{name: "heat and settings multipliers", potato: scorchingPotato, turn: 2, heatLevel: 10, multiplier: 3, want: 33},
+	}
+
+	gm := &GameMaster{potatoes: potatoes}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			potato := tt.potato
+			if potato == nil {
+				var err error
+				potato, err = potatoes.Get(tt.kind)
+				if err != nil {
+					t.Fatalf("failed to get potato: %v", err)
+				}
+			}
+
+			if got := gm.ExplodeChance(potato, tt.turn, tt.heatLevel, tt.multiplier); got != tt.want {
+				t.Errorf("ExplodeChance() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecideExplode(t *testing.T) {
+	tests := []struct {
+		name     string
+		roll     int
+		chance   int
+		armed    map[string]bool
+		exploded bool
+		used     []string
+	}{
+		{name: "roll under chance", roll: 4, chance: 5, exploded: true},
+		{name: "roll at chance", roll: 5, chance: 5, exploded: true},
+		{name: "roll over chance", roll: 6, chance: 5, exploded: false},
+		{name: "zero chance", roll: 1, chance: 0, exploded: false},
+		{name: "oven mitt halves chance", roll: 6, chance: 10, armed: map[string]bool{ItemOvenMitt: true}, exploded: false, used: []string{ItemOvenMitt}},
+		{name: "oven mitt used on explosion", roll: 5, chance: 10, armed: map[string]bool{ItemOvenMitt: true}, exploded: true, used: []string{ItemOvenMitt}},
+		{name: "shield absorbs explosion", roll: 0, chance: 10, armed: map[string]bool{ItemShield: true}, exploded: false, used: []string{ItemShield}},
+		{name: "shield kept without explosion", roll: 50, chance: 10, armed: map[string]bool{ItemShield: true}, exploded: false},
+		{name: "oven mitt and shield", roll: 3, chance: 10, armed: map[string]bool{ItemOvenMitt: true, ItemShield: true}, exploded: false, used: []string{ItemOvenMitt, ItemShield}},
+		{name: "dodge has no effect", roll: 0, chance: 10, armed: map[string]bool{ItemDodge: true}, exploded: true},
+	}
+
+	gm := &GameMaster{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			random := &sequenceRandomizer{values: []int{tt.roll}}
+
+			exploded, used := gm.DecideExplode(random, tt.chance, tt.armed)
+			if exploded != tt.exploded {
+				t.Errorf("DecideExplode() exploded = %t, want %t", exploded, tt.exploded)
+			}
+			if !reflect.DeepEqual(used, tt.used) {
+				t.Errorf("DecideExplode() used = %v, want %v", used, tt.used)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package hotpotato
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Randomizer is the source of randomness used to decide how games play out.
+type Randomizer interface {
+	Intn(n int) int
+	Int63() int64
+}
+
+type lockedRandomizer struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewRandomizer returns a Randomizer seeded with the given seed that is safe
+// for concurrent use.
+func NewRandomizer(seed int64) Randomizer {
+	return &lockedRandomizer{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (r *lockedRandomizer) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Intn(n)
+}
+
+func (r *lockedRandomizer) Int63() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Int63()
+}
+
+// turnRandomizer derives the randomness for a single turn from the seed the
+// game was created with, so that any game can be replayed from its seed.
+func turnRandomizer(seed int64, turn int) Randomizer {
+	return NewRandomizer(seed + int64(turn))
+}
@@ -0,0 +1,138 @@
+package hotpotato
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+type PotatoRegistry struct {
+	potatoes []Potato
+	weights  []int
+	total    int
+}
+
+func NewPotatoRegistry(definitions []PotatoDefinition) (*PotatoRegistry, error) {
+	if len(definitions) == 0 {
+		return nil, errors.New("no potatoes defined")
+	}
+
+	r := &PotatoRegistry{}
+	kinds := make(map[string]bool)
+	for i, definition := range definitions {
+		if err := definition.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid potato definition at index %d: %w", i, err)
+		}
+
+		if kinds[definition.Kind] {
+			return nil, fmt.Errorf("duplicate potato kind '%s'", definition.Kind)
+		}
+		kinds[definition.Kind] = true
+
+		r.potatoes = append(r.potatoes, definedPotato{definition})
+		r.weights = append(r.weights, definition.SpawnWeight)
+		r.total += definition.SpawnWeight
+	}
+
+	if r.total == 0 {
+		return nil, errors.New("at least one potato must have a positive spawn weight")
+	}
+
+	return r, nil
+}
+
+func DefaultPotatoRegistry() *PotatoRegistry {
+	r, err := NewPotatoRegistry(DefaultPotatoDefinitions)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+type potatoesFile struct {
+	Potatoes []PotatoDefinition `json:"potatoes" yaml:"potatoes"`
+}
+
+// LoadPotatoRegistry reads potato definitions from a YAML or JSON file, as
+// determined by the file extension.
+func LoadPotatoRegistry(path string) (*PotatoRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file potatoesFile
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported potatoes file extension '%s'", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding potatoes file: %w", err)
+	}
+
+	return NewPotatoRegistry(file.Potatoes)
+}
+
+func (r *PotatoRegistry) Get(kind string) (Potato, error) {
+	for _, potato := range r.potatoes {
+		if potato.Kind() == kind {
+			return potato, nil
+		}
+	}
+
+	return nil, ErrInvalidPotatoKind
+}
+
+func (r *PotatoRegistry) Has(kind string) bool {
+	_, err := r.Get(kind)
+	return err == nil
+}
+
+// Random picks a potato of the given kinds, if any, weighted by spawn weight.
+func (r *PotatoRegistry) Random(random Randomizer, kinds ...string) Potato {
+	potatoes, weights, total := r.potatoes, r.weights, r.total
+	if len(kinds) > 0 {
+		potatoes, weights, total = r.filter(kinds)
+		if total == 0 {
+			potatoes, weights, total = r.potatoes, r.weights, r.total
+		}
+	}
+
+	n := random.Intn(total)
+	for i, weight := range weights {
+		if n < weight {
+			return potatoes[i]
+		}
+		n -= weight
+	}
+
+	return potatoes[len(potatoes)-1]
+}
+
+func (r *PotatoRegistry) filter(kinds []string) ([]Potato, []int, int) {
+	allowed := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		allowed[kind] = true
+	}
+
+	var potatoes []Potato
+	var weights []int
+	var total int
+	for i, potato := range r.potatoes {
+		if allowed[potato.Kind()] {
+			potatoes = append(potatoes, potato)
+			weights = append(weights, r.weights[i])
+			total += r.weights[i]
+		}
+	}
+
+	return potatoes, weights, total
+}
@@ -0,0 +1,122 @@
+package hotpotato
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// checkOptedIn enforces that potatoes are only tossed to users who have opted
+// in to play in the room. Players drawn into a tournament heat or still
+// standing in an elimination game have already signed up to play, so they can
+// be tossed to regardless.
+func (gm *GameMaster) checkOptedIn(ctx context.Context, r *room.Room, heat *room.Heat, games []*game.Game, targetUserID string) error {
+	if heat != nil {
+		return nil
+	}
+
+	for _, g := range games {
+		if g.EliminationGame() && g.Playing(targetUserID) {
+			return nil
+		}
+	}
+
+	ok, err := gm.rooms.IsOptedIn(ctx, r.Namespace, r.ID, targetUserID)
+	if err != nil {
+		return fmt.Errorf("error checking participation: %w", err)
+	}
+
+	if !ok {
+		return &NotOptedInError{targetUserID}
+	}
+
+	return nil
+}
+
+// recentPlayersLimit is how many of the players most recently active in a
+// channel a random toss picks its target from.
+const recentPlayersLimit = 10
+
+// pickTarget picks the target of a random toss uniformly from the players that
+// the actor could toss the potato to: the other players of a tournament heat,
+// elimination game or opposing team, or otherwise the opted in players most
+// recently active in the channel, falling back to the room's whole opt-in
+// roster. Bots never make it into any of these, as they cannot opt in or be
+// drawn into games.
+func (gm *GameMaster) pickTarget(ctx context.Context, logger log.Logger, r *room.Room, heat *room.Heat, games []*game.Game, channelID string, potatoID int, actorUserID string) (string, error) {
+	var candidates []string
+	switch g, _ := gm.findGame(logger, games, potatoID, actorUserID); {
+	case heat != nil:
+		candidates = heat.Players
+	case g != nil && g.EliminationGame():
+		candidates = g.Players
+	case g != nil && g.TeamGame():
+		for userID, team := range g.Teams {
+			if team != g.Teams[actorUserID] {
+				candidates = append(candidates, userID)
+			}
+		}
+		sort.Strings(candidates)
+	default:
+		var err error
+		candidates, err = gm.activeParticipants(ctx, r, channelID, actorUserID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var targets []string
+	for _, userID := range candidates {
+		if userID != actorUserID {
+			targets = append(targets, userID)
+		}
+	}
+
+	if len(targets) == 0 {
+		return "", ErrNoTossTarget
+	}
+
+	return targets[gm.random.Intn(len(targets))], nil
+}
+
+// activeParticipants lists the opted in players most recently active in the
+// channel other than the given user, or the room's whole opt-in roster if none
+// of them have been.
+func (gm *GameMaster) activeParticipants(ctx context.Context, r *room.Room, channelID, excludeUserID string) ([]string, error) {
+	participants, err := gm.rooms.ListParticipants(ctx, r.Namespace, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing participants: %w", err)
+	}
+
+	optedIn := make(map[string]bool, len(participants))
+	for _, userID := range participants {
+		optedIn[userID] = true
+	}
+
+	active, err := gm.games.ListActivePlayers(ctx, r.Namespace, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing active players: %w", err)
+	}
+
+	var recent []string
+	for _, userID := range active {
+		if len(recent) == recentPlayersLimit {
+			break
+		}
+
+		if optedIn[userID] && userID != excludeUserID {
+			recent = append(recent, userID)
+		}
+	}
+
+	if len(recent) == 0 {
+		return participants, nil
+	}
+
+	return recent, nil
+}
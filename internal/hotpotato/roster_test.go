@@ -0,0 +1,139 @@
+package hotpotato
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+func TestCheckOptedIn(t *testing.T) {
+	elimination := &game.Game{PotatoID: 1, PotatoKind: "hot", HolderUserID: "a", Players: []string{"a", "player"}}
+
+	tests := []struct {
+		name       string
+		target     string
+		heat       *room.Heat
+		games      []*game.Game
+		notOptedIn bool
+	}{
+		{name: "opted in", target: "in"},
+		{name: "never opted in", target: "stranger", notOptedIn: true},
+		{name: "opted out", target: "out", notOptedIn: true},
+		{name: "heat player", target: "player", heat: &room.Heat{Round: 1, Number: 1, Players: []string{"a", "player"}}},
+		{name: "elimination player", target: "player", games: []*game.Game{elimination}},
+		{name: "not in elimination", target: "stranger", games: []*game.Game{elimination}, notOptedIn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, memoryStorage)
+			ctx := context.Background()
+
+			optIn(t, gm, "in", "out")
+			if _, err := gm.OptOut(ctx, &OptOutRequest{Namespace: testNamespace, RoomID: testRoomID, UserID: "out"}); err != nil {
+				t.Fatalf("failed to opt out: %v", err)
+			}
+
+			r, err := gm.rooms.GetRoom(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to get room: %v", err)
+			}
+
+			err = gm.checkOptedIn(ctx, r, tt.heat, tt.games, tt.target)
+			var notOptedIn *NotOptedInError
+			switch {
+			case tt.notOptedIn:
+				if !errors.As(err, &notOptedIn) || notOptedIn.UserID != tt.target {
+					t.Errorf("checking %s returned %v, want NotOptedInError", tt.target, err)
+				}
+			case err != nil:
+				t.Errorf("checking %s returned %v, want nil", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestPickTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		optedIn []string
+		tosses  [][2]string
+		heat    *room.Heat
+		games   []*game.Game
+		actor   string
+		want    []string
+		err     error
+	}{
+		{name: "roster", optedIn: []string{"a", "b", "c"}, actor: "a", want: []string{"b", "c"}},
+		{
+			name:    "recent players",
+			optedIn: []string{"a", "b", "c", "d"},
+			tosses:  [][2]string{{"bot", "b"}, {"b", "c"}},
+			actor:   "c",
+			want:    []string{"b"},
+		},
+		{name: "heat", optedIn: []string{"c"}, heat: &room.Heat{Round: 1, Number: 1, Players: []string{"a", "b"}}, actor: "a", want: []string{"b"}},
+		{
+			name:  "elimination",
+			games: []*game.Game{{PotatoID: 1, PotatoKind: "hot", HolderUserID: "a", Players: []string{"a", "b", "c"}}},
+			actor: "a",
+			want:  []string{"b", "c"},
+		},
+		{
+			name:  "team",
+			games: []*game.Game{{PotatoID: 1, PotatoKind: "hot", HolderUserID: "a", Teams: map[string]string{"a": "Red", "b": "Red", "c": "Blue", "d": "Blue"}}},
+			actor: "a",
+			want:  []string{"c", "d"},
+		},
+		{name: "alone", optedIn: []string{"a"}, actor: "a", err: ErrNoTossTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, memoryStorage)
+			ctx := context.Background()
+
+			optIn(t, gm, tt.optedIn...)
+			updateSettings(t, gm, func(settings *room.Settings) {
+				settings.ExplodeMultiplier = 0
+			})
+			for _, players := range tt.tosses {
+				toss(t, gm, players[0], players[1])
+			}
+
+			r, err := gm.rooms.GetRoom(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to get room: %v", err)
+			}
+
+			picked := make(map[string]bool)
+			for roll := 0; roll < 10; roll++ {
+				gm.random = &sequenceRandomizer{values: []int{roll}}
+				target, err := gm.pickTarget(ctx, gm.logger, r, tt.heat, tt.games, testChannelID, 0, tt.actor)
+				if tt.err != nil {
+					if !errors.Is(err, tt.err) {
+						t.Fatalf("picking a target returned %v, want %v", err, tt.err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("failed to pick target: %v", err)
+				}
+				picked[target] = true
+			}
+
+			if len(picked) != len(tt.want) {
+				t.Errorf("picked targets %v, want %v", picked, tt.want)
+			}
+			for _, userID := range tt.want {
+				if !picked[userID] {
+					t.Errorf("picked targets %v, want %v", picked, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
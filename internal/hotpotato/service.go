@@ -3,6 +3,10 @@ package hotpotato
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
 )
 
 type Service interface {
@@ -10,7 +14,28 @@ type Service interface {
 	Steal(ctx context.Context, req *StealRequest) (*StealResponse, error)
 	Cook(ctx context.Context, req *CookRequest) (*CookResponse, error)
 	GetHolder(ctx context.Context, req *GetHolderRequest) (*GetHolderResponse, error)
+	GetHistory(ctx context.Context, req *GetHistoryRequest) (*GetHistoryResponse, error)
 	GetLeaderboard(ctx context.Context, req *GetLeaderboardRequest) (*GetLeaderboardResponse, error)
+	GetProfile(ctx context.Context, req *GetProfileRequest) (*GetProfileResponse, error)
+	ListAchievements(ctx context.Context, req *ListAchievementsRequest) (*ListAchievementsResponse, error)
+	GetSettings(ctx context.Context, req *GetSettingsRequest) (*GetSettingsResponse, error)
+	UpdateSettings(ctx context.Context, req *UpdateSettingsRequest) (*UpdateSettingsResponse, error)
+	EndSeason(ctx context.Context, req *EndSeasonRequest) (*EndSeasonResponse, error)
+	StartTeamGame(ctx context.Context, req *StartTeamGameRequest) (*StartTeamGameResponse, error)
+	OptIn(ctx context.Context, req *OptInRequest) (*OptInResponse, error)
+	OptOut(ctx context.Context, req *OptOutRequest) (*OptOutResponse, error)
+	JoinLobby(ctx context.Context, req *JoinLobbyRequest) (*JoinLobbyResponse, error)
+	UseItem(ctx context.Context, req *UseItemRequest) (*UseItemResponse, error)
+	StartElimination(ctx context.Context, req *StartEliminationRequest) (*StartEliminationResponse, error)
+	GetTeamLeaderboard(ctx context.Context, req *GetTeamLeaderboardRequest) (*GetTeamLeaderboardResponse, error)
+	RegisterTournamentPlayers(ctx context.Context, req *RegisterTournamentPlayersRequest) (*RegisterTournamentPlayersResponse, error)
+	StartTournament(ctx context.Context, req *StartTournamentRequest) (*StartTournamentResponse, error)
+	OpenHeat(ctx context.Context, req *OpenHeatRequest) (*OpenHeatResponse, error)
+	GetTournament(ctx context.Context, req *GetTournamentRequest) (*GetTournamentResponse, error)
+	CancelTournament(ctx context.Context, req *CancelTournamentRequest) (*CancelTournamentResponse, error)
+	ListClosingHeats(ctx context.Context, req *ListClosingHeatsRequest) (*ListClosingHeatsResponse, error)
+	CloseHeat(ctx context.Context, req *CloseHeatRequest) (*CloseHeatResponse, error)
+	Detonations() <-chan *Detonation
 }
 
 type TossRequest struct {
@@ -19,6 +44,14 @@ type TossRequest struct {
 	ChannelID    string
 	ActorUserID  string
 	TargetUserID string
+
+	// Random has the potato tossed to a target picked at random in place of
+	// TargetUserID.
+	Random bool
+
+	// PotatoID selects the potato to play the turn with, or the first one held
+	// by the actor when 0.
+	PotatoID int
 }
 
 func (r *TossRequest) Validate() error {
@@ -31,18 +64,46 @@ func (r *TossRequest) Validate() error {
 		return errors.New("missing channel ID")
 	case r.ActorUserID == "":
 		return errors.New("missing actor user ID")
-	case r.TargetUserID == "":
+	case r.TargetUserID == "" && !r.Random:
 		return errors.New("missing target user ID")
+	case r.TargetUserID != "" && r.Random:
+		return errors.New("target user ID cannot be given for a random toss")
+	case r.PotatoID < 0:
+		return errors.New("potato ID cannot be negative")
 	default:
 		return nil
 	}
 }
 
 type TossResponse struct {
+	PotatoID     int
 	Turn         int
 	Potato       Potato
+	TargetUserID string
 	HolderUserID string
 	Exploded     bool
+
+	// Dodged reports whether the target dodged the toss, bouncing the potato
+	// back to the actor.
+	Dodged bool
+
+	// LosingTeam is the team that lost the game when the potato exploded in a
+	// team game.
+	LosingTeam string
+
+	// Achievements are the achievements unlocked on the turn.
+	Achievements []Unlock
+
+	// Tournament is the progress made in the tournament when the potato
+	// exploded in one of its heats.
+	Tournament *TournamentUpdate
+
+	// Elimination is the progress made in the elimination game when the potato
+	// exploded in it.
+	Elimination *EliminationUpdate
+
+	// Items are the items used up and earned on the turn, if any.
+	Items *ItemUpdate
 }
 
 type StealRequest struct {
@@ -51,6 +112,10 @@ type StealRequest struct {
 	ChannelID    string
 	ActorUserID  string
 	TargetUserID string
+
+	// PotatoID selects the potato to steal, or the first one held by the
+	// target when 0.
+	PotatoID int
 }
 
 func (r *StealRequest) Validate() error {
@@ -65,16 +130,37 @@ func (r *StealRequest) Validate() error {
 		return errors.New("missing actor user ID")
 	case r.TargetUserID == "":
 		return errors.New("missing target user ID")
+	case r.PotatoID < 0:
+		return errors.New("potato ID cannot be negative")
 	default:
 		return nil
 	}
 }
 
 type StealResponse struct {
+	PotatoID     int
 	Turn         int
 	Potato       Potato
 	HolderUserID string
 	Exploded     bool
+
+	// LosingTeam is the team that lost the game when the potato exploded in a
+	// team game.
+	LosingTeam string
+
+	// Achievements are the achievements unlocked on the turn.
+	Achievements []Unlock
+
+	// Tournament is the progress made in the tournament when the potato
+	// exploded in one of its heats.
+	Tournament *TournamentUpdate
+
+	// Elimination is the progress made in the elimination game when the potato
+	// exploded in it.
+	Elimination *EliminationUpdate
+
+	// Items are the items used up and earned on the turn, if any.
+	Items *ItemUpdate
 }
 
 type CookRequest struct {
@@ -82,6 +168,10 @@ type CookRequest struct {
 	RoomID      string
 	ChannelID   string
 	ActorUserID string
+
+	// PotatoID selects the potato to play the turn with, or the first one held
+	// by the actor when 0.
+	PotatoID int
 }
 
 func (r *CookRequest) Validate() error {
@@ -94,17 +184,38 @@ func (r *CookRequest) Validate() error {
 		return errors.New("missing channel ID")
 	case r.ActorUserID == "":
 		return errors.New("missing actor user ID")
+	case r.PotatoID < 0:
+		return errors.New("potato ID cannot be negative")
 	default:
 		return nil
 	}
 }
 
 type CookResponse struct {
+	PotatoID     int
 	Turn         int
 	Potato       Potato
 	HeatLevel    int
 	HolderUserID string
 	Exploded     bool
+
+	// LosingTeam is the team that lost the game when the potato exploded in a
+	// team game.
+	LosingTeam string
+
+	// Achievements are the achievements unlocked on the turn.
+	Achievements []Unlock
+
+	// Tournament is the progress made in the tournament when the potato
+	// exploded in one of its heats.
+	Tournament *TournamentUpdate
+
+	// Elimination is the progress made in the elimination game when the potato
+	// exploded in it.
+	Elimination *EliminationUpdate
+
+	// Items are the items used up and earned on the turn, if any.
+	Items *ItemUpdate
 }
 
 type GetHolderRequest struct {
@@ -127,14 +238,67 @@ func (r *GetHolderRequest) Validate() error {
 }
 
 type GetHolderResponse struct {
+	// Potatoes are the potatoes in play in the channel, ordered by ID.
+	Potatoes []*LivePotato
+}
+
+// LivePotato is a potato in play and the user holding it.
+type LivePotato struct {
+	ID           int
+	Potato       Potato
+	HolderUserID string
+}
+
+type GetHistoryRequest struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+
+	// PotatoID selects the potato to show the history of, or the last one
+	// played in the channel when 0.
+	PotatoID int
+}
+
+func (r *GetHistoryRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.ChannelID == "":
+		return errors.New("missing channel ID")
+	case r.PotatoID < 0:
+		return errors.New("potato ID cannot be negative")
+	default:
+		return nil
+	}
+}
+
+type GetHistoryResponse struct {
+	PotatoID     int
 	Potato       Potato
 	HolderUserID string
+	Finished     bool
+	Turns        []*game.Turn
 }
 
 type GetLeaderboardRequest struct {
 	Namespace string
 	RoomID    string
-	Top       int
+
+	// Top is the number of users shown on each page of the leaderboard, or the
+	// room's configured leaderboard size when 0.
+	Top int
+
+	// Page selects the page of the leaderboard to show, starting from 0.
+	Page int
+
+	// Season selects the season to show the leaderboard for, or the active
+	// season when 0.
+	Season int
+
+	// Metric is the metric to rank users by, or MetricDeaths when empty.
+	Metric Metric
 }
 
 func (r *GetLeaderboardRequest) Validate() error {
@@ -145,11 +309,524 @@ func (r *GetLeaderboardRequest) Validate() error {
 		return errors.New("missing room ID")
 	case r.Top < 0:
 		return errors.New("top cannot be negative")
+	case r.Page < 0:
+		return errors.New("page cannot be negative")
+	case r.Season < 0:
+		return errors.New("season cannot be negative")
+	case r.Metric != "" && !r.Metric.Valid():
+		return errors.New("unknown metric")
 	default:
 		return nil
 	}
 }
 
 type GetLeaderboardResponse struct {
+	Metric Metric
+	Season int
+	Ended  bool
+	Page   int
+	Pages  int
+
+	// Offset is the number of users ranked above the first user on the page.
+	Offset      int
 	Leaderboard Scoreboard
 }
+
+type GetProfileRequest struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (r *GetProfileRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.UserID == "":
+		return errors.New("missing user ID")
+	default:
+		return nil
+	}
+}
+
+type GetProfileResponse struct {
+	Season int
+	Deaths int
+	Wins   int
+
+	// Rank is the user's position on the deaths leaderboard of the active
+	// season, or 0 if they have not died this season.
+	Rank int
+
+	GamesPlayed           int
+	FavouriteTargetUserID string
+	FavouriteTargetTosses int
+	NemesisUserID         string
+	NemesisKills          int
+
+	// Items are the items held by the user that they have yet to arm.
+	Items []*HeldItem
+}
+
+// HeldItem is an item that a user holds in their inventory.
+type HeldItem struct {
+	Item  Item
+	Count int
+}
+
+type ListAchievementsRequest struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (r *ListAchievementsRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.UserID == "":
+		return errors.New("missing user ID")
+	default:
+		return nil
+	}
+}
+
+type ListAchievementsResponse struct {
+	Achievements []*AchievementProgress
+}
+
+// AchievementProgress is whether a user has unlocked an achievement, and when.
+type AchievementProgress struct {
+	Achievement Achievement
+	Unlocked    bool
+	UnlockedAt  time.Time
+}
+
+type GetSettingsRequest struct {
+	Namespace string
+	RoomID    string
+}
+
+func (r *GetSettingsRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	default:
+		return nil
+	}
+}
+
+type GetSettingsResponse struct {
+	Settings *room.Settings
+}
+
+type UpdateSettingsRequest struct {
+	Namespace string
+	RoomID    string
+	Settings  *room.Settings
+}
+
+func (r *UpdateSettingsRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.Settings == nil:
+		return errors.New("missing settings")
+	default:
+		return nil
+	}
+}
+
+type UpdateSettingsResponse struct {
+	Settings *room.Settings
+}
+
+type EndSeasonRequest struct {
+	Namespace string
+	RoomID    string
+}
+
+func (r *EndSeasonRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	default:
+		return nil
+	}
+}
+
+type EndSeasonResponse struct {
+	Season      int
+	NextSeason  int
+	Leaderboard Scoreboard
+}
+
+type StartTeamGameRequest struct {
+	Namespace   string
+	RoomID      string
+	ChannelID   string
+	ActorUserID string
+
+	// Teams maps the name of each team to its players. When empty, the players
+	// of the last game in the channel are split into teams at random.
+	Teams map[string][]string
+}
+
+func (r *StartTeamGameRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.ChannelID == "":
+		return errors.New("missing channel ID")
+	case r.ActorUserID == "":
+		return errors.New("missing actor user ID")
+	}
+
+	seen := make(map[string]bool)
+	for _, players := range r.Teams {
+		for _, userID := range players {
+			if seen[userID] {
+				return errors.New("user cannot be on more than one team")
+			}
+			seen[userID] = true
+		}
+	}
+
+	return nil
+}
+
+type StartTeamGameResponse struct {
+	PotatoID     int
+	Potato       Potato
+	HolderUserID string
+	Teams        map[string][]string
+}
+
+type OptInRequest struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (r *OptInRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.UserID == "":
+		return errors.New("missing user ID")
+	default:
+		return nil
+	}
+}
+
+type OptInResponse struct {
+	// Added reports whether the user had not already opted in.
+	Added bool
+}
+
+type OptOutRequest struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (r *OptOutRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.UserID == "":
+		return errors.New("missing user ID")
+	default:
+		return nil
+	}
+}
+
+type OptOutResponse struct {
+	// Removed reports whether the user had opted in.
+	Removed bool
+}
+
+type UseItemRequest struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	ItemID    string
+}
+
+func (r *UseItemRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.UserID == "":
+		return errors.New("missing user ID")
+	case r.ItemID == "":
+		return errors.New("missing item ID")
+	default:
+		return nil
+	}
+}
+
+type UseItemResponse struct {
+	Item Item
+
+	// Remaining is the number of the item that the user has left after arming
+	// one.
+	Remaining int
+}
+
+type JoinLobbyRequest struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	UserID    string
+}
+
+func (r *JoinLobbyRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.ChannelID == "":
+		return errors.New("missing channel ID")
+	case r.UserID == "":
+		return errors.New("missing user ID")
+	default:
+		return nil
+	}
+}
+
+type JoinLobbyResponse struct {
+	// Players are the players waiting in the lobby for the next elimination
+	// game in the channel.
+	Players []string
+}
+
+type StartEliminationRequest struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+}
+
+func (r *StartEliminationRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.ChannelID == "":
+		return errors.New("missing channel ID")
+	default:
+		return nil
+	}
+}
+
+type StartEliminationResponse struct {
+	Potato       Potato
+	HolderUserID string
+	Players      []string
+}
+
+type GetTeamLeaderboardRequest struct {
+	Namespace string
+	RoomID    string
+}
+
+func (r *GetTeamLeaderboardRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	default:
+		return nil
+	}
+}
+
+type GetTeamLeaderboardResponse struct {
+	Leaderboard []room.TeamLossCounter
+}
+
+type RegisterTournamentPlayersRequest struct {
+	Namespace string
+	RoomID    string
+
+	// ChannelID is the channel that updates to the bracket are posted in, if
+	// the tournament has yet to be set up.
+	ChannelID string
+	UserIDs   []string
+}
+
+func (r *RegisterTournamentPlayersRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.ChannelID == "":
+		return errors.New("missing channel ID")
+	case len(r.UserIDs) == 0:
+		return errors.New("missing user IDs")
+	default:
+		return nil
+	}
+}
+
+type RegisterTournamentPlayersResponse struct {
+	Tournament *room.Tournament
+
+	// Registered is the number of players that were newly registered.
+	Registered int
+}
+
+type StartTournamentRequest struct {
+	Namespace string
+	RoomID    string
+}
+
+func (r *StartTournamentRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	default:
+		return nil
+	}
+}
+
+type StartTournamentResponse struct {
+	Update *TournamentUpdate
+}
+
+type OpenHeatRequest struct {
+	Namespace  string
+	RoomID     string
+	Tournament int
+	Round      int
+	Heat       int
+
+	// ChannelID is the channel opened for the heat to be played in.
+	ChannelID string
+}
+
+func (r *OpenHeatRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.Tournament < 1:
+		return errors.New("invalid tournament number")
+	case r.Round < 1:
+		return errors.New("invalid round")
+	case r.Heat < 1:
+		return errors.New("invalid heat")
+	case r.ChannelID == "":
+		return errors.New("missing channel ID")
+	default:
+		return nil
+	}
+}
+
+type OpenHeatResponse struct {
+	Heat         *room.Heat
+	PotatoID     int
+	Potato       Potato
+	HolderUserID string
+}
+
+type GetTournamentRequest struct {
+	Namespace string
+	RoomID    string
+}
+
+func (r *GetTournamentRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	default:
+		return nil
+	}
+}
+
+type GetTournamentResponse struct {
+	Tournament *room.Tournament
+}
+
+type CancelTournamentRequest struct {
+	Namespace string
+	RoomID    string
+}
+
+func (r *CancelTournamentRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	default:
+		return nil
+	}
+}
+
+type CancelTournamentResponse struct {
+	Tournament *room.Tournament
+}
+
+type ListClosingHeatsRequest struct{}
+
+func (r *ListClosingHeatsRequest) Validate() error {
+	return nil
+}
+
+type ListClosingHeatsResponse struct {
+	Heats []*room.ClosingHeat
+}
+
+type CloseHeatRequest struct {
+	Namespace  string
+	RoomID     string
+	Tournament int
+	Round      int
+	Heat       int
+}
+
+func (r *CloseHeatRequest) Validate() error {
+	switch {
+	case r.Namespace == "":
+		return errors.New("missing namespace")
+	case r.RoomID == "":
+		return errors.New("missing room ID")
+	case r.Tournament < 1:
+		return errors.New("invalid tournament number")
+	case r.Round < 1:
+		return errors.New("invalid round")
+	case r.Heat < 1:
+		return errors.New("invalid heat")
+	default:
+		return nil
+	}
+}
+
+type CloseHeatResponse struct{}
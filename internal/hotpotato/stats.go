@@ -0,0 +1,98 @@
+package hotpotato
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// recordStats records the stats of everyone who played in the finished game.
+func (gm *GameMaster) recordStats(ctx context.Context, g *game.Game) error {
+	turns, err := gm.games.ListTurns(ctx, g.Namespace, g.ChannelID, g.PotatoID, g.Round)
+	if err != nil {
+		return fmt.Errorf("error listing turns: %w", err)
+	}
+
+	err = gm.rooms.RecordStats(ctx, g.Namespace, g.RoomID, GameStats(turns, g.HolderUserID))
+	if err != nil {
+		return fmt.Errorf("error recording stats: %w", err)
+	}
+
+	return nil
+}
+
+// GameStats works out the stats of everyone who played in a game from its
+// turns, given the user the potato exploded on. A kill is credited to whoever
+// last tossed the potato to that user.
+func GameStats(turns []*game.Turn, deadUserID string) []room.UserStats {
+	var order []string
+	stats := make(map[string]*room.UserStats)
+	player := func(userID string) *room.UserStats {
+		s, ok := stats[userID]
+		if !ok {
+			s = &room.UserStats{UserID: userID}
+			stats[userID] = s
+			order = append(order, userID)
+		}
+		return s
+	}
+
+	var holderUserID, killerUserID string
+	var streak int
+	for _, turn := range turns {
+		if turn.Action == game.ActionExplode {
+			continue
+		}
+
+		actor := player(turn.ActorUserID)
+		player(turn.TargetUserID)
+
+		switch turn.Action {
+		case game.ActionToss:
+			actor.Tosses++
+		case game.ActionSteal:
+			actor.Steals++
+		case game.ActionCook:
+			actor.Cooks++
+		}
+
+		if next := turnHolder(turn); next != holderUserID {
+			holderUserID = next
+			streak = 0
+			killerUserID = ""
+			if turn.Action == game.ActionToss && turn.ActorUserID != next {
+				killerUserID = turn.ActorUserID
+			}
+		}
+
+		streak++
+		if holder := player(holderUserID); streak > holder.LongestHoldStreak {
+			holder.LongestHoldStreak = streak
+		}
+	}
+
+	if killerUserID != "" && holderUserID == deadUserID {
+		player(killerUserID).Kills++
+	}
+
+	played := make([]room.UserStats, len(order))
+	for i, userID := range order {
+		s := stats[userID]
+		s.GamesPlayed = 1
+		if userID != deadUserID {
+			s.GamesSurvived = 1
+		}
+		played[i] = *s
+	}
+
+	return played
+}
+
+func turnHolder(turn *game.Turn) string {
+	if turn.Action == game.ActionToss {
+		return turn.TargetUserID
+	}
+	return turn.ActorUserID
+}
@@ -0,0 +1,90 @@
+package hotpotato
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+func TestGameStats(t *testing.T) {
+	toss := func(actor, target string) *game.Turn {
+		return &game.Turn{Action: game.ActionToss, ActorUserID: actor, TargetUserID: target}
+	}
+	steal := func(actor, target string) *game.Turn {
+		return &game.Turn{Action: game.ActionSteal, ActorUserID: actor, TargetUserID: target}
+	}
+	cook := func(actor string) *game.Turn {
+		return &game.Turn{Action: game.ActionCook, ActorUserID: actor, TargetUserID: actor}
+	}
+	explode := func(holder string) *game.Turn {
+		return &game.Turn{Action: game.ActionExplode, ActorUserID: holder, TargetUserID: holder, Exploded: true}
+	}
+
+	tests := []struct {
+		name  string
+		turns []*game.Turn
+		dead  string
+		want  []room.UserStats
+	}{
+		{
+			name: "no turns",
+			want: []room.UserStats{},
+		},
+		{
+			name:  "kill goes to last tosser",
+			turns: []*game.Turn{toss("a", "b"), toss("b", "c"), explode("c")},
+			dead:  "c",
+			want: []room.UserStats{
+				{UserID: "a", GamesPlayed: 1, GamesSurvived: 1, Tosses: 1},
+				{UserID: "b", GamesPlayed: 1, GamesSurvived: 1, Tosses: 1, Kills: 1, LongestHoldStreak: 1},
+				{UserID: "c", GamesPlayed: 1, LongestHoldStreak: 1},
+			},
+		},
+		{
+			name:  "cooking extends streak and keeps kill",
+			turns: []*game.Turn{toss("a", "b"), cook("b"), cook("b"), explode("b")},
+			dead:  "b",
+			want: []room.UserStats{
+				{UserID: "a", GamesPlayed: 1, GamesSurvived: 1, Tosses: 1, Kills: 1},
+				{UserID: "b", GamesPlayed: 1, Cooks: 2, LongestHoldStreak: 3},
+			},
+		},
+		{
+			name:  "stealing clears kill",
+			turns: []*game.Turn{toss("a", "b"), steal("c", "b"), explode("c")},
+			dead:  "c",
+			want: []room.UserStats{
+				{UserID: "a", GamesPlayed: 1, GamesSurvived: 1, Tosses: 1},
+				{UserID: "b", GamesPlayed: 1, GamesSurvived: 1, LongestHoldStreak: 1},
+				{UserID: "c", GamesPlayed: 1, Steals: 1, LongestHoldStreak: 1},
+			},
+		},
+		{
+			name:  "longest streak kept",
+			turns: []*game.Turn{toss("a", "b"), cook("b"), toss("b", "a"), toss("a", "b"), explode("b")},
+			dead:  "b",
+			want: []room.UserStats{
+				{UserID: "a", GamesPlayed: 1, GamesSurvived: 1, Tosses: 2, Kills: 1, LongestHoldStreak: 1},
+				{UserID: "b", GamesPlayed: 1, Tosses: 1, Cooks: 1, LongestHoldStreak: 2},
+			},
+		},
+		{
+			name:  "no kill when holder survives",
+			turns: []*game.Turn{toss("a", "b")},
+			want: []room.UserStats{
+				{UserID: "a", GamesPlayed: 1, GamesSurvived: 1, Tosses: 1},
+				{UserID: "b", GamesPlayed: 1, GamesSurvived: 1, LongestHoldStreak: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GameStats(tt.turns, tt.dead); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GameStats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
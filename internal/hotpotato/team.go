@@ -0,0 +1,91 @@
+package hotpotato
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+)
+
+// DefaultTeams are the teams that players are split into when a team game is
+// started without teams of its own.
+var DefaultTeams = []string{"Red", "Blue"}
+
+// splitTeams shuffles the players and deals them out to the default teams in
+// turn.
+func (gm *GameMaster) splitTeams(players []string) map[string][]string {
+	shuffled := append([]string(nil), players...)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := gm.random.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+
+	teams := make(map[string][]string, len(DefaultTeams))
+	for i, userID := range shuffled {
+		team := DefaultTeams[i%len(DefaultTeams)]
+		teams[team] = append(teams[team], userID)
+	}
+
+	return teams
+}
+
+// participants lists the actor followed by everyone else who took part in the
+// given turns, in the order they first appeared.
+func participants(actorUserID string, turns []*game.Turn) []string {
+	seen := map[string]bool{actorUserID: true}
+	players := []string{actorUserID}
+	for _, turn := range turns {
+		for _, userID := range []string{turn.ActorUserID, turn.TargetUserID} {
+			if userID == "" || seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			players = append(players, userID)
+		}
+	}
+
+	return players
+}
+
+// checkTeamToss enforces that potatoes in a team game are only tossed to
+// players on the other teams.
+func checkTeamToss(g *game.Game, actorUserID, targetUserID string) error {
+	if !g.TeamGame() {
+		return nil
+	}
+
+	team, ok := g.Teams[targetUserID]
+	if !ok {
+		return &NotOnTeamError{targetUserID}
+	}
+
+	if team == g.Teams[actorUserID] {
+		return ErrTeammateToss
+	}
+
+	return nil
+}
+
+// losingTeam returns the team of the player that the potato exploded on, if
+// the game was a team game that has ended.
+func losingTeam(g *game.Game) string {
+	if !g.Finished {
+		return ""
+	}
+	return g.Teams[g.HolderUserID]
+}
+
+// creditTeamLoss credits the loss of a finished team game to the team of the
+// player that the potato exploded on.
+func (gm *GameMaster) creditTeamLoss(ctx context.Context, g *game.Game) error {
+	team := losingTeam(g)
+	if team == "" {
+		return nil
+	}
+
+	if err := gm.rooms.IncrementTeamLosses(ctx, g.Namespace, g.RoomID, team); err != nil {
+		return fmt.Errorf("error incrementing team losses: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package hotpotato
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+func TestGameMasterStartTeamGame(t *testing.T) {
+	tests := []struct {
+		name      string
+		actor     string
+		teams     map[string][]string
+		err       error
+		notOnTeam string
+	}{
+		{name: "teams", actor: "a", teams: map[string][]string{"Red": {"a", "b"}, "Blue": {"c", "d"}}},
+		{name: "single team", actor: "a", teams: map[string][]string{"Red": {"a", "b"}}, err: ErrNotEnoughPlayers},
+		{name: "empty team", actor: "a", teams: map[string][]string{"Red": {"a", "b"}, "Blue": {}}, err: ErrNotEnoughPlayers},
+		{name: "actor not on a team", actor: "e", teams: map[string][]string{"Red": {"a", "b"}, "Blue": {"c", "d"}}, notOnTeam: "e"},
+		{name: "actor alone", actor: "a", err: ErrNotEnoughPlayers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, memoryStorage)
+
+			rsp, err := gm.StartTeamGame(context.Background(), &StartTeamGameRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: tt.actor, Teams: tt.teams})
+			var notOnTeam *NotOnTeamError
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Errorf("starting a team game returned %v, want %v", err, tt.err)
+				}
+				return
+			case tt.notOnTeam != "":
+				if !errors.As(err, &notOnTeam) || notOnTeam.UserID != tt.notOnTeam {
+					t.Errorf("starting a team game returned %v, want NotOnTeamError for %s", err, tt.notOnTeam)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to start team game: %v", err)
+			}
+
+			if rsp.HolderUserID != tt.actor || len(rsp.Teams) != len(tt.teams) {
+				t.Errorf("unexpected team game: %+v", rsp)
+			}
+		})
+	}
+}
+
+func TestGameMasterStartTeamGameFromLastGame(t *testing.T) {
+	gm := newTestGameMaster(t, memoryStorage)
+	ctx := context.Background()
+
+	players := []string{"a", "b", "c", "d"}
+	optIn(t, gm, players...)
+	updateSettings(t, gm, func(settings *room.Settings) {
+		settings.ExplodeMultiplier = 0
+	})
+
+	// Everyone gets the potato before it is allowed to explode.
+	for i := 0; i < len(players)-1; i++ {
+		toss(t, gm, players[i], players[i+1])
+	}
+	updateSettings(t, gm, func(settings *room.Settings) {
+		settings.ExplodeMultiplier = room.MaxExplodeMultiplier
+	})
+	for i := len(players) - 1; !toss(t, gm, players[i%len(players)], players[(i+1)%len(players)]).Exploded; i++ {
+		if i > 100 {
+			t.Fatal("potato never exploded")
+		}
+	}
+
+	rsp, err := gm.StartTeamGame(ctx, &StartTeamGameRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "a"})
+	if err != nil {
+		t.Fatalf("failed to start team game: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, team := range DefaultTeams {
+		if len(rsp.Teams[team]) != 2 {
+			t.Errorf("team %s has players %v, want 2", team, rsp.Teams[team])
+		}
+		for _, userID := range rsp.Teams[team] {
+			seen[userID] = true
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("teams %v do not split the players of the last game", rsp.Teams)
+	}
+}
+
+func TestGameMasterTeamToss(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		err       error
+		notOnTeam string
+	}{
+		{name: "opponent", target: "c"},
+		{name: "teammate", target: "b", err: ErrTeammateToss},
+		{name: "not on a team", target: "e", notOnTeam: "e"},
+	}
+
+	for _, storage := range testStorages {
+		for _, tt := range tests {
+			t.Run(storage.name+"/"+tt.name, func(t *testing.T) {
+				gm := newTestGameMaster(t, storage)
+				ctx := context.Background()
+
+				optIn(t, gm, "a", "b", "c", "d", "e")
+				updateSettings(t, gm, func(settings *room.Settings) {
+					settings.ExplodeMultiplier = 0
+				})
+
+				started, err := gm.StartTeamGame(ctx, &StartTeamGameRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "a", Teams: map[string][]string{"Red": {"a", "b"}, "Blue": {"c", "d"}}})
+				if err != nil {
+					t.Fatalf("failed to start team game: %v", err)
+				}
+
+				_, err = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "a", TargetUserID: tt.target, PotatoID: started.PotatoID})
+				var notOnTeam *NotOnTeamError
+				switch {
+				case tt.err != nil:
+					if !errors.Is(err, tt.err) {
+						t.Errorf("tossing to %s returned %v, want %v", tt.target, err, tt.err)
+					}
+				case tt.notOnTeam != "":
+					if !errors.As(err, &notOnTeam) || notOnTeam.UserID != tt.notOnTeam {
+						t.Errorf("tossing to %s returned %v, want NotOnTeamError", tt.target, err)
+					}
+				case err != nil:
+					t.Errorf("failed to toss to %s: %v", tt.target, err)
+				}
+			})
+		}
+	}
+}
+
+func TestGameMasterTeamLoss(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			gm := newTestGameMaster(t, storage)
+			ctx := context.Background()
+
+			optIn(t, gm, "a", "b")
+			updateSettings(t, gm, func(settings *room.Settings) {
+				settings.ExplodeMultiplier = room.MaxExplodeMultiplier
+			})
+
+			teams := map[string]string{"a": "Red", "b": "Blue"}
+			started, err := gm.StartTeamGame(ctx, &StartTeamGameRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: "a", Teams: map[string][]string{"Red": {"a"}, "Blue": {"b"}}})
+			if err != nil {
+				t.Fatalf("failed to start team game: %v", err)
+			}
+
+			actor, target := "a", "b"
+			var rsp *TossResponse
+			for i := 0; i < 100; i++ {
+				rsp, err = gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, ActorUserID: actor, TargetUserID: target, PotatoID: started.PotatoID})
+				if err != nil {
+					t.Fatalf("failed to toss from %s to %s: %v", actor, target, err)
+				}
+				if rsp.Exploded {
+					break
+				}
+				actor, target = target, actor
+			}
+			if !rsp.Exploded {
+				t.Fatal("potato never exploded")
+			}
+
+			losses, err := gm.rooms.ListTeamLosses(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to list team losses: %v", err)
+			}
+			if len(losses) != 1 || losses[0].Team != teams[rsp.HolderUserID] || losses[0].Count != 1 {
+				t.Errorf("got team losses %+v, want one loss for %s", losses, teams[rsp.HolderUserID])
+			}
+		})
+	}
+}
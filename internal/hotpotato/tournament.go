@@ -0,0 +1,156 @@
+package hotpotato
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/jace-ys/hot-potato-discord/internal/game"
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// HeatSize is the number of players drawn into each heat of a tournament.
+// Players left over when the survivors of a round can't be split evenly are
+// given a bye to the next round.
+const HeatSize = 2
+
+// HeatChannelRetention is how long the channel of a finished heat is kept
+// around for its players to read the result before it is closed.
+const HeatChannelRetention = time.Minute
+
+// TournamentUpdate is the progress of a tournament after a change to its
+// bracket.
+type TournamentUpdate struct {
+	Tournament *room.Tournament
+
+	// KnockedOutUserID is the player knocked out of the tournament by the heat
+	// that ended, if any.
+	KnockedOutUserID string
+
+	// Heat is the heat that ended, if any, whose channel is no longer needed.
+	Heat *room.Heat
+
+	// Heats are the heats drawn for the next round, which need channels to be
+	// opened for them to be played in.
+	Heats []*room.Heat
+}
+
+// drawHeats shuffles the players and draws them into heats, leaving any
+// players that don't fill a heat out to be given a bye.
+func (gm *GameMaster) drawHeats(players []string) []*room.Heat {
+	shuffled := append([]string(nil), players...)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := gm.random.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+
+	var heats []*room.Heat
+	for i := 0; i+HeatSize <= len(shuffled); i += HeatSize {
+		heats = append(heats, &room.Heat{
+			Number:  len(heats) + 1,
+			Players: shuffled[i : i+HeatSize],
+		})
+	}
+
+	return heats
+}
+
+// liveHeat finds the tournament heat being played in the given channel, if
+// there is one.
+func (gm *GameMaster) liveHeat(ctx context.Context, namespace, roomID, channelID string) (*room.Tournament, *room.Heat, error) {
+	t, err := gm.rooms.GetTournament(ctx, namespace, roomID)
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentNotFound) || errors.Is(err, room.ErrRoomNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("error getting tournament: %w", err)
+	}
+
+	return t, t.LiveHeat(channelID), nil
+}
+
+// checkHeatPlayers enforces that only the players drawn into a heat take part
+// in the game played in its channel.
+func checkHeatPlayers(heat *room.Heat, userIDs ...string) error {
+	if heat == nil {
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		if !heat.Has(userID) {
+			return &NotInHeatError{userID}
+		}
+	}
+
+	return nil
+}
+
+// advanceTournament knocks the player that the potato exploded on out of the
+// tournament heat played in the game's channel, and moves the tournament on to
+// its next round or crowns its winner once every heat of the round has ended.
+func (gm *GameMaster) advanceTournament(ctx context.Context, logger log.Logger, g *game.Game) (*TournamentUpdate, error) {
+	t, heat, err := gm.liveHeat(ctx, g.Namespace, g.RoomID, g.ChannelID)
+	if err != nil || heat == nil || !heat.Has(g.HolderUserID) {
+		return nil, err
+	}
+
+	var closesAt time.Time
+	if heat.ChannelID != "" && heat.ChannelID != t.ChannelID {
+		closesAt = time.Now().Add(HeatChannelRetention)
+	}
+
+	t, err = gm.rooms.FinishHeat(ctx, g.Namespace, g.RoomID, t.Number, heat.Round, heat.Number, g.HolderUserID, closesAt)
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentConflict) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finishing heat: %w", err)
+	}
+	level.Info(logger).Log("event", "tournament.heat.finished", "tournament", t.Number, "round", heat.Round, "heat", heat.Number)
+
+	finished := *heat
+	finished.LoserUserID = g.HolderUserID
+	finished.ClosesAt = closesAt
+
+	update := &TournamentUpdate{
+		Tournament:       t,
+		KnockedOutUserID: g.HolderUserID,
+		Heat:             &finished,
+	}
+
+	if !t.RoundFinished() {
+		return update, nil
+	}
+
+	survivors := t.Survivors()
+	if len(survivors) == 1 {
+		ended, err := gm.rooms.EndTournament(ctx, g.Namespace, g.RoomID, t.Number, survivors[0])
+		if err != nil {
+			if errors.Is(err, room.ErrTournamentConflict) {
+				return update, nil
+			}
+			return nil, fmt.Errorf("error ending tournament: %w", err)
+		}
+		level.Info(logger).Log("event", "tournament.ended", "tournament", t.Number)
+
+		update.Tournament = ended
+		return update, nil
+	}
+
+	next, err := gm.rooms.StartRound(ctx, g.Namespace, g.RoomID, t.Number, t.Round+1, gm.drawHeats(survivors))
+	if err != nil {
+		if errors.Is(err, room.ErrTournamentConflict) {
+			return update, nil
+		}
+		return nil, fmt.Errorf("error starting round: %w", err)
+	}
+	level.Info(logger).Log("event", "tournament.round.started", "tournament", next.Number, "round", next.Round)
+
+	update.Tournament = next
+	update.Heats = next.RoundHeats(next.Round)
+	return update, nil
+}
@@ -0,0 +1,200 @@
+package hotpotato
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jace-ys/hot-potato-discord/internal/room"
+)
+
+// startTournament registers the players in a tournament set up in the test
+// channel and starts it.
+func startTournament(t *testing.T, gm *GameMaster, userIDs ...string) *TournamentUpdate {
+	ctx := context.Background()
+
+	optIn(t, gm, userIDs...)
+	updateSettings(t, gm, func(settings *room.Settings) {
+		settings.ExplodeMultiplier = room.MaxExplodeMultiplier
+	})
+
+	_, err := gm.RegisterTournamentPlayers(ctx, &RegisterTournamentPlayersRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: testChannelID, UserIDs: userIDs})
+	if err != nil {
+		t.Fatalf("failed to register players: %v", err)
+	}
+
+	rsp, err := gm.StartTournament(ctx, &StartTournamentRequest{Namespace: testNamespace, RoomID: testRoomID})
+	if err != nil {
+		t.Fatalf("failed to start tournament: %v", err)
+	}
+	return rsp.Update
+}
+
+// playHeat opens the heat in the given channel and has its players toss the
+// potato back and forth until it explodes.
+func playHeat(t *testing.T, gm *GameMaster, tournament int, heat *room.Heat, channelID string) *TossResponse {
+	ctx := context.Background()
+
+	opened, err := gm.OpenHeat(ctx, &OpenHeatRequest{Namespace: testNamespace, RoomID: testRoomID, Tournament: tournament, Round: heat.Round, Heat: heat.Number, ChannelID: channelID})
+	if err != nil {
+		t.Fatalf("failed to open heat: %v", err)
+	}
+
+	actor := opened.HolderUserID
+	for i := 0; i < 100; i++ {
+		target := heat.Players[0]
+		if target == actor {
+			target = heat.Players[1]
+		}
+
+		rsp, err := gm.Toss(ctx, &TossRequest{Namespace: testNamespace, RoomID: testRoomID, ChannelID: channelID, ActorUserID: actor, TargetUserID: target, PotatoID: opened.PotatoID})
+		if err != nil {
+			t.Fatalf("failed to toss from %s to %s: %v", actor, target, err)
+		}
+		if rsp.Exploded {
+			return rsp
+		}
+		actor = rsp.HolderUserID
+	}
+
+	t.Fatal("potato never exploded")
+	return nil
+}
+
+func TestGameMasterHeatClose(t *testing.T) {
+	tests := []struct {
+		name      string
+		channelID string
+		closes    bool
+	}{
+		{name: "own channel", channelID: "heat", closes: true},
+		{name: "host channel", channelID: testChannelID},
+	}
+
+	for _, storage := range testStorages {
+		for _, tt := range tests {
+			t.Run(storage.name+"/"+tt.name, func(t *testing.T) {
+				gm := newTestGameMaster(t, storage)
+				ctx := context.Background()
+
+				update := startTournament(t, gm, "a", "b")
+				rsp := playHeat(t, gm, update.Tournament.Number, update.Heats[0], tt.channelID)
+				if rsp.Tournament == nil || rsp.Tournament.Heat == nil {
+					t.Fatalf("exploding in a heat made no progress: %+v", rsp.Tournament)
+				}
+
+				closesAt := rsp.Tournament.Heat.ClosesAt
+				if closes := !closesAt.IsZero(); closes != tt.closes {
+					t.Fatalf("heat closes at %v, want closing %t", closesAt, tt.closes)
+				}
+				if tt.closes && time.Until(closesAt) > HeatChannelRetention {
+					t.Errorf("heat closes at %v, after the retention period", closesAt)
+				}
+
+				closing, err := gm.ListClosingHeats(ctx, &ListClosingHeatsRequest{})
+				if err != nil {
+					t.Fatalf("failed to list closing heats: %v", err)
+				}
+				if !tt.closes {
+					if len(closing.Heats) != 0 {
+						t.Errorf("unexpected closing heats: %+v", closing.Heats)
+					}
+					return
+				}
+				if len(closing.Heats) != 1 {
+					t.Fatalf("%d closing heats, want 1", len(closing.Heats))
+				}
+				if h := closing.Heats[0]; h.Namespace != testNamespace || h.RoomID != testRoomID || h.Heat.ChannelID != tt.channelID || !h.Heat.ClosesAt.Equal(closesAt) {
+					t.Errorf("unexpected closing heat: %+v %+v", h, h.Heat)
+				}
+
+				_, err = gm.CloseHeat(ctx, &CloseHeatRequest{Namespace: testNamespace, RoomID: testRoomID, Tournament: update.Tournament.Number, Round: 1, Heat: 1})
+				if err != nil {
+					t.Fatalf("failed to close heat: %v", err)
+				}
+
+				closing, err = gm.ListClosingHeats(ctx, &ListClosingHeatsRequest{})
+				if err != nil {
+					t.Fatalf("failed to list closing heats: %v", err)
+				}
+				if len(closing.Heats) != 0 {
+					t.Errorf("closed heat still listed: %+v", closing.Heats)
+				}
+			})
+		}
+	}
+}
+
+func TestGameMasterTournamentBracket(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []string
+		rounds  int
+	}{
+		{name: "final", players: []string{"a", "b"}, rounds: 1},
+		{name: "bye", players: []string{"a", "b", "c"}, rounds: 2},
+		{name: "semi-finals", players: []string{"a", "b", "c", "d"}, rounds: 2},
+		{name: "byes", players: []string{"a", "b", "c", "d", "e"}, rounds: 3},
+	}
+
+	for _, storage := range testStorages {
+		for _, tt := range tests {
+			t.Run(storage.name+"/"+tt.name, func(t *testing.T) {
+				gm := newTestGameMaster(t, storage)
+
+				update := startTournament(t, gm, tt.players...)
+				number := update.Tournament.Number
+				knockedOut := make(map[string]bool)
+
+				var rounds int
+				for heats := update.Heats; len(heats) > 0; {
+					rounds++
+
+					drawn := make(map[string]bool)
+					for _, heat := range heats {
+						if heat.Round != rounds {
+							t.Fatalf("heat drawn for round %d in round %d", heat.Round, rounds)
+						}
+						for _, userID := range heat.Players {
+							if knockedOut[userID] || drawn[userID] {
+								t.Fatalf("%s drawn into round %d more than once or after being knocked out", userID, rounds)
+							}
+							drawn[userID] = true
+						}
+					}
+					if byes := len(tt.players) - len(knockedOut) - len(drawn); byes != (len(tt.players)-len(knockedOut))%HeatSize {
+						t.Errorf("%d byes given in round %d", byes, rounds)
+					}
+
+					var next []*room.Heat
+					for i, heat := range heats {
+						rsp := playHeat(t, gm, number, heat, fmt.Sprintf("heat-%d-%d", heat.Round, heat.Number))
+						if rsp.Tournament == nil {
+							t.Fatal("exploding in a heat made no progress")
+						}
+						knockedOut[rsp.Tournament.KnockedOutUserID] = true
+
+						if i < len(heats)-1 && len(rsp.Tournament.Heats) != 0 {
+							t.Fatalf("next round drawn before every heat of round %d ended", rounds)
+						}
+						update, next = rsp.Tournament, rsp.Tournament.Heats
+					}
+					heats = next
+				}
+
+				if rounds != tt.rounds {
+					t.Errorf("tournament played over %d rounds, want %d", rounds, tt.rounds)
+				}
+				if len(knockedOut) != len(tt.players)-1 {
+					t.Errorf("%d players knocked out, want %d", len(knockedOut), len(tt.players)-1)
+				}
+
+				winner := update.Tournament.WinnerUserID
+				if winner == "" || knockedOut[winner] || update.Tournament.EndedAt.IsZero() {
+					t.Errorf("tournament ended with winner %q at %v", winner, update.Tournament.EndedAt)
+				}
+			})
+		}
+	}
+}
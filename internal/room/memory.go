@@ -0,0 +1,748 @@
+package room
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryRoom struct {
+	namespace string
+	id        string
+	settings  *Settings
+	seasons   []*Season
+	deaths    map[string]int
+	stats     map[int]map[string]*UserStats
+
+	achievements map[string][]*Achievement
+	teamLosses   map[string]int
+	wins         map[string]int
+	participants map[string]bool
+	items        map[string]map[string]*Item
+	tournaments  []*Tournament
+}
+
+// MemoryRepository is a RoomRepository that keeps rooms in memory, for running
+// without a database.
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	rooms map[string]*memoryRoom
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		rooms: make(map[string]*memoryRoom),
+	}
+}
+
+func (r *MemoryRepository) GetRoom(ctx context.Context, namespace, roomID string) (*Room, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	return room.toDomain(), nil
+}
+
+func (r *MemoryRepository) CreateRoom(ctx context.Context, namespace, roomID string) (*Room, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memoryKey(namespace, roomID)
+	if _, ok := r.rooms[key]; ok {
+		return nil, ErrRoomAlreadyExists
+	}
+
+	room := &memoryRoom{
+		namespace: namespace,
+		id:        roomID,
+		settings:  DefaultSettings(),
+		seasons:   []*Season{{Number: 1, StartedAt: time.Now()}},
+		deaths:    make(map[string]int),
+		stats:     make(map[int]map[string]*UserStats),
+
+		achievements: make(map[string][]*Achievement),
+		teamLosses:   make(map[string]int),
+		wins:         make(map[string]int),
+		participants: make(map[string]bool),
+		items:        make(map[string]map[string]*Item),
+	}
+	r.rooms[key] = room
+
+	return room.toDomain(), nil
+}
+
+func (r *MemoryRepository) IncrementDeaths(ctx context.Context, namespace, roomID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return ErrRoomNotFound
+	}
+	room.deaths[userID]++
+
+	return nil
+}
+
+func (r *MemoryRepository) UpdateSettings(ctx context.Context, namespace, roomID string, settings *Settings) (*Room, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	room.settings = settings.Copy()
+
+	return room.toDomain(), nil
+}
+
+func (r *MemoryRepository) GetSeason(ctx context.Context, namespace, roomID string, number int) (*Season, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok || number < 1 || number > len(room.seasons) {
+		return nil, ErrSeasonNotFound
+	}
+
+	return room.season(number), nil
+}
+
+func (r *MemoryRepository) EndSeason(ctx context.Context, namespace, roomID string, number int) (*Season, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok || number < 1 || number > len(room.seasons) {
+		return nil, ErrSeasonNotFound
+	}
+
+	season := room.seasons[number-1]
+	if season.Ended() {
+		return nil, ErrSeasonAlreadyEnded
+	}
+
+	season.EndedAt = time.Now()
+	season.Standings = room.deathCount()
+	room.deaths = make(map[string]int)
+	room.seasons = append(room.seasons, &Season{Number: number + 1, StartedAt: season.EndedAt})
+
+	return room.season(number), nil
+}
+
+func (r *MemoryRepository) RecordStats(ctx context.Context, namespace, roomID string, stats []UserStats) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return ErrRoomNotFound
+	}
+
+	number := len(room.seasons)
+	if room.stats[number] == nil {
+		room.stats[number] = make(map[string]*UserStats)
+	}
+
+	for _, s := range stats {
+		existing, ok := room.stats[number][s.UserID]
+		if !ok {
+			existing = &UserStats{UserID: s.UserID}
+			room.stats[number][s.UserID] = existing
+		}
+		existing.Add(s)
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) UnlockAchievement(ctx context.Context, namespace, roomID, userID, achievementID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return false, ErrRoomNotFound
+	}
+
+	for _, achievement := range room.achievements[userID] {
+		if achievement.ID == achievementID {
+			return false, nil
+		}
+	}
+
+	room.achievements[userID] = append(room.achievements[userID], &Achievement{
+		ID:         achievementID,
+		UnlockedAt: time.Now(),
+	})
+
+	return true, nil
+}
+
+func (r *MemoryRepository) ListAchievements(ctx context.Context, namespace, roomID, userID string) ([]*Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	achievements := make([]*Achievement, len(room.achievements[userID]))
+	for i, achievement := range room.achievements[userID] {
+		a := *achievement
+		achievements[i] = &a
+	}
+
+	return achievements, nil
+}
+
+func (r *MemoryRepository) IncrementTeamLosses(ctx context.Context, namespace, roomID, team string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return ErrRoomNotFound
+	}
+	room.teamLosses[team]++
+
+	return nil
+}
+
+func (r *MemoryRepository) ListTeamLosses(ctx context.Context, namespace, roomID string) ([]TeamLossCounter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	counters := make([]TeamLossCounter, 0, len(room.teamLosses))
+	for team, count := range room.teamLosses {
+		counters = append(counters, TeamLossCounter{Team: team, Count: count})
+	}
+
+	return counters, nil
+}
+
+func (r *MemoryRepository) IncrementWins(ctx context.Context, namespace, roomID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return ErrRoomNotFound
+	}
+	room.wins[userID]++
+
+	return nil
+}
+
+func (r *MemoryRepository) ListWins(ctx context.Context, namespace, roomID string) ([]WinCounter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	counters := make([]WinCounter, 0, len(room.wins))
+	for userID, count := range room.wins {
+		counters = append(counters, WinCounter{UserID: userID, Count: count})
+	}
+
+	return counters, nil
+}
+
+func (r *MemoryRepository) OptIn(ctx context.Context, namespace, roomID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return false, ErrRoomNotFound
+	}
+
+	if room.participants[userID] {
+		return false, nil
+	}
+	room.participants[userID] = true
+
+	return true, nil
+}
+
+func (r *MemoryRepository) OptOut(ctx context.Context, namespace, roomID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return false, ErrRoomNotFound
+	}
+
+	if !room.participants[userID] {
+		return false, nil
+	}
+	delete(room.participants, userID)
+
+	return true, nil
+}
+
+func (r *MemoryRepository) IsOptedIn(ctx context.Context, namespace, roomID, userID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return false, ErrRoomNotFound
+	}
+
+	return room.participants[userID], nil
+}
+
+func (r *MemoryRepository) ListParticipants(ctx context.Context, namespace, roomID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	userIDs := make([]string, 0, len(room.participants))
+	for userID := range room.participants {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	return userIDs, nil
+}
+
+func (r *MemoryRepository) AddItem(ctx context.Context, namespace, roomID, userID, itemID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return ErrRoomNotFound
+	}
+
+	if room.items[userID] == nil {
+		room.items[userID] = make(map[string]*Item)
+	}
+
+	item, ok := room.items[userID][itemID]
+	if !ok {
+		item = &Item{ID: itemID}
+		room.items[userID][itemID] = item
+	}
+	item.Count++
+
+	return nil
+}
+
+func (r *MemoryRepository) ArmItem(ctx context.Context, namespace, roomID, userID, itemID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return false, ErrRoomNotFound
+	}
+
+	item, ok := room.items[userID][itemID]
+	if !ok || item.Count == 0 || item.Armed {
+		return false, nil
+	}
+	item.Count--
+	item.Armed = true
+
+	return true, nil
+}
+
+func (r *MemoryRepository) DisarmItem(ctx context.Context, namespace, roomID, userID, itemID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return false, ErrRoomNotFound
+	}
+
+	item, ok := room.items[userID][itemID]
+	if !ok || !item.Armed {
+		return false, nil
+	}
+	item.Armed = false
+
+	return true, nil
+}
+
+func (r *MemoryRepository) ListItems(ctx context.Context, namespace, roomID, userID string) ([]*Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	items := make([]*Item, 0, len(room.items[userID]))
+	for _, item := range room.items[userID] {
+		copied := *item
+		items = append(items, &copied)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+func (r *MemoryRepository) GetTournament(ctx context.Context, namespace, roomID string) (*Tournament, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	if len(room.tournaments) == 0 || room.tournaments[len(room.tournaments)-1].Ended() {
+		return nil, ErrTournamentNotFound
+	}
+
+	return copyTournament(room.tournaments[len(room.tournaments)-1]), nil
+}
+
+func (r *MemoryRepository) CreateTournament(ctx context.Context, namespace, roomID, channelID string) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	if len(room.tournaments) > 0 && !room.tournaments[len(room.tournaments)-1].Ended() {
+		return nil, ErrTournamentAlreadyExists
+	}
+
+	tournament := &Tournament{
+		Number:    len(room.tournaments) + 1,
+		ChannelID: channelID,
+		CreatedAt: time.Now(),
+	}
+	room.tournaments = append(room.tournaments, tournament)
+
+	return copyTournament(tournament), nil
+}
+
+func (r *MemoryRepository) RegisterPlayers(ctx context.Context, namespace, roomID string, number int, userIDs []string) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, err := r.tournament(namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Started() || tournament.Ended() {
+		return nil, ErrTournamentConflict
+	}
+
+	registered := make(map[string]bool)
+	for _, userID := range tournament.Players {
+		registered[userID] = true
+	}
+
+	for _, userID := range userIDs {
+		if !registered[userID] {
+			registered[userID] = true
+			tournament.Players = append(tournament.Players, userID)
+		}
+	}
+
+	return copyTournament(tournament), nil
+}
+
+func (r *MemoryRepository) StartRound(ctx context.Context, namespace, roomID string, number, round int, heats []*Heat) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, err := r.tournament(namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Round != round-1 || tournament.Ended() {
+		return nil, ErrTournamentConflict
+	}
+
+	tournament.Round = round
+	for _, heat := range heats {
+		tournament.Heats = append(tournament.Heats, &Heat{
+			Round:   round,
+			Number:  heat.Number,
+			Players: append([]string(nil), heat.Players...),
+		})
+	}
+
+	return copyTournament(tournament), nil
+}
+
+func (r *MemoryRepository) OpenHeat(ctx context.Context, namespace, roomID string, number, round, heat int, channelID string) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, err := r.tournament(namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	h := tournament.heat(round, heat)
+	if h == nil || h.ChannelID != "" {
+		return nil, ErrTournamentConflict
+	}
+	h.ChannelID = channelID
+
+	return copyTournament(tournament), nil
+}
+
+func (r *MemoryRepository) FinishHeat(ctx context.Context, namespace, roomID string, number, round, heat int, loserUserID string, closesAt time.Time) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, err := r.tournament(namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	h := tournament.heat(round, heat)
+	if h == nil || h.Finished() {
+		return nil, ErrTournamentConflict
+	}
+	h.LoserUserID = loserUserID
+	h.ClosesAt = closesAt
+
+	return copyTournament(tournament), nil
+}
+
+func (r *MemoryRepository) ListClosingHeats(ctx context.Context) ([]*ClosingHeat, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var heats []*ClosingHeat
+	for _, room := range r.rooms {
+		for _, tournament := range room.tournaments {
+			for _, h := range tournament.Heats {
+				if h.ClosesAt.IsZero() {
+					continue
+				}
+				heat := *h
+				heats = append(heats, &ClosingHeat{
+					Namespace:  room.namespace,
+					RoomID:     room.id,
+					Tournament: tournament.Number,
+					Heat:       &heat,
+				})
+			}
+		}
+	}
+
+	sort.Slice(heats, func(i, j int) bool {
+		return heats[i].Heat.ClosesAt.Before(heats[j].Heat.ClosesAt)
+	})
+
+	return heats, nil
+}
+
+func (r *MemoryRepository) CloseHeat(ctx context.Context, namespace, roomID string, number, round, heat int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, err := r.tournament(namespace, roomID, number)
+	if err != nil {
+		return err
+	}
+
+	if h := tournament.heat(round, heat); h != nil {
+		h.ClosesAt = time.Time{}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) EndTournament(ctx context.Context, namespace, roomID string, number int, winnerUserID string) (*Tournament, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tournament, err := r.tournament(namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Ended() {
+		return nil, ErrTournamentConflict
+	}
+	tournament.WinnerUserID = winnerUserID
+	tournament.EndedAt = time.Now()
+
+	return copyTournament(tournament), nil
+}
+
+// tournament finds the tournament with the given number, which must be called
+// with the lock held.
+func (r *MemoryRepository) tournament(namespace, roomID string, number int) (*Tournament, error) {
+	room, ok := r.rooms[memoryKey(namespace, roomID)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	if number < 1 || number > len(room.tournaments) {
+		return nil, ErrTournamentNotFound
+	}
+
+	return room.tournaments[number-1], nil
+}
+
+// Snapshot copies the rooms held in memory, returning a function that restores
+// them.
+func (r *MemoryRepository) Snapshot() func() {
+	r.mu.RLock()
+	rooms := make(map[string]*memoryRoom, len(r.rooms))
+	for key, room := range r.rooms {
+		rooms[key] = room.copy()
+	}
+	r.mu.RUnlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.rooms = rooms
+	}
+}
+
+func (r *memoryRoom) copy() *memoryRoom {
+	room := &memoryRoom{
+		namespace: r.namespace,
+		id:        r.id,
+		settings:  r.settings.Copy(),
+		seasons:   make([]*Season, len(r.seasons)),
+		deaths:    make(map[string]int, len(r.deaths)),
+		stats:     make(map[int]map[string]*UserStats, len(r.stats)),
+
+		achievements: make(map[string][]*Achievement, len(r.achievements)),
+		teamLosses:   make(map[string]int, len(r.teamLosses)),
+		wins:         make(map[string]int, len(r.wins)),
+		participants: make(map[string]bool, len(r.participants)),
+		items:        make(map[string]map[string]*Item, len(r.items)),
+		tournaments:  make([]*Tournament, len(r.tournaments)),
+	}
+
+	for i, season := range r.seasons {
+		s := *season
+		s.Standings = append([]DeathCounter(nil), season.Standings...)
+		s.Stats = append([]UserStats(nil), season.Stats...)
+		room.seasons[i] = &s
+	}
+	for userID, count := range r.deaths {
+		room.deaths[userID] = count
+	}
+	for number, stats := range r.stats {
+		room.stats[number] = make(map[string]*UserStats, len(stats))
+		for userID, s := range stats {
+			userStats := *s
+			room.stats[number][userID] = &userStats
+		}
+	}
+	for userID, achievements := range r.achievements {
+		for _, a := range achievements {
+			achievement := *a
+			room.achievements[userID] = append(room.achievements[userID], &achievement)
+		}
+	}
+	for team, count := range r.teamLosses {
+		room.teamLosses[team] = count
+	}
+	for userID, count := range r.wins {
+		room.wins[userID] = count
+	}
+	for userID, optedIn := range r.participants {
+		room.participants[userID] = optedIn
+	}
+	for userID, items := range r.items {
+		room.items[userID] = make(map[string]*Item, len(items))
+		for itemID, i := range items {
+			item := *i
+			room.items[userID][itemID] = &item
+		}
+	}
+	for i, tournament := range r.tournaments {
+		room.tournaments[i] = copyTournament(tournament)
+	}
+
+	return room
+}
+
+func (r *memoryRoom) toDomain() *Room {
+	return &Room{
+		Namespace:  r.namespace,
+		ID:         r.id,
+		Settings:   r.settings.Copy(),
+		Season:     len(r.seasons),
+		DeathCount: r.deathCount(),
+	}
+}
+
+func (r *memoryRoom) season(number int) *Season {
+	season := *r.seasons[number-1]
+	if season.Ended() {
+		season.Standings = append([]DeathCounter(nil), season.Standings...)
+	} else {
+		season.Standings = r.deathCount()
+	}
+
+	season.Stats = make([]UserStats, 0, len(r.stats[number]))
+	for _, stats := range r.stats[number] {
+		season.Stats = append(season.Stats, *stats)
+	}
+
+	return &season
+}
+
+func (r *memoryRoom) deathCount() []DeathCounter {
+	counters := make([]DeathCounter, 0, len(r.deaths))
+	for userID, count := range r.deaths {
+		counters = append(counters, DeathCounter{UserID: userID, Count: count})
+	}
+
+	return counters
+}
+
+func memoryKey(namespace, roomID string) string {
+	return namespace + "/" + roomID
+}
+
+func copyTournament(t *Tournament) *Tournament {
+	tournament := *t
+	tournament.Players = append([]string(nil), t.Players...)
+	tournament.Heats = make([]*Heat, len(t.Heats))
+	for i, heat := range t.Heats {
+		h := *heat
+		h.Players = append([]string(nil), heat.Players...)
+		tournament.Heats[i] = &h
+	}
+
+	return &tournament
+}
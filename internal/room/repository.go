@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/jace-ys/hot-potato-discord/internal/database"
 	"github.com/jace-ys/hot-potato-discord/internal/room/store"
-	"github.com/lib/pq"
 )
 
 type Repository struct {
@@ -21,8 +23,16 @@ func NewRepository(db *sql.DB) *Repository {
 	}
 }
 
+// queries runs queries in the transaction carried on the context, if any.
+func (r *Repository) queries(ctx context.Context) *store.Queries {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return r.store.WithTx(tx)
+	}
+	return r.store
+}
+
 func (r *Repository) GetRoom(ctx context.Context, namespace, roomID string) (*Room, error) {
-	room, err := r.store.GetRoom(ctx, store.GetRoomParams{
+	room, err := r.queries(ctx).GetRoom(ctx, store.GetRoomParams{
 		Namespace: namespace,
 		ID:        roomID,
 	})
@@ -34,7 +44,19 @@ func (r *Repository) GetRoom(ctx context.Context, namespace, roomID string) (*Ro
 		return nil, err
 	}
 
-	deaths, err := r.store.ListDeathCount(ctx, store.ListDeathCountParams{
+	season, err := r.queries(ctx).GetActiveSeason(ctx, store.GetActiveSeasonParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrSeasonNotFound
+		}
+		return nil, err
+	}
+
+	deaths, err := r.queries(ctx).ListDeathCount(ctx, store.ListDeathCountParams{
 		Namespace: namespace,
 		RoomID:    roomID,
 	})
@@ -46,28 +68,46 @@ func (r *Repository) GetRoom(ctx context.Context, namespace, roomID string) (*Ro
 		return nil, err
 	}
 
-	return StoreToDomain(room, deaths), nil
+	return StoreToDomain(room, season, deaths), nil
 }
 
 func (r *Repository) CreateRoom(ctx context.Context, namespace, roomID string) (*Room, error) {
-	room, err := r.store.InsertRoom(ctx, store.InsertRoomParams{
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	room, err := r.store.WithTx(tx.Tx).InsertRoom(ctx, store.InsertRoomParams{
 		Namespace: namespace,
 		ID:        roomID,
 	})
 	if err != nil {
-		var pqErr *pq.Error
 		switch {
-		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+		case database.IsUniqueViolation(err):
 			return nil, ErrRoomAlreadyExists
 		}
 		return nil, err
 	}
 
+	err = r.store.WithTx(tx.Tx).InsertSeason(ctx, store.InsertSeasonParams{
+		Namespace: room.Namespace,
+		RoomID:    room.ID,
+		Number:    1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return r.GetRoom(ctx, room.Namespace, room.ID)
 }
 
 func (r *Repository) IncrementDeaths(ctx context.Context, namespace, roomID, userID string) error {
-	err := r.store.IncrementDeathCount(ctx, store.IncrementDeathCountParams{
+	err := r.queries(ctx).IncrementDeathCount(ctx, store.IncrementDeathCountParams{
 		Namespace: namespace,
 		RoomID:    roomID,
 		UserID:    userID,
@@ -83,16 +123,825 @@ func (r *Repository) IncrementDeaths(ctx context.Context, namespace, roomID, use
 	return err
 }
 
-func StoreToDomain(room store.Room, deaths []store.Death) *Room {
-	r := &Room{
+func (r *Repository) UpdateSettings(ctx context.Context, namespace, roomID string, settings *Settings) (*Room, error) {
+	room, err := r.queries(ctx).UpdateRoomSettings(ctx, store.UpdateRoomSettingsParams{
+		Namespace:          namespace,
+		ID:                 roomID,
+		AllowedPotatoKinds: strings.Join(settings.AllowedPotatoKinds, ","),
+		ExplodeMultiplier:  settings.ExplodeMultiplier,
+		StealEnabled:       settings.StealEnabled,
+		CookCap:            int32(settings.CookCap),
+		FuseTimeoutSeconds: int32(settings.FuseTimeout / time.Second),
+		LeaderboardSize:    int32(settings.LeaderboardSize),
+		PotatoLimit:        int32(settings.PotatoLimit),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+
+	return r.GetRoom(ctx, room.Namespace, room.ID)
+}
+
+func (r *Repository) GetSeason(ctx context.Context, namespace, roomID string, number int) (*Season, error) {
+	season, err := r.queries(ctx).GetSeason(ctx, store.GetSeasonParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Number:    int32(number),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrSeasonNotFound
+		}
+		return nil, err
+	}
+
+	stats, err := r.queries(ctx).ListUserStats(ctx, store.ListUserStatsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Season:    int32(number),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !season.EndedAt.Valid {
+		deaths, err := r.queries(ctx).ListDeathCount(ctx, store.ListDeathCountParams{
+			Namespace: namespace,
+			RoomID:    roomID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return SeasonStoreToDomain(season, DeathsStoreToDomain(deaths), stats), nil
+	}
+
+	standings, err := r.queries(ctx).ListSeasonStandings(ctx, store.ListSeasonStandingsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Season:    int32(number),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return SeasonStoreToDomain(season, StandingsStoreToDomain(standings), stats), nil
+}
+
+func (r *Repository) EndSeason(ctx context.Context, namespace, roomID string, number int) (*Season, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ended, err := r.store.WithTx(tx.Tx).EndSeason(ctx, store.EndSeasonParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Number:    int32(number),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ended == 0 {
+		return nil, ErrSeasonAlreadyEnded
+	}
+
+	deaths, err := r.store.WithTx(tx.Tx).ListDeathCount(ctx, store.ListDeathCountParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, death := range deaths {
+		err := r.store.WithTx(tx.Tx).InsertSeasonStanding(ctx, store.InsertSeasonStandingParams{
+			Namespace: namespace,
+			RoomID:    roomID,
+			Season:    int32(number),
+			UserID:    death.UserID,
+			Count:     death.Count.Int32,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.store.WithTx(tx.Tx).ResetDeathCount(ctx, store.ResetDeathCountParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.store.WithTx(tx.Tx).InsertSeason(ctx, store.InsertSeasonParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Number:    int32(number + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetSeason(ctx, namespace, roomID, number)
+}
+
+func (r *Repository) RecordStats(ctx context.Context, namespace, roomID string, stats []UserStats) error {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	season, err := r.store.WithTx(tx.Tx).GetActiveSeason(ctx, store.GetActiveSeasonParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrSeasonNotFound
+		}
+		return err
+	}
+
+	for _, s := range stats {
+		err := r.store.WithTx(tx.Tx).RecordUserStats(ctx, store.RecordUserStatsParams{
+			Namespace:         namespace,
+			RoomID:            roomID,
+			Season:            season.Number,
+			UserID:            s.UserID,
+			GamesPlayed:       int32(s.GamesPlayed),
+			GamesSurvived:     int32(s.GamesSurvived),
+			Tosses:            int32(s.Tosses),
+			Steals:            int32(s.Steals),
+			Cooks:             int32(s.Cooks),
+			Kills:             int32(s.Kills),
+			LongestHoldStreak: int32(s.LongestHoldStreak),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UnlockAchievement unlocks the achievement for the user, reporting whether
+// they had not already unlocked it.
+func (r *Repository) UnlockAchievement(ctx context.Context, namespace, roomID, userID, achievementID string) (bool, error) {
+	unlocked, err := r.queries(ctx).UnlockAchievement(ctx, store.UnlockAchievementParams{
+		Namespace:     namespace,
+		RoomID:        roomID,
+		UserID:        userID,
+		AchievementID: achievementID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return unlocked > 0, nil
+}
+
+func (r *Repository) ListAchievements(ctx context.Context, namespace, roomID, userID string) ([]*Achievement, error) {
+	rows, err := r.queries(ctx).ListAchievements(ctx, store.ListAchievementsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	achievements := make([]*Achievement, len(rows))
+	for i, row := range rows {
+		achievements[i] = &Achievement{
+			ID:         row.AchievementID,
+			UnlockedAt: row.UnlockedAt.Time,
+		}
+	}
+
+	return achievements, nil
+}
+
+func (r *Repository) IncrementTeamLosses(ctx context.Context, namespace, roomID, team string) error {
+	return r.queries(ctx).IncrementTeamLosses(ctx, store.IncrementTeamLossesParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Team:      team,
+	})
+}
+
+func (r *Repository) ListTeamLosses(ctx context.Context, namespace, roomID string) ([]TeamLossCounter, error) {
+	rows, err := r.queries(ctx).ListTeamLosses(ctx, store.ListTeamLossesParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make([]TeamLossCounter, len(rows))
+	for i, row := range rows {
+		counters[i] = TeamLossCounter{
+			Team:  row.Team,
+			Count: int(row.Count),
+		}
+	}
+
+	return counters, nil
+}
+
+func (r *Repository) IncrementWins(ctx context.Context, namespace, roomID, userID string) error {
+	return r.queries(ctx).IncrementWins(ctx, store.IncrementWinsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+}
+
+func (r *Repository) ListWins(ctx context.Context, namespace, roomID string) ([]WinCounter, error) {
+	rows, err := r.queries(ctx).ListWins(ctx, store.ListWinsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make([]WinCounter, len(rows))
+	for i, row := range rows {
+		counters[i] = WinCounter{
+			UserID: row.UserID,
+			Count:  int(row.Count),
+		}
+	}
+
+	return counters, nil
+}
+
+// OptIn adds the user to the room's roster of participants, reporting whether
+// they had not already opted in.
+func (r *Repository) OptIn(ctx context.Context, namespace, roomID, userID string) (bool, error) {
+	added, err := r.queries(ctx).InsertParticipant(ctx, store.InsertParticipantParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added > 0, nil
+}
+
+// OptOut removes the user from the room's roster of participants, reporting
+// whether they had opted in.
+func (r *Repository) OptOut(ctx context.Context, namespace, roomID, userID string) (bool, error) {
+	removed, err := r.queries(ctx).DeleteParticipant(ctx, store.DeleteParticipantParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return removed > 0, nil
+}
+
+func (r *Repository) IsOptedIn(ctx context.Context, namespace, roomID, userID string) (bool, error) {
+	_, err := r.queries(ctx).GetParticipant(ctx, store.GetParticipantParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *Repository) ListParticipants(ctx context.Context, namespace, roomID string) ([]string, error) {
+	rows, err := r.queries(ctx).ListParticipants(ctx, store.ListParticipantsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, len(rows))
+	for i, row := range rows {
+		userIDs[i] = row.UserID
+	}
+
+	return userIDs, nil
+}
+
+func (r *Repository) AddItem(ctx context.Context, namespace, roomID, userID, itemID string) error {
+	return r.queries(ctx).AddItem(ctx, store.AddItemParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+		ItemID:    itemID,
+	})
+}
+
+// ArmItem takes one of the item out of the user's inventory to be armed,
+// reporting whether they held one and did not already have one armed.
+func (r *Repository) ArmItem(ctx context.Context, namespace, roomID, userID, itemID string) (bool, error) {
+	armed, err := r.queries(ctx).ArmItem(ctx, store.ArmItemParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+		ItemID:    itemID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return armed > 0, nil
+}
+
+// DisarmItem uses up the item that the user has armed, reporting whether they
+// had one armed.
+func (r *Repository) DisarmItem(ctx context.Context, namespace, roomID, userID, itemID string) (bool, error) {
+	disarmed, err := r.queries(ctx).DisarmItem(ctx, store.DisarmItemParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+		ItemID:    itemID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return disarmed > 0, nil
+}
+
+func (r *Repository) ListItems(ctx context.Context, namespace, roomID, userID string) ([]*Item, error) {
+	rows, err := r.queries(ctx).ListItems(ctx, store.ListItemsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Item, len(rows))
+	for i, row := range rows {
+		items[i] = &Item{
+			ID:    row.ItemID,
+			Count: int(row.Count),
+			Armed: row.Armed,
+		}
+	}
+
+	return items, nil
+}
+
+func (r *Repository) GetTournament(ctx context.Context, namespace, roomID string) (*Tournament, error) {
+	tournament, err := r.queries(ctx).GetActiveTournament(ctx, store.GetActiveTournamentParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrTournamentNotFound
+		}
+		return nil, err
+	}
+
+	return withBracket(ctx, r.queries(ctx), tournament)
+}
+
+func (r *Repository) CreateTournament(ctx context.Context, namespace, roomID, channelID string) (*Tournament, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.store.WithTx(tx.Tx)
+
+	_, err = q.GetActiveTournament(ctx, store.GetActiveTournamentParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	switch {
+	case err == nil:
+		return nil, ErrTournamentAlreadyExists
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	count, err := q.CountTournaments(ctx, store.CountTournamentsParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := q.InsertTournament(ctx, store.InsertTournamentParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Number:    int32(count + 1),
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := withBracket(ctx, q, tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, tx.Commit()
+}
+
+func (r *Repository) RegisterPlayers(ctx context.Context, namespace, roomID string, number int, userIDs []string) (*Tournament, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.store.WithTx(tx.Tx)
+
+	tournament, err := getTournament(ctx, q, namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Round != 0 || tournament.EndedAt.Valid {
+		return nil, ErrTournamentConflict
+	}
+
+	for _, userID := range userIDs {
+		err := q.InsertTournamentPlayer(ctx, store.InsertTournamentPlayerParams{
+			Namespace:  namespace,
+			RoomID:     roomID,
+			Tournament: int32(number),
+			UserID:     userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	next, err := withBracket(ctx, q, tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, tx.Commit()
+}
+
+func (r *Repository) StartRound(ctx context.Context, namespace, roomID string, number, round int, heats []*Heat) (*Tournament, error) {
+	tx, err := database.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.store.WithTx(tx.Tx)
+
+	advanced, err := q.AdvanceTournamentRound(ctx, store.AdvanceTournamentRoundParams{
+		Namespace:     namespace,
+		RoomID:        roomID,
+		Number:        int32(number),
+		Round:         int32(round),
+		ExpectedRound: int32(round - 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if advanced == 0 {
+		return nil, ErrTournamentConflict
+	}
+
+	for _, heat := range heats {
+		err := q.InsertTournamentHeat(ctx, store.InsertTournamentHeatParams{
+			Namespace:  namespace,
+			RoomID:     roomID,
+			Tournament: int32(number),
+			Round:      int32(round),
+			Heat:       int32(heat.Number),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for seat, userID := range heat.Players {
+			err := q.InsertTournamentHeatPlayer(ctx, store.InsertTournamentHeatPlayerParams{
+				Namespace:  namespace,
+				RoomID:     roomID,
+				Tournament: int32(number),
+				Round:      int32(round),
+				Heat:       int32(heat.Number),
+				UserID:     userID,
+				Seat:       int32(seat),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tournament, err := getTournament(ctx, q, namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := withBracket(ctx, q, tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	return next, tx.Commit()
+}
+
+func (r *Repository) OpenHeat(ctx context.Context, namespace, roomID string, number, round, heat int, channelID string) (*Tournament, error) {
+	opened, err := r.queries(ctx).OpenTournamentHeat(ctx, store.OpenTournamentHeatParams{
+		Namespace:  namespace,
+		RoomID:     roomID,
+		Tournament: int32(number),
+		Round:      int32(round),
+		Heat:       int32(heat),
+		ChannelID:  channelID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opened == 0 {
+		return nil, ErrTournamentConflict
+	}
+
+	tournament, err := getTournament(ctx, r.queries(ctx), namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return withBracket(ctx, r.queries(ctx), tournament)
+}
+
+func (r *Repository) FinishHeat(ctx context.Context, namespace, roomID string, number, round, heat int, loserUserID string, closesAt time.Time) (*Tournament, error) {
+	finished, err := r.queries(ctx).FinishTournamentHeat(ctx, store.FinishTournamentHeatParams{
+		Namespace:   namespace,
+		RoomID:      roomID,
+		Tournament:  int32(number),
+		Round:       int32(round),
+		Heat:        int32(heat),
+		LoserUserID: loserUserID,
+		ClosesAt:    sql.NullTime{Time: closesAt, Valid: !closesAt.IsZero()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if finished == 0 {
+		return nil, ErrTournamentConflict
+	}
+
+	tournament, err := getTournament(ctx, r.queries(ctx), namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return withBracket(ctx, r.queries(ctx), tournament)
+}
+
+func (r *Repository) ListClosingHeats(ctx context.Context) ([]*ClosingHeat, error) {
+	rows, err := r.queries(ctx).ListClosingTournamentHeats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	heats := make([]*ClosingHeat, len(rows))
+	for i, row := range rows {
+		heats[i] = &ClosingHeat{
+			Namespace:  row.Namespace,
+			RoomID:     row.RoomID,
+			Tournament: int(row.Tournament),
+			Heat: &Heat{
+				Round:       int(row.Round),
+				Number:      int(row.Heat),
+				ChannelID:   row.ChannelID,
+				LoserUserID: row.LoserUserID,
+				ClosesAt:    row.ClosesAt.Time,
+			},
+		}
+	}
+
+	return heats, nil
+}
+
+func (r *Repository) CloseHeat(ctx context.Context, namespace, roomID string, number, round, heat int) error {
+	return r.queries(ctx).CloseTournamentHeat(ctx, store.CloseTournamentHeatParams{
+		Namespace:  namespace,
+		RoomID:     roomID,
+		Tournament: int32(number),
+		Round:      int32(round),
+		Heat:       int32(heat),
+	})
+}
+
+func (r *Repository) EndTournament(ctx context.Context, namespace, roomID string, number int, winnerUserID string) (*Tournament, error) {
+	ended, err := r.queries(ctx).EndTournament(ctx, store.EndTournamentParams{
+		Namespace:    namespace,
+		RoomID:       roomID,
+		Number:       int32(number),
+		WinnerUserID: winnerUserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ended == 0 {
+		return nil, ErrTournamentConflict
+	}
+
+	tournament, err := getTournament(ctx, r.queries(ctx), namespace, roomID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return withBracket(ctx, r.queries(ctx), tournament)
+}
+
+func getTournament(ctx context.Context, q *store.Queries, namespace, roomID string, number int) (store.Tournament, error) {
+	tournament, err := q.GetTournament(ctx, store.GetTournamentParams{
+		Namespace: namespace,
+		RoomID:    roomID,
+		Number:    int32(number),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return tournament, ErrTournamentNotFound
+		}
+		return tournament, err
+	}
+
+	return tournament, nil
+}
+
+// withBracket converts the tournament to its domain model, along with its
+// players and the heats drawn so far.
+func withBracket(ctx context.Context, q *store.Queries, tournament store.Tournament) (*Tournament, error) {
+	players, err := q.ListTournamentPlayers(ctx, store.ListTournamentPlayersParams{
+		Namespace:  tournament.Namespace,
+		RoomID:     tournament.RoomID,
+		Tournament: tournament.Number,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	heats, err := q.ListTournamentHeats(ctx, store.ListTournamentHeatsParams{
+		Namespace:  tournament.Namespace,
+		RoomID:     tournament.RoomID,
+		Tournament: tournament.Number,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seats, err := q.ListTournamentHeatPlayers(ctx, store.ListTournamentHeatPlayersParams{
+		Namespace:  tournament.Namespace,
+		RoomID:     tournament.RoomID,
+		Tournament: tournament.Number,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return TournamentStoreToDomain(tournament, players, heats, seats), nil
+}
+
+func StoreToDomain(room store.Room, season store.Season, deaths []store.Death) *Room {
+	return &Room{
 		Namespace:  room.Namespace,
 		ID:         room.ID,
-		DeathCount: make([]DeathCounter, len(deaths)),
+		Settings:   SettingsStoreToDomain(room),
+		Season:     int(season.Number),
+		DeathCount: DeathsStoreToDomain(deaths),
 	}
+}
 
+func DeathsStoreToDomain(deaths []store.Death) []DeathCounter {
+	counters := make([]DeathCounter, len(deaths))
 	for i, row := range deaths {
-		r.DeathCount[i] = DeathCounter{UserID: row.UserID, Count: int(row.Count.Int32)}
+		counters[i] = DeathCounter{UserID: row.UserID, Count: int(row.Count.Int32)}
+	}
+
+	return counters
+}
+
+func StandingsStoreToDomain(standings []store.SeasonStanding) []DeathCounter {
+	counters := make([]DeathCounter, len(standings))
+	for i, row := range standings {
+		counters[i] = DeathCounter{UserID: row.UserID, Count: int(row.Count)}
+	}
+
+	return counters
+}
+
+func StatsStoreToDomain(stats []store.UserStat) []UserStats {
+	domain := make([]UserStats, len(stats))
+	for i, row := range stats {
+		domain[i] = UserStats{
+			UserID:            row.UserID,
+			GamesPlayed:       int(row.GamesPlayed),
+			GamesSurvived:     int(row.GamesSurvived),
+			Tosses:            int(row.Tosses),
+			Steals:            int(row.Steals),
+			Cooks:             int(row.Cooks),
+			Kills:             int(row.Kills),
+			LongestHoldStreak: int(row.LongestHoldStreak),
+		}
+	}
+
+	return domain
+}
+
+func SeasonStoreToDomain(season store.Season, standings []DeathCounter, stats []store.UserStat) *Season {
+	return &Season{
+		Number:    int(season.Number),
+		StartedAt: season.StartedAt.Time,
+		EndedAt:   season.EndedAt.Time,
+		Standings: standings,
+		Stats:     StatsStoreToDomain(stats),
+	}
+}
+
+func SettingsStoreToDomain(room store.Room) *Settings {
+	settings := &Settings{
+		ExplodeMultiplier: room.ExplodeMultiplier,
+		StealEnabled:      room.StealEnabled,
+		CookCap:           int(room.CookCap),
+		FuseTimeout:       time.Duration(room.FuseTimeoutSeconds) * time.Second,
+		LeaderboardSize:   int(room.LeaderboardSize),
+		PotatoLimit:       int(room.PotatoLimit),
+	}
+
+	if room.AllowedPotatoKinds != "" {
+		settings.AllowedPotatoKinds = strings.Split(room.AllowedPotatoKinds, ",")
+	}
+
+	return settings
+}
+
+func TournamentStoreToDomain(tournament store.Tournament, players []store.TournamentPlayer, heats []store.TournamentHeat, seats []store.TournamentHeatPlayer) *Tournament {
+	t := &Tournament{
+		Number:       int(tournament.Number),
+		ChannelID:    tournament.ChannelID,
+		Round:        int(tournament.Round),
+		Players:      make([]string, len(players)),
+		Heats:        make([]*Heat, len(heats)),
+		WinnerUserID: tournament.WinnerUserID,
+		CreatedAt:    tournament.CreatedAt,
+		EndedAt:      tournament.EndedAt.Time,
+	}
+
+	for i, row := range players {
+		t.Players[i] = row.UserID
+	}
+
+	for i, row := range heats {
+		heat := &Heat{
+			Round:       int(row.Round),
+			Number:      int(row.Heat),
+			ChannelID:   row.ChannelID,
+			LoserUserID: row.LoserUserID,
+			ClosesAt:    row.ClosesAt.Time,
+		}
+		for _, seat := range seats {
+			if seat.Round == row.Round && seat.Heat == row.Heat {
+				heat.Players = append(heat.Players, seat.UserID)
+			}
+		}
+		t.Heats[i] = heat
 	}
 
-	return r
+	return t
 }
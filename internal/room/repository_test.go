@@ -0,0 +1,242 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/jace-ys/hot-potato-discord/db"
+	"github.com/jace-ys/hot-potato-discord/internal/database"
+)
+
+const (
+	testNamespace = "guild"
+	testRoomID    = "room"
+)
+
+type testStorage struct {
+	name string
+	open func(t *testing.T) RoomRepository
+}
+
+var testStorages = []testStorage{
+	{name: "memory", open: func(t *testing.T) RoomRepository { return NewMemoryRepository() }},
+	{name: "sqlite", open: openSQLiteRepository},
+}
+
+func openSQLiteRepository(t *testing.T) RoomRepository {
+	sqldb, dialect, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "hotpotato.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqldb.Close() })
+
+	migrator := database.NewMigrator(log.NewNopLogger(), sqldb, dialect, db.Migrations(string(dialect)))
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	return NewRepository(sqldb)
+}
+
+func createRoom(t *testing.T, rooms RoomRepository) *Room {
+	r, err := rooms.CreateRoom(context.Background(), testNamespace, testRoomID)
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	return r
+}
+
+func incrementDeaths(t *testing.T, rooms RoomRepository, userIDs ...string) {
+	for _, userID := range userIDs {
+		if err := rooms.IncrementDeaths(context.Background(), testNamespace, testRoomID, userID); err != nil {
+			t.Fatalf("failed to increment deaths of %s: %v", userID, err)
+		}
+	}
+}
+
+func sortDeaths(counters []DeathCounter) []DeathCounter {
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].UserID < counters[j].UserID
+	})
+	return counters
+}
+
+func TestRepositoryCreateRoom(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			rooms := storage.open(t)
+			ctx := context.Background()
+
+			if _, err := rooms.GetRoom(ctx, testNamespace, testRoomID); !errors.Is(err, ErrRoomNotFound) {
+				t.Fatalf("getting a missing room returned %v, want ErrRoomNotFound", err)
+			}
+
+			r := createRoom(t, rooms)
+			if r.Namespace != testNamespace || r.ID != testRoomID || r.Season != 1 || len(r.DeathCount) != 0 {
+				t.Errorf("unexpected room: %+v", r)
+			}
+			if !reflect.DeepEqual(r.Settings, DefaultSettings()) {
+				t.Errorf("room created with settings %+v, want defaults", r.Settings)
+			}
+
+			if _, err := rooms.CreateRoom(ctx, testNamespace, testRoomID); !errors.Is(err, ErrRoomAlreadyExists) {
+				t.Errorf("creating the room again returned %v, want ErrRoomAlreadyExists", err)
+			}
+
+			if _, err := rooms.CreateRoom(ctx, "other", testRoomID); err != nil {
+				t.Errorf("failed to create room in another namespace: %v", err)
+			}
+		})
+	}
+}
+
+func TestRepositoryIncrementDeaths(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			rooms := storage.open(t)
+			ctx := context.Background()
+
+			createRoom(t, rooms)
+			incrementDeaths(t, rooms, "a", "b", "a")
+
+			r, err := rooms.GetRoom(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to get room: %v", err)
+			}
+
+			want := []DeathCounter{{UserID: "a", Count: 2}, {UserID: "b", Count: 1}}
+			if got := sortDeaths(r.DeathCount); !reflect.DeepEqual(got, want) {
+				t.Errorf("got death counts %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestRepositoryUpdateSettings(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			rooms := storage.open(t)
+			ctx := context.Background()
+
+			settings := &Settings{
+				AllowedPotatoKinds: []string{"hot", "burnt"},
+				ExplodeMultiplier:  2.5,
+				CookCap:            5,
+				FuseTimeout:        90 * time.Second,
+				LeaderboardSize:    15,
+				PotatoLimit:        3,
+			}
+
+			if _, err := rooms.UpdateSettings(ctx, testNamespace, testRoomID, settings); !errors.Is(err, ErrRoomNotFound) {
+				t.Fatalf("updating the settings of a missing room returned %v, want ErrRoomNotFound", err)
+			}
+
+			createRoom(t, rooms)
+			updated, err := rooms.UpdateSettings(ctx, testNamespace, testRoomID, settings)
+			if err != nil {
+				t.Fatalf("failed to update settings: %v", err)
+			}
+			if !reflect.DeepEqual(updated.Settings, settings) {
+				t.Errorf("updated settings to %+v, want %+v", updated.Settings, settings)
+			}
+
+			r, err := rooms.GetRoom(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to get room: %v", err)
+			}
+			if !reflect.DeepEqual(r.Settings, settings) {
+				t.Errorf("got settings %+v, want %+v", r.Settings, settings)
+			}
+		})
+	}
+}
+
+func TestRepositoryEndSeason(t *testing.T) {
+	for _, storage := range testStorages {
+		t.Run(storage.name, func(t *testing.T) {
+			rooms := storage.open(t)
+			ctx := context.Background()
+
+			createRoom(t, rooms)
+			incrementDeaths(t, rooms, "a", "b", "a")
+			if err := rooms.RecordStats(ctx, testNamespace, testRoomID, []UserStats{{UserID: "a", GamesPlayed: 1, LongestHoldStreak: 3}}); err != nil {
+				t.Fatalf("failed to record stats: %v", err)
+			}
+
+			ended, err := rooms.EndSeason(ctx, testNamespace, testRoomID, 1)
+			if err != nil {
+				t.Fatalf("failed to end season: %v", err)
+			}
+			if ended.Number != 1 || !ended.Ended() {
+				t.Errorf("unexpected ended season: %+v", ended)
+			}
+
+			if _, err := rooms.EndSeason(ctx, testNamespace, testRoomID, 1); !errors.Is(err, ErrSeasonAlreadyEnded) {
+				t.Errorf("ending the season again returned %v, want ErrSeasonAlreadyEnded", err)
+			}
+
+			r, err := rooms.GetRoom(ctx, testNamespace, testRoomID)
+			if err != nil {
+				t.Fatalf("failed to get room: %v", err)
+			}
+			if r.Season != 2 || len(r.DeathCount) != 0 {
+				t.Errorf("room in season %d with death counts %+v, want season 2 without deaths", r.Season, r.DeathCount)
+			}
+
+			incrementDeaths(t, rooms, "c")
+
+			tests := []struct {
+				number    int
+				ended     bool
+				standings []DeathCounter
+				stats     []UserStats
+				err       error
+			}{
+				{
+					number:    1,
+					ended:     true,
+					standings: []DeathCounter{{UserID: "a", Count: 2}, {UserID: "b", Count: 1}},
+					stats:     []UserStats{{UserID: "a", GamesPlayed: 1, LongestHoldStreak: 3}},
+				},
+				{
+					number:    2,
+					standings: []DeathCounter{{UserID: "c", Count: 1}},
+				},
+				{
+					number: 3,
+					err:    ErrSeasonNotFound,
+				},
+			}
+
+			for _, tt := range tests {
+				season, err := rooms.GetSeason(ctx, testNamespace, testRoomID, tt.number)
+				if tt.err != nil {
+					if !errors.Is(err, tt.err) {
+						t.Errorf("getting season %d returned %v, want %v", tt.number, err, tt.err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("failed to get season %d: %v", tt.number, err)
+				}
+
+				if season.Number != tt.number || season.Ended() != tt.ended {
+					t.Errorf("got season %d ended %t, want season %d ended %t", season.Number, season.Ended(), tt.number, tt.ended)
+				}
+				if got := sortDeaths(season.Standings); !reflect.DeepEqual(got, tt.standings) {
+					t.Errorf("season %d has standings %+v, want %+v", tt.number, got, tt.standings)
+				}
+				if len(season.Stats) != len(tt.stats) || (len(tt.stats) > 0 && !reflect.DeepEqual(season.Stats, tt.stats)) {
+					t.Errorf("season %d has stats %+v, want %+v", tt.number, season.Stats, tt.stats)
+				}
+			}
+		})
+	}
+}
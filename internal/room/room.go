@@ -3,22 +3,54 @@ package room
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
-	ErrRoomAlreadyExists = errors.New("room for guild already exists")
-	ErrRoomNotFound      = errors.New("room for guild not found")
+	ErrRoomAlreadyExists  = errors.New("room for guild already exists")
+	ErrRoomNotFound       = errors.New("room for guild not found")
+	ErrSeasonNotFound     = errors.New("season for guild not found")
+	ErrSeasonAlreadyEnded = errors.New("season for guild already ended")
 )
 
 type RoomRepository interface {
 	GetRoom(ctx context.Context, namespace, roomID string) (*Room, error)
 	CreateRoom(ctx context.Context, namespace, roomID string) (*Room, error)
 	IncrementDeaths(ctx context.Context, namespace, roomID, userID string) error
+	UpdateSettings(ctx context.Context, namespace, roomID string, settings *Settings) (*Room, error)
+	GetSeason(ctx context.Context, namespace, roomID string, number int) (*Season, error)
+	EndSeason(ctx context.Context, namespace, roomID string, number int) (*Season, error)
+	RecordStats(ctx context.Context, namespace, roomID string, stats []UserStats) error
+	UnlockAchievement(ctx context.Context, namespace, roomID, userID, achievementID string) (bool, error)
+	ListAchievements(ctx context.Context, namespace, roomID, userID string) ([]*Achievement, error)
+	IncrementTeamLosses(ctx context.Context, namespace, roomID, team string) error
+	ListTeamLosses(ctx context.Context, namespace, roomID string) ([]TeamLossCounter, error)
+	IncrementWins(ctx context.Context, namespace, roomID, userID string) error
+	ListWins(ctx context.Context, namespace, roomID string) ([]WinCounter, error)
+	OptIn(ctx context.Context, namespace, roomID, userID string) (bool, error)
+	OptOut(ctx context.Context, namespace, roomID, userID string) (bool, error)
+	IsOptedIn(ctx context.Context, namespace, roomID, userID string) (bool, error)
+	ListParticipants(ctx context.Context, namespace, roomID string) ([]string, error)
+	AddItem(ctx context.Context, namespace, roomID, userID, itemID string) error
+	ArmItem(ctx context.Context, namespace, roomID, userID, itemID string) (bool, error)
+	DisarmItem(ctx context.Context, namespace, roomID, userID, itemID string) (bool, error)
+	ListItems(ctx context.Context, namespace, roomID, userID string) ([]*Item, error)
+	GetTournament(ctx context.Context, namespace, roomID string) (*Tournament, error)
+	CreateTournament(ctx context.Context, namespace, roomID, channelID string) (*Tournament, error)
+	RegisterPlayers(ctx context.Context, namespace, roomID string, number int, userIDs []string) (*Tournament, error)
+	StartRound(ctx context.Context, namespace, roomID string, number, round int, heats []*Heat) (*Tournament, error)
+	OpenHeat(ctx context.Context, namespace, roomID string, number, round, heat int, channelID string) (*Tournament, error)
+	FinishHeat(ctx context.Context, namespace, roomID string, number, round, heat int, loserUserID string, closesAt time.Time) (*Tournament, error)
+	ListClosingHeats(ctx context.Context) ([]*ClosingHeat, error)
+	CloseHeat(ctx context.Context, namespace, roomID string, number, round, heat int) error
+	EndTournament(ctx context.Context, namespace, roomID string, number int, winnerUserID string) (*Tournament, error)
 }
 
 type Room struct {
 	Namespace  string
 	ID         string
+	Settings   *Settings
+	Season     int
 	DeathCount []DeathCounter
 }
 
@@ -26,3 +58,29 @@ type DeathCounter struct {
 	UserID string
 	Count  int
 }
+
+// TeamLossCounter is the number of team games that a team has lost in a room.
+type TeamLossCounter struct {
+	Team  string
+	Count int
+}
+
+// WinCounter is the number of elimination games that a user has won in a room.
+type WinCounter struct {
+	UserID string
+	Count  int
+}
+
+// Item is a kind of item in a user's inventory in a room. Count is the number
+// of the item that the user holds on to, not counting the one they have armed.
+type Item struct {
+	ID    string
+	Count int
+	Armed bool
+}
+
+// Achievement is an achievement that a user has unlocked in a room.
+type Achievement struct {
+	ID         string
+	UnlockedAt time.Time
+}
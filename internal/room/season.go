@@ -0,0 +1,18 @@
+package room
+
+import "time"
+
+// Season is a period over which deaths and stats in a room are counted towards
+// the leaderboard. Only the latest season of a room is active; ended seasons
+// keep their final standings and stats.
+type Season struct {
+	Number    int
+	StartedAt time.Time
+	EndedAt   time.Time
+	Standings []DeathCounter
+	Stats     []UserStats
+}
+
+func (s *Season) Ended() bool {
+	return !s.EndedAt.IsZero()
+}
@@ -0,0 +1,79 @@
+package room
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultExplodeMultiplier = 1
+	DefaultLeaderboardSize   = 10
+	DefaultPotatoLimit       = 1
+
+	MaxExplodeMultiplier = 10
+	MaxLeaderboardSize   = 25
+	MaxPotatoLimit       = 25
+)
+
+// Settings are the rules a room plays Hot Potato by, as configured by its
+// admins.
+type Settings struct {
+	// AllowedPotatoKinds restricts the kinds of potatoes that new games are
+	// started with. All kinds are allowed when empty.
+	AllowedPotatoKinds []string
+
+	// ExplodeMultiplier scales the chance of a potato exploding on each turn.
+	ExplodeMultiplier float64
+
+	StealEnabled bool
+
+	// CookCap is the heat level beyond which a potato can no longer be cooked,
+	// or 0 for no cap.
+	CookCap int
+
+	// FuseTimeout is how long a potato can be held before it explodes on its
+	// own, or 0 to use the default fuse timeout.
+	FuseTimeout time.Duration
+
+	// LeaderboardSize is the number of users shown on each page of the
+	// leaderboard.
+	LeaderboardSize int
+
+	// PotatoLimit is the number of potatoes that can be in play in a channel at
+	// once.
+	PotatoLimit int
+}
+
+func DefaultSettings() *Settings {
+	return &Settings{
+		ExplodeMultiplier: DefaultExplodeMultiplier,
+		StealEnabled:      true,
+		LeaderboardSize:   DefaultLeaderboardSize,
+		PotatoLimit:       DefaultPotatoLimit,
+	}
+}
+
+func (s *Settings) Validate() error {
+	switch {
+	case s.ExplodeMultiplier < 0 || s.ExplodeMultiplier > MaxExplodeMultiplier:
+		return errors.New("explode multiplier must be between 0 and 10")
+	case s.CookCap < 0:
+		return errors.New("cook cap cannot be negative")
+	case s.FuseTimeout < 0:
+		return errors.New("fuse timeout cannot be negative")
+	case s.FuseTimeout%time.Second != 0:
+		return errors.New("fuse timeout must be a whole number of seconds")
+	case s.LeaderboardSize < 1 || s.LeaderboardSize > MaxLeaderboardSize:
+		return errors.New("leaderboard size must be between 1 and 25")
+	case s.PotatoLimit < 1 || s.PotatoLimit > MaxPotatoLimit:
+		return errors.New("potato limit must be between 1 and 25")
+	default:
+		return nil
+	}
+}
+
+func (s *Settings) Copy() *Settings {
+	settings := *s
+	settings.AllowedPotatoKinds = append([]string(nil), s.AllowedPotatoKinds...)
+	return &settings
+}
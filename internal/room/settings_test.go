@@ -0,0 +1,55 @@
+package room
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(s *Settings)
+		invalid bool
+	}{
+		{name: "defaults", modify: func(s *Settings) {}},
+		{name: "explode multiplier disabled", modify: func(s *Settings) { s.ExplodeMultiplier = 0 }},
+		{name: "explode multiplier at max", modify: func(s *Settings) { s.ExplodeMultiplier = MaxExplodeMultiplier }},
+		{name: "explode multiplier negative", modify: func(s *Settings) { s.ExplodeMultiplier = -0.5 }, invalid: true},
+		{name: "explode multiplier over max", modify: func(s *Settings) { s.ExplodeMultiplier = MaxExplodeMultiplier + 0.5 }, invalid: true},
+		{name: "cook cap", modify: func(s *Settings) { s.CookCap = 5 }},
+		{name: "cook cap negative", modify: func(s *Settings) { s.CookCap = -1 }, invalid: true},
+		{name: "fuse timeout", modify: func(s *Settings) { s.FuseTimeout = time.Minute }},
+		{name: "fuse timeout negative", modify: func(s *Settings) { s.FuseTimeout = -time.Second }, invalid: true},
+		{name: "fuse timeout fractional", modify: func(s *Settings) { s.FuseTimeout = 1500 * time.Millisecond }, invalid: true},
+		{name: "leaderboard size at max", modify: func(s *Settings) { s.LeaderboardSize = MaxLeaderboardSize }},
+		{name: "leaderboard size zero", modify: func(s *Settings) { s.LeaderboardSize = 0 }, invalid: true},
+		{name: "leaderboard size over max", modify: func(s *Settings) { s.LeaderboardSize = MaxLeaderboardSize + 1 }, invalid: true},
+		{name: "potato limit at max", modify: func(s *Settings) { s.PotatoLimit = MaxPotatoLimit }},
+		{name: "potato limit zero", modify: func(s *Settings) { s.PotatoLimit = 0 }, invalid: true},
+		{name: "potato limit over max", modify: func(s *Settings) { s.PotatoLimit = MaxPotatoLimit + 1 }, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultSettings()
+			tt.modify(settings)
+
+			if err := settings.Validate(); (err != nil) != tt.invalid {
+				t.Errorf("Validate() = %v, want invalid %t", err, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestSettingsCopy(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AllowedPotatoKinds = []string{"hot", "burnt"}
+
+	copied := settings.Copy()
+	copied.AllowedPotatoKinds[0] = "raw"
+	copied.PotatoLimit = 5
+
+	if settings.AllowedPotatoKinds[0] != "hot" || settings.PotatoLimit != DefaultPotatoLimit {
+		t.Errorf("changing the copy changed the settings: %+v", settings)
+	}
+}
@@ -0,0 +1,29 @@
+package room
+
+// UserStats are the stats of a user over the games they have played in a
+// season of a room. When recording stats, all fields except LongestHoldStreak
+// are added to the user's existing stats, while LongestHoldStreak only
+// replaces the existing value if it is longer.
+type UserStats struct {
+	UserID            string
+	GamesPlayed       int
+	GamesSurvived     int
+	Tosses            int
+	Steals            int
+	Cooks             int
+	Kills             int
+	LongestHoldStreak int
+}
+
+// Add merges the given stats for the same user into the stats.
+func (s *UserStats) Add(stats UserStats) {
+	s.GamesPlayed += stats.GamesPlayed
+	s.GamesSurvived += stats.GamesSurvived
+	s.Tosses += stats.Tosses
+	s.Steals += stats.Steals
+	s.Cooks += stats.Cooks
+	s.Kills += stats.Kills
+	if stats.LongestHoldStreak > s.LongestHoldStreak {
+		s.LongestHoldStreak = stats.LongestHoldStreak
+	}
+}
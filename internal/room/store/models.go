@@ -4,8 +4,25 @@ package store
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Achievement struct {
+	Namespace     string
+	RoomID        string
+	UserID        string
+	AchievementID string
+	UnlockedAt    sql.NullTime
+}
+
+type ChannelActivity struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	UserID    string
+	ActiveAt  time.Time
+}
+
 type Death struct {
 	Namespace string
 	RoomID    string
@@ -23,10 +40,164 @@ type Game struct {
 	Turns        int32
 	Finished     bool
 	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Seed         int64
+	Round        int32
+	PotatoID     int32
+}
+
+type GameLobby struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	UserID    string
+	JoinedAt  time.Time
+}
+
+type GamePlayer struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	PotatoID  int32
+	Round     int32
+	UserID    string
+}
+
+type GameTeam struct {
+	Namespace string
+	RoomID    string
+	ChannelID string
+	Round     int32
+	UserID    string
+	Team      string
+	PotatoID  int32
+}
+
+type GameTurn struct {
+	Namespace     string
+	RoomID        string
+	ChannelID     string
+	Round         int32
+	Turn          int32
+	Action        string
+	ActorUserID   string
+	TargetUserID  string
+	HeatLevel     int32
+	ExplodeChance int32
+	Exploded      bool
+	CreatedAt     sql.NullTime
+	PotatoID      int32
+}
+
+type Item struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	ItemID    string
+	Count     int32
+	Armed     bool
+}
+
+type Participant struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	OptedInAt time.Time
 }
 
 type Room struct {
+	Namespace          string
+	ID                 string
+	CreatedAt          sql.NullTime
+	AllowedPotatoKinds string
+	ExplodeMultiplier  float64
+	StealEnabled       bool
+	CookCap            int32
+	FuseTimeoutSeconds int32
+	LeaderboardSize    int32
+	PotatoLimit        int32
+}
+
+type Season struct {
 	Namespace string
-	ID        string
-	CreatedAt sql.NullTime
+	RoomID    string
+	Number    int32
+	StartedAt sql.NullTime
+	EndedAt   sql.NullTime
+}
+
+type SeasonStanding struct {
+	Namespace string
+	RoomID    string
+	Season    int32
+	UserID    string
+	Count     int32
+}
+
+type TeamLoss struct {
+	Namespace string
+	RoomID    string
+	Team      string
+	Count     int32
+}
+
+type Tournament struct {
+	Namespace    string
+	RoomID       string
+	Number       int32
+	ChannelID    string
+	Round        int32
+	WinnerUserID string
+	CreatedAt    time.Time
+	EndedAt      sql.NullTime
+}
+
+type TournamentHeat struct {
+	Namespace   string
+	RoomID      string
+	Tournament  int32
+	Round       int32
+	Heat        int32
+	ChannelID   string
+	LoserUserID string
+	ClosesAt    sql.NullTime
+}
+
+type TournamentHeatPlayer struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+	Round      int32
+	Heat       int32
+	UserID     string
+	Seat       int32
+}
+
+type TournamentPlayer struct {
+	Namespace    string
+	RoomID       string
+	Tournament   int32
+	UserID       string
+	RegisteredAt time.Time
+}
+
+type UserStat struct {
+	Namespace         string
+	RoomID            string
+	Season            int32
+	UserID            string
+	GamesPlayed       int32
+	GamesSurvived     int32
+	Tosses            int32
+	Steals            int32
+	Cooks             int32
+	Kills             int32
+	LongestHoldStreak int32
+}
+
+type Win struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	Count     int32
 }
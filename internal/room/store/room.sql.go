@@ -5,10 +5,333 @@ package store
 
 import (
 	"context"
+	"database/sql"
 )
 
+const addItem = `-- name: AddItem :exec
+INSERT INTO items (
+  namespace, room_id, user_id, item_id, count
+) VALUES (
+  $1, $2, $3, $4, 1
+) ON CONFLICT (namespace, room_id, user_id, item_id)
+  DO UPDATE SET count = items.count + 1
+`
+
+type AddItemParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	ItemID    string
+}
+
+func (q *Queries) AddItem(ctx context.Context, arg AddItemParams) error {
+	_, err := q.db.ExecContext(ctx, addItem,
+		arg.Namespace,
+		arg.RoomID,
+		arg.UserID,
+		arg.ItemID,
+	)
+	return err
+}
+
+const advanceTournamentRound = `-- name: AdvanceTournamentRound :execrows
+UPDATE tournaments
+SET round = $4
+WHERE namespace = $1 AND room_id = $2 AND number = $3 AND round = $5 AND ended_at IS NULL
+`
+
+type AdvanceTournamentRoundParams struct {
+	Namespace     string
+	RoomID        string
+	Number        int32
+	Round         int32
+	ExpectedRound int32
+}
+
+func (q *Queries) AdvanceTournamentRound(ctx context.Context, arg AdvanceTournamentRoundParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, advanceTournamentRound,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Number,
+		arg.Round,
+		arg.ExpectedRound,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const armItem = `-- name: ArmItem :execrows
+UPDATE items
+SET count = count - 1, armed = true
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3 AND item_id = $4 AND count > 0 AND armed = false
+`
+
+type ArmItemParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	ItemID    string
+}
+
+func (q *Queries) ArmItem(ctx context.Context, arg ArmItemParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, armItem,
+		arg.Namespace,
+		arg.RoomID,
+		arg.UserID,
+		arg.ItemID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const closeTournamentHeat = `-- name: CloseTournamentHeat :exec
+UPDATE tournament_heats
+SET closes_at = NULL
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3 AND round = $4 AND heat = $5
+`
+
+type CloseTournamentHeatParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+	Round      int32
+	Heat       int32
+}
+
+func (q *Queries) CloseTournamentHeat(ctx context.Context, arg CloseTournamentHeatParams) error {
+	_, err := q.db.ExecContext(ctx, closeTournamentHeat,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Tournament,
+		arg.Round,
+		arg.Heat,
+	)
+	return err
+}
+
+const countTournaments = `-- name: CountTournaments :one
+SELECT COUNT(*) AS count FROM tournaments
+WHERE namespace = $1 AND room_id = $2
+`
+
+type CountTournamentsParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) CountTournaments(ctx context.Context, arg CountTournamentsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTournaments, arg.Namespace, arg.RoomID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteParticipant = `-- name: DeleteParticipant :execrows
+DELETE FROM participants
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+`
+
+type DeleteParticipantParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) DeleteParticipant(ctx context.Context, arg DeleteParticipantParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteParticipant, arg.Namespace, arg.RoomID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const disarmItem = `-- name: DisarmItem :execrows
+UPDATE items
+SET armed = false
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3 AND item_id = $4 AND armed = true
+`
+
+type DisarmItemParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+	ItemID    string
+}
+
+func (q *Queries) DisarmItem(ctx context.Context, arg DisarmItemParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, disarmItem,
+		arg.Namespace,
+		arg.RoomID,
+		arg.UserID,
+		arg.ItemID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const endSeason = `-- name: EndSeason :execrows
+UPDATE seasons
+SET ended_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND room_id = $2 AND number = $3 AND ended_at IS NULL
+`
+
+type EndSeasonParams struct {
+	Namespace string
+	RoomID    string
+	Number    int32
+}
+
+func (q *Queries) EndSeason(ctx context.Context, arg EndSeasonParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, endSeason, arg.Namespace, arg.RoomID, arg.Number)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const endTournament = `-- name: EndTournament :execrows
+UPDATE tournaments
+SET winner_user_id = $4, ended_at = CURRENT_TIMESTAMP
+WHERE namespace = $1 AND room_id = $2 AND number = $3 AND ended_at IS NULL
+`
+
+type EndTournamentParams struct {
+	Namespace    string
+	RoomID       string
+	Number       int32
+	WinnerUserID string
+}
+
+func (q *Queries) EndTournament(ctx context.Context, arg EndTournamentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, endTournament,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Number,
+		arg.WinnerUserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const finishTournamentHeat = `-- name: FinishTournamentHeat :execrows
+UPDATE tournament_heats
+SET loser_user_id = $6, closes_at = $7
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3 AND round = $4 AND heat = $5 AND loser_user_id = ''
+`
+
+type FinishTournamentHeatParams struct {
+	Namespace   string
+	RoomID      string
+	Tournament  int32
+	Round       int32
+	Heat        int32
+	LoserUserID string
+	ClosesAt    sql.NullTime
+}
+
+func (q *Queries) FinishTournamentHeat(ctx context.Context, arg FinishTournamentHeatParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, finishTournamentHeat,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Tournament,
+		arg.Round,
+		arg.Heat,
+		arg.LoserUserID,
+		arg.ClosesAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getActiveSeason = `-- name: GetActiveSeason :one
+SELECT namespace, room_id, number, started_at, ended_at FROM seasons
+WHERE namespace = $1 AND room_id = $2 AND ended_at IS NULL
+ORDER BY number DESC
+LIMIT 1
+`
+
+type GetActiveSeasonParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) GetActiveSeason(ctx context.Context, arg GetActiveSeasonParams) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getActiveSeason, arg.Namespace, arg.RoomID)
+	var i Season
+	err := row.Scan(
+		&i.Namespace,
+		&i.RoomID,
+		&i.Number,
+		&i.StartedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getActiveTournament = `-- name: GetActiveTournament :one
+SELECT namespace, room_id, number, channel_id, round, winner_user_id, created_at, ended_at FROM tournaments
+WHERE namespace = $1 AND room_id = $2 AND ended_at IS NULL
+ORDER BY number DESC
+LIMIT 1
+`
+
+type GetActiveTournamentParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) GetActiveTournament(ctx context.Context, arg GetActiveTournamentParams) (Tournament, error) {
+	row := q.db.QueryRowContext(ctx, getActiveTournament, arg.Namespace, arg.RoomID)
+	var i Tournament
+	err := row.Scan(
+		&i.Namespace,
+		&i.RoomID,
+		&i.Number,
+		&i.ChannelID,
+		&i.Round,
+		&i.WinnerUserID,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getParticipant = `-- name: GetParticipant :one
+SELECT namespace, room_id, user_id, opted_in_at FROM participants
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+LIMIT 1
+`
+
+type GetParticipantParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) GetParticipant(ctx context.Context, arg GetParticipantParams) (Participant, error) {
+	row := q.db.QueryRowContext(ctx, getParticipant, arg.Namespace, arg.RoomID, arg.UserID)
+	var i Participant
+	err := row.Scan(
+		&i.Namespace,
+		&i.RoomID,
+		&i.UserID,
+		&i.OptedInAt,
+	)
+	return i, err
+}
+
 const getRoom = `-- name: GetRoom :one
-SELECT namespace, id, created_at FROM rooms
+SELECT namespace, id, created_at, allowed_potato_kinds, explode_multiplier, steal_enabled, cook_cap, fuse_timeout_seconds, leaderboard_size, potato_limit FROM rooms
 WHERE namespace = $1 AND id = $2
 LIMIT 1
 `
@@ -21,7 +344,71 @@ type GetRoomParams struct {
 func (q *Queries) GetRoom(ctx context.Context, arg GetRoomParams) (Room, error) {
 	row := q.db.QueryRowContext(ctx, getRoom, arg.Namespace, arg.ID)
 	var i Room
-	err := row.Scan(&i.Namespace, &i.ID, &i.CreatedAt)
+	err := row.Scan(
+		&i.Namespace,
+		&i.ID,
+		&i.CreatedAt,
+		&i.AllowedPotatoKinds,
+		&i.ExplodeMultiplier,
+		&i.StealEnabled,
+		&i.CookCap,
+		&i.FuseTimeoutSeconds,
+		&i.LeaderboardSize,
+		&i.PotatoLimit,
+	)
+	return i, err
+}
+
+const getSeason = `-- name: GetSeason :one
+SELECT namespace, room_id, number, started_at, ended_at FROM seasons
+WHERE namespace = $1 AND room_id = $2 AND number = $3
+LIMIT 1
+`
+
+type GetSeasonParams struct {
+	Namespace string
+	RoomID    string
+	Number    int32
+}
+
+func (q *Queries) GetSeason(ctx context.Context, arg GetSeasonParams) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getSeason, arg.Namespace, arg.RoomID, arg.Number)
+	var i Season
+	err := row.Scan(
+		&i.Namespace,
+		&i.RoomID,
+		&i.Number,
+		&i.StartedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getTournament = `-- name: GetTournament :one
+SELECT namespace, room_id, number, channel_id, round, winner_user_id, created_at, ended_at FROM tournaments
+WHERE namespace = $1 AND room_id = $2 AND number = $3
+LIMIT 1
+`
+
+type GetTournamentParams struct {
+	Namespace string
+	RoomID    string
+	Number    int32
+}
+
+func (q *Queries) GetTournament(ctx context.Context, arg GetTournamentParams) (Tournament, error) {
+	row := q.db.QueryRowContext(ctx, getTournament, arg.Namespace, arg.RoomID, arg.Number)
+	var i Tournament
+	err := row.Scan(
+		&i.Namespace,
+		&i.RoomID,
+		&i.Number,
+		&i.ChannelID,
+		&i.Round,
+		&i.WinnerUserID,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
 	return i, err
 }
 
@@ -46,13 +433,75 @@ func (q *Queries) IncrementDeathCount(ctx context.Context, arg IncrementDeathCou
 	return err
 }
 
+const incrementTeamLosses = `-- name: IncrementTeamLosses :exec
+INSERT INTO team_losses (
+  namespace, room_id, team, count
+) VALUES (
+  $1, $2, $3, 1
+) ON CONFLICT (namespace, room_id, team)
+  DO UPDATE SET count = team_losses.count + 1
+`
+
+type IncrementTeamLossesParams struct {
+	Namespace string
+	RoomID    string
+	Team      string
+}
+
+func (q *Queries) IncrementTeamLosses(ctx context.Context, arg IncrementTeamLossesParams) error {
+	_, err := q.db.ExecContext(ctx, incrementTeamLosses, arg.Namespace, arg.RoomID, arg.Team)
+	return err
+}
+
+const incrementWins = `-- name: IncrementWins :exec
+INSERT INTO wins (
+  namespace, room_id, user_id, count
+) VALUES (
+  $1, $2, $3, 1
+) ON CONFLICT (namespace, room_id, user_id)
+  DO UPDATE SET count = wins.count + 1
+`
+
+type IncrementWinsParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) IncrementWins(ctx context.Context, arg IncrementWinsParams) error {
+	_, err := q.db.ExecContext(ctx, incrementWins, arg.Namespace, arg.RoomID, arg.UserID)
+	return err
+}
+
+const insertParticipant = `-- name: InsertParticipant :execrows
+INSERT INTO participants (
+  namespace, room_id, user_id
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (namespace, room_id, user_id) DO NOTHING
+`
+
+type InsertParticipantParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) InsertParticipant(ctx context.Context, arg InsertParticipantParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertParticipant, arg.Namespace, arg.RoomID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const insertRoom = `-- name: InsertRoom :one
 INSERT INTO rooms (
   namespace, id 
 ) VALUES (
   $1, $2
 )
-RETURNING namespace, id, created_at
+RETURNING namespace, id, created_at, allowed_potato_kinds, explode_multiplier, steal_enabled, cook_cap, fuse_timeout_seconds, leaderboard_size, potato_limit
 `
 
 type InsertRoomParams struct {
@@ -63,34 +512,214 @@ type InsertRoomParams struct {
 func (q *Queries) InsertRoom(ctx context.Context, arg InsertRoomParams) (Room, error) {
 	row := q.db.QueryRowContext(ctx, insertRoom, arg.Namespace, arg.ID)
 	var i Room
-	err := row.Scan(&i.Namespace, &i.ID, &i.CreatedAt)
+	err := row.Scan(
+		&i.Namespace,
+		&i.ID,
+		&i.CreatedAt,
+		&i.AllowedPotatoKinds,
+		&i.ExplodeMultiplier,
+		&i.StealEnabled,
+		&i.CookCap,
+		&i.FuseTimeoutSeconds,
+		&i.LeaderboardSize,
+		&i.PotatoLimit,
+	)
 	return i, err
 }
 
-const listDeathCount = `-- name: ListDeathCount :many
-SELECT namespace, room_id, user_id, count FROM deaths
-WHERE namespace = $1 AND room_id = $2
+const insertSeason = `-- name: InsertSeason :exec
+INSERT INTO seasons (
+  namespace, room_id, number
+) VALUES (
+  $1, $2, $3
+)
 `
 
-type ListDeathCountParams struct {
+type InsertSeasonParams struct {
 	Namespace string
 	RoomID    string
+	Number    int32
 }
 
-func (q *Queries) ListDeathCount(ctx context.Context, arg ListDeathCountParams) ([]Death, error) {
-	rows, err := q.db.QueryContext(ctx, listDeathCount, arg.Namespace, arg.RoomID)
+func (q *Queries) InsertSeason(ctx context.Context, arg InsertSeasonParams) error {
+	_, err := q.db.ExecContext(ctx, insertSeason, arg.Namespace, arg.RoomID, arg.Number)
+	return err
+}
+
+const insertSeasonStanding = `-- name: InsertSeasonStanding :exec
+INSERT INTO season_standings (
+  namespace, room_id, season, user_id, count
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+`
+
+type InsertSeasonStandingParams struct {
+	Namespace string
+	RoomID    string
+	Season    int32
+	UserID    string
+	Count     int32
+}
+
+func (q *Queries) InsertSeasonStanding(ctx context.Context, arg InsertSeasonStandingParams) error {
+	_, err := q.db.ExecContext(ctx, insertSeasonStanding,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Season,
+		arg.UserID,
+		arg.Count,
+	)
+	return err
+}
+
+const insertTournament = `-- name: InsertTournament :one
+INSERT INTO tournaments (
+  namespace, room_id, number, channel_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING namespace, room_id, number, channel_id, round, winner_user_id, created_at, ended_at
+`
+
+type InsertTournamentParams struct {
+	Namespace string
+	RoomID    string
+	Number    int32
+	ChannelID string
+}
+
+func (q *Queries) InsertTournament(ctx context.Context, arg InsertTournamentParams) (Tournament, error) {
+	row := q.db.QueryRowContext(ctx, insertTournament,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Number,
+		arg.ChannelID,
+	)
+	var i Tournament
+	err := row.Scan(
+		&i.Namespace,
+		&i.RoomID,
+		&i.Number,
+		&i.ChannelID,
+		&i.Round,
+		&i.WinnerUserID,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const insertTournamentHeat = `-- name: InsertTournamentHeat :exec
+INSERT INTO tournament_heats (
+  namespace, room_id, tournament, round, heat
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+`
+
+type InsertTournamentHeatParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+	Round      int32
+	Heat       int32
+}
+
+func (q *Queries) InsertTournamentHeat(ctx context.Context, arg InsertTournamentHeatParams) error {
+	_, err := q.db.ExecContext(ctx, insertTournamentHeat,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Tournament,
+		arg.Round,
+		arg.Heat,
+	)
+	return err
+}
+
+const insertTournamentHeatPlayer = `-- name: InsertTournamentHeatPlayer :exec
+INSERT INTO tournament_heat_players (
+  namespace, room_id, tournament, round, heat, user_id, seat
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+`
+
+type InsertTournamentHeatPlayerParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+	Round      int32
+	Heat       int32
+	UserID     string
+	Seat       int32
+}
+
+func (q *Queries) InsertTournamentHeatPlayer(ctx context.Context, arg InsertTournamentHeatPlayerParams) error {
+	_, err := q.db.ExecContext(ctx, insertTournamentHeatPlayer,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Tournament,
+		arg.Round,
+		arg.Heat,
+		arg.UserID,
+		arg.Seat,
+	)
+	return err
+}
+
+const insertTournamentPlayer = `-- name: InsertTournamentPlayer :exec
+INSERT INTO tournament_players (
+  namespace, room_id, tournament, user_id
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (namespace, room_id, tournament, user_id) DO NOTHING
+`
+
+type InsertTournamentPlayerParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+	UserID     string
+}
+
+func (q *Queries) InsertTournamentPlayer(ctx context.Context, arg InsertTournamentPlayerParams) error {
+	_, err := q.db.ExecContext(ctx, insertTournamentPlayer,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Tournament,
+		arg.UserID,
+	)
+	return err
+}
+
+const listAchievements = `-- name: ListAchievements :many
+SELECT namespace, room_id, user_id, achievement_id, unlocked_at FROM achievements
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+ORDER BY unlocked_at
+`
+
+type ListAchievementsParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) ListAchievements(ctx context.Context, arg ListAchievementsParams) ([]Achievement, error) {
+	rows, err := q.db.QueryContext(ctx, listAchievements, arg.Namespace, arg.RoomID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Death
+	var items []Achievement
 	for rows.Next() {
-		var i Death
+		var i Achievement
 		if err := rows.Scan(
 			&i.Namespace,
 			&i.RoomID,
 			&i.UserID,
-			&i.Count,
+			&i.AchievementID,
+			&i.UnlockedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -104,3 +733,620 @@ func (q *Queries) ListDeathCount(ctx context.Context, arg ListDeathCountParams)
 	}
 	return items, nil
 }
+
+const listClosingTournamentHeats = `-- name: ListClosingTournamentHeats :many
+SELECT namespace, room_id, tournament, round, heat, channel_id, loser_user_id, closes_at FROM tournament_heats
+WHERE closes_at IS NOT NULL
+ORDER BY closes_at
+`
+
+func (q *Queries) ListClosingTournamentHeats(ctx context.Context) ([]TournamentHeat, error) {
+	rows, err := q.db.QueryContext(ctx, listClosingTournamentHeats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TournamentHeat
+	for rows.Next() {
+		var i TournamentHeat
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Tournament,
+			&i.Round,
+			&i.Heat,
+			&i.ChannelID,
+			&i.LoserUserID,
+			&i.ClosesAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeathCount = `-- name: ListDeathCount :many
+SELECT namespace, room_id, user_id, count FROM deaths
+WHERE namespace = $1 AND room_id = $2
+`
+
+type ListDeathCountParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) ListDeathCount(ctx context.Context, arg ListDeathCountParams) ([]Death, error) {
+	rows, err := q.db.QueryContext(ctx, listDeathCount, arg.Namespace, arg.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Death
+	for rows.Next() {
+		var i Death
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.UserID,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItems = `-- name: ListItems :many
+SELECT namespace, room_id, user_id, item_id, count, armed FROM items
+WHERE namespace = $1 AND room_id = $2 AND user_id = $3
+ORDER BY item_id
+`
+
+type ListItemsParams struct {
+	Namespace string
+	RoomID    string
+	UserID    string
+}
+
+func (q *Queries) ListItems(ctx context.Context, arg ListItemsParams) ([]Item, error) {
+	rows, err := q.db.QueryContext(ctx, listItems, arg.Namespace, arg.RoomID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Item
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.UserID,
+			&i.ItemID,
+			&i.Count,
+			&i.Armed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listParticipants = `-- name: ListParticipants :many
+SELECT namespace, room_id, user_id, opted_in_at FROM participants
+WHERE namespace = $1 AND room_id = $2
+ORDER BY user_id
+`
+
+type ListParticipantsParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) ListParticipants(ctx context.Context, arg ListParticipantsParams) ([]Participant, error) {
+	rows, err := q.db.QueryContext(ctx, listParticipants, arg.Namespace, arg.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Participant
+	for rows.Next() {
+		var i Participant
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.UserID,
+			&i.OptedInAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeasonStandings = `-- name: ListSeasonStandings :many
+SELECT namespace, room_id, season, user_id, count FROM season_standings
+WHERE namespace = $1 AND room_id = $2 AND season = $3
+`
+
+type ListSeasonStandingsParams struct {
+	Namespace string
+	RoomID    string
+	Season    int32
+}
+
+func (q *Queries) ListSeasonStandings(ctx context.Context, arg ListSeasonStandingsParams) ([]SeasonStanding, error) {
+	rows, err := q.db.QueryContext(ctx, listSeasonStandings, arg.Namespace, arg.RoomID, arg.Season)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SeasonStanding
+	for rows.Next() {
+		var i SeasonStanding
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Season,
+			&i.UserID,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamLosses = `-- name: ListTeamLosses :many
+SELECT namespace, room_id, team, count FROM team_losses
+WHERE namespace = $1 AND room_id = $2
+`
+
+type ListTeamLossesParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) ListTeamLosses(ctx context.Context, arg ListTeamLossesParams) ([]TeamLoss, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamLosses, arg.Namespace, arg.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamLoss
+	for rows.Next() {
+		var i TeamLoss
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Team,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTournamentHeatPlayers = `-- name: ListTournamentHeatPlayers :many
+SELECT namespace, room_id, tournament, round, heat, user_id, seat FROM tournament_heat_players
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3
+ORDER BY round, heat, seat
+`
+
+type ListTournamentHeatPlayersParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+}
+
+func (q *Queries) ListTournamentHeatPlayers(ctx context.Context, arg ListTournamentHeatPlayersParams) ([]TournamentHeatPlayer, error) {
+	rows, err := q.db.QueryContext(ctx, listTournamentHeatPlayers, arg.Namespace, arg.RoomID, arg.Tournament)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TournamentHeatPlayer
+	for rows.Next() {
+		var i TournamentHeatPlayer
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Tournament,
+			&i.Round,
+			&i.Heat,
+			&i.UserID,
+			&i.Seat,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTournamentHeats = `-- name: ListTournamentHeats :many
+SELECT namespace, room_id, tournament, round, heat, channel_id, loser_user_id, closes_at FROM tournament_heats
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3
+ORDER BY round, heat
+`
+
+type ListTournamentHeatsParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+}
+
+func (q *Queries) ListTournamentHeats(ctx context.Context, arg ListTournamentHeatsParams) ([]TournamentHeat, error) {
+	rows, err := q.db.QueryContext(ctx, listTournamentHeats, arg.Namespace, arg.RoomID, arg.Tournament)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TournamentHeat
+	for rows.Next() {
+		var i TournamentHeat
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Tournament,
+			&i.Round,
+			&i.Heat,
+			&i.ChannelID,
+			&i.LoserUserID,
+			&i.ClosesAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTournamentPlayers = `-- name: ListTournamentPlayers :many
+SELECT namespace, room_id, tournament, user_id, registered_at FROM tournament_players
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3
+ORDER BY registered_at, user_id
+`
+
+type ListTournamentPlayersParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+}
+
+func (q *Queries) ListTournamentPlayers(ctx context.Context, arg ListTournamentPlayersParams) ([]TournamentPlayer, error) {
+	rows, err := q.db.QueryContext(ctx, listTournamentPlayers, arg.Namespace, arg.RoomID, arg.Tournament)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TournamentPlayer
+	for rows.Next() {
+		var i TournamentPlayer
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Tournament,
+			&i.UserID,
+			&i.RegisteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserStats = `-- name: ListUserStats :many
+SELECT namespace, room_id, season, user_id, games_played, games_survived, tosses, steals, cooks, kills, longest_hold_streak FROM user_stats
+WHERE namespace = $1 AND room_id = $2 AND season = $3
+`
+
+type ListUserStatsParams struct {
+	Namespace string
+	RoomID    string
+	Season    int32
+}
+
+func (q *Queries) ListUserStats(ctx context.Context, arg ListUserStatsParams) ([]UserStat, error) {
+	rows, err := q.db.QueryContext(ctx, listUserStats, arg.Namespace, arg.RoomID, arg.Season)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserStat
+	for rows.Next() {
+		var i UserStat
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.Season,
+			&i.UserID,
+			&i.GamesPlayed,
+			&i.GamesSurvived,
+			&i.Tosses,
+			&i.Steals,
+			&i.Cooks,
+			&i.Kills,
+			&i.LongestHoldStreak,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWins = `-- name: ListWins :many
+SELECT namespace, room_id, user_id, count FROM wins
+WHERE namespace = $1 AND room_id = $2
+`
+
+type ListWinsParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) ListWins(ctx context.Context, arg ListWinsParams) ([]Win, error) {
+	rows, err := q.db.QueryContext(ctx, listWins, arg.Namespace, arg.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Win
+	for rows.Next() {
+		var i Win
+		if err := rows.Scan(
+			&i.Namespace,
+			&i.RoomID,
+			&i.UserID,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const openTournamentHeat = `-- name: OpenTournamentHeat :execrows
+UPDATE tournament_heats
+SET channel_id = $6
+WHERE namespace = $1 AND room_id = $2 AND tournament = $3 AND round = $4 AND heat = $5 AND channel_id = ''
+`
+
+type OpenTournamentHeatParams struct {
+	Namespace  string
+	RoomID     string
+	Tournament int32
+	Round      int32
+	Heat       int32
+	ChannelID  string
+}
+
+func (q *Queries) OpenTournamentHeat(ctx context.Context, arg OpenTournamentHeatParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, openTournamentHeat,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Tournament,
+		arg.Round,
+		arg.Heat,
+		arg.ChannelID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordUserStats = `-- name: RecordUserStats :exec
+INSERT INTO user_stats (
+  namespace, room_id, season, user_id, games_played, games_survived, tosses, steals, cooks, kills, longest_hold_streak
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) ON CONFLICT (namespace, room_id, season, user_id)
+  DO UPDATE SET
+    games_played = user_stats.games_played + excluded.games_played,
+    games_survived = user_stats.games_survived + excluded.games_survived,
+    tosses = user_stats.tosses + excluded.tosses,
+    steals = user_stats.steals + excluded.steals,
+    cooks = user_stats.cooks + excluded.cooks,
+    kills = user_stats.kills + excluded.kills,
+    longest_hold_streak = CASE
+      WHEN excluded.longest_hold_streak > user_stats.longest_hold_streak THEN excluded.longest_hold_streak
+      ELSE user_stats.longest_hold_streak
+    END
+`
+
+type RecordUserStatsParams struct {
+	Namespace         string
+	RoomID            string
+	Season            int32
+	UserID            string
+	GamesPlayed       int32
+	GamesSurvived     int32
+	Tosses            int32
+	Steals            int32
+	Cooks             int32
+	Kills             int32
+	LongestHoldStreak int32
+}
+
+func (q *Queries) RecordUserStats(ctx context.Context, arg RecordUserStatsParams) error {
+	_, err := q.db.ExecContext(ctx, recordUserStats,
+		arg.Namespace,
+		arg.RoomID,
+		arg.Season,
+		arg.UserID,
+		arg.GamesPlayed,
+		arg.GamesSurvived,
+		arg.Tosses,
+		arg.Steals,
+		arg.Cooks,
+		arg.Kills,
+		arg.LongestHoldStreak,
+	)
+	return err
+}
+
+const resetDeathCount = `-- name: ResetDeathCount :exec
+DELETE FROM deaths
+WHERE namespace = $1 AND room_id = $2
+`
+
+type ResetDeathCountParams struct {
+	Namespace string
+	RoomID    string
+}
+
+func (q *Queries) ResetDeathCount(ctx context.Context, arg ResetDeathCountParams) error {
+	_, err := q.db.ExecContext(ctx, resetDeathCount, arg.Namespace, arg.RoomID)
+	return err
+}
+
+const unlockAchievement = `-- name: UnlockAchievement :execrows
+INSERT INTO achievements (
+  namespace, room_id, user_id, achievement_id
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (namespace, room_id, user_id, achievement_id) DO NOTHING
+`
+
+type UnlockAchievementParams struct {
+	Namespace     string
+	RoomID        string
+	UserID        string
+	AchievementID string
+}
+
+func (q *Queries) UnlockAchievement(ctx context.Context, arg UnlockAchievementParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unlockAchievement,
+		arg.Namespace,
+		arg.RoomID,
+		arg.UserID,
+		arg.AchievementID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateRoomSettings = `-- name: UpdateRoomSettings :one
+UPDATE rooms
+SET allowed_potato_kinds = $3, explode_multiplier = $4, steal_enabled = $5, cook_cap = $6, fuse_timeout_seconds = $7, leaderboard_size = $8, potato_limit = $9
+WHERE namespace = $1 AND id = $2
+RETURNING namespace, id, created_at, allowed_potato_kinds, explode_multiplier, steal_enabled, cook_cap, fuse_timeout_seconds, leaderboard_size, potato_limit
+`
+
+type UpdateRoomSettingsParams struct {
+	Namespace          string
+	ID                 string
+	AllowedPotatoKinds string
+	ExplodeMultiplier  float64
+	StealEnabled       bool
+	CookCap            int32
+	FuseTimeoutSeconds int32
+	LeaderboardSize    int32
+	PotatoLimit        int32
+}
+
+func (q *Queries) UpdateRoomSettings(ctx context.Context, arg UpdateRoomSettingsParams) (Room, error) {
+	row := q.db.QueryRowContext(ctx, updateRoomSettings,
+		arg.Namespace,
+		arg.ID,
+		arg.AllowedPotatoKinds,
+		arg.ExplodeMultiplier,
+		arg.StealEnabled,
+		arg.CookCap,
+		arg.FuseTimeoutSeconds,
+		arg.LeaderboardSize,
+		arg.PotatoLimit,
+	)
+	var i Room
+	err := row.Scan(
+		&i.Namespace,
+		&i.ID,
+		&i.CreatedAt,
+		&i.AllowedPotatoKinds,
+		&i.ExplodeMultiplier,
+		&i.StealEnabled,
+		&i.CookCap,
+		&i.FuseTimeoutSeconds,
+		&i.LeaderboardSize,
+		&i.PotatoLimit,
+	)
+	return i, err
+}
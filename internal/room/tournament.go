@@ -0,0 +1,180 @@
+package room
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrTournamentNotFound      = errors.New("tournament for guild not found")
+	ErrTournamentAlreadyExists = errors.New("tournament for guild already in progress")
+	ErrTournamentConflict      = errors.New("tournament for guild has already moved on")
+)
+
+// Tournament is a single-elimination tournament played in a room. Its players
+// are drawn into heats each round, and whoever is holding the potato when it
+// explodes in their heat is knocked out, until a single winner is left.
+type Tournament struct {
+	Number int
+
+	// ChannelID is the channel that the tournament was set up in, where
+	// updates to the bracket are posted.
+	ChannelID string
+
+	// Round is the round being played, or 0 while players are registering.
+	Round int
+
+	Players      []string
+	Heats        []*Heat
+	WinnerUserID string
+	CreatedAt    time.Time
+	EndedAt      time.Time
+}
+
+func (t *Tournament) Started() bool {
+	return t.Round > 0
+}
+
+func (t *Tournament) Ended() bool {
+	return !t.EndedAt.IsZero()
+}
+
+// RoundHeats lists the heats drawn for the given round.
+func (t *Tournament) RoundHeats(round int) []*Heat {
+	var heats []*Heat
+	for _, heat := range t.Heats {
+		if heat.Round == round {
+			heats = append(heats, heat)
+		}
+	}
+
+	return heats
+}
+
+// RoundFinished reports whether every heat of the current round has ended.
+func (t *Tournament) RoundFinished() bool {
+	for _, heat := range t.RoundHeats(t.Round) {
+		if !heat.Finished() {
+			return false
+		}
+	}
+
+	return t.Started()
+}
+
+// LiveHeat returns the heat of the current round being played in the given
+// channel, if there is one.
+func (t *Tournament) LiveHeat(channelID string) *Heat {
+	for _, heat := range t.RoundHeats(t.Round) {
+		if heat.ChannelID == channelID && !heat.Finished() {
+			return heat
+		}
+	}
+
+	return nil
+}
+
+func (t *Tournament) heat(round, number int) *Heat {
+	for _, heat := range t.Heats {
+		if heat.Round == round && heat.Number == number {
+			return heat
+		}
+	}
+
+	return nil
+}
+
+// Survivors lists the players that have not been knocked out of the
+// tournament, in the order they registered.
+func (t *Tournament) Survivors() []string {
+	knockedOut := make(map[string]bool)
+	for _, heat := range t.Heats {
+		if heat.Finished() {
+			knockedOut[heat.LoserUserID] = true
+		}
+	}
+
+	var survivors []string
+	for _, userID := range t.Players {
+		if !knockedOut[userID] {
+			survivors = append(survivors, userID)
+		}
+	}
+
+	return survivors
+}
+
+// Byes lists the players that were not drawn into a heat in the given round,
+// and so go through to the next round without playing.
+func (t *Tournament) Byes(round int) []string {
+	drawn := make(map[string]bool)
+	for _, heat := range t.RoundHeats(round) {
+		for _, userID := range heat.Players {
+			drawn[userID] = true
+		}
+	}
+
+	var byes []string
+	for _, userID := range t.Players {
+		if !drawn[userID] && t.survivedTo(userID, round) {
+			byes = append(byes, userID)
+		}
+	}
+
+	return byes
+}
+
+// survivedTo reports whether the player was still in the tournament at the
+// start of the given round.
+func (t *Tournament) survivedTo(userID string, round int) bool {
+	for _, heat := range t.Heats {
+		if heat.Round < round && heat.LoserUserID == userID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Heat is a game played between some of the players of a tournament in a
+// channel of its own.
+type Heat struct {
+	Round  int
+	Number int
+
+	// ChannelID is the channel that the heat is played in, or empty until a
+	// channel has been opened for it.
+	ChannelID string
+
+	// Players are the players drawn into the heat, starting with the one who
+	// is handed the potato first.
+	Players []string
+
+	LoserUserID string
+
+	// ClosesAt is when the channel of a finished heat is due to be closed, or
+	// zero if there is no channel left to close.
+	ClosesAt time.Time
+}
+
+// ClosingHeat is a finished heat whose channel is due to be closed.
+type ClosingHeat struct {
+	Namespace  string
+	RoomID     string
+	Tournament int
+	Heat       *Heat
+}
+
+func (h *Heat) Finished() bool {
+	return h.LoserUserID != ""
+}
+
+func (h *Heat) Has(userID string) bool {
+	for _, player := range h.Players {
+		if player == userID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,9 +2,8 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
-	"math/rand"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,9 +14,9 @@ import (
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/alecthomas/kingpin.v2"
 
-	_ "github.com/lib/pq"
-
+	"github.com/jace-ys/hot-potato-discord/db"
 	"github.com/jace-ys/hot-potato-discord/internal/bedrock"
+	"github.com/jace-ys/hot-potato-discord/internal/database"
 	"github.com/jace-ys/hot-potato-discord/internal/discord"
 	"github.com/jace-ys/hot-potato-discord/internal/game"
 	"github.com/jace-ys/hot-potato-discord/internal/hotpotato"
@@ -26,10 +25,6 @@ import (
 
 var logger log.Logger
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 func main() {
 	c := parseCommand()
 
@@ -39,16 +34,84 @@ func main() {
 	logger = log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout))
 	logger = log.With(logger, "ts", log.DefaultTimestampUTC, "caller", log.DefaultCaller)
 
-	db, err := sql.Open("postgres", c.DatabaseURL)
-	if err != nil {
-		exit(fmt.Errorf("error opening database connection: %w", err))
+	var (
+		rooms    room.RoomRepository
+		games    game.GameRepository
+		tx       database.Transactor
+		migrator *database.Migrator
+	)
+
+	switch c.Storage {
+	case "memory":
+		memoryRooms, memoryGames := room.NewMemoryRepository(), game.NewMemoryRepository()
+		rooms, games = memoryRooms, memoryGames
+		tx = database.NewMutexTransactor(memoryRooms, memoryGames)
+	default:
+		if c.DatabaseURL == "" {
+			exit(errors.New("database URL is required for database storage"))
+		}
+
+		sqldb, dialect, err := database.Open(c.DatabaseURL)
+		if err != nil {
+			exit(fmt.Errorf("error opening database connection: %w", err))
+		}
+		level.Info(logger).Log("event", "database.opened", "dialect", dialect)
+
+		migrator = database.NewMigrator(logger, sqldb, dialect, db.Migrations(string(dialect)))
+		if !c.SkipMigrations {
+			version, err := migrator.Up(ctx)
+			if err != nil {
+				exit(fmt.Errorf("error applying migrations: %w", err))
+			}
+			level.Info(logger).Log("event", "migrations.completed", "version", version)
+		}
+
+		rooms = room.NewRepository(sqldb)
+		games = game.NewRepository(sqldb)
+		tx = database.NewTransactor(sqldb)
 	}
+	level.Info(logger).Log("event", "storage.configured", "storage", c.Storage)
 
-	rooms := room.NewRepository(db)
-	games := game.NewRepository(db)
-	gamemaster := hotpotato.NewGameMaster(logger, rooms, games)
+	if c.MigrateOnly {
+		return
+	}
 
-	bot, err := discord.NewBot(logger, gamemaster, c.DiscordToken, c.Port)
+	if c.DiscordToken == "" {
+		exit(errors.New("discord token is required"))
+	}
+
+	potatoes := hotpotato.DefaultPotatoRegistry()
+	if c.PotatoesFile != "" {
+		var err error
+		potatoes, err = hotpotato.LoadPotatoRegistry(c.PotatoesFile)
+		if err != nil {
+			exit(fmt.Errorf("error loading potatoes file: %w", err))
+		}
+	}
+
+	seed := c.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	level.Info(logger).Log("event", "randomizer.seeded", "seed", seed)
+
+	random := hotpotato.NewRandomizer(seed)
+	fuse := hotpotato.NewFuse(c.FuseTimeout)
+	cooldowns := hotpotato.NewCooldowns(map[game.Action]time.Duration{
+		game.ActionToss:  c.TossCooldown,
+		game.ActionSteal: c.StealCooldown,
+		game.ActionCook:  c.CookCooldown,
+	})
+	gamemaster := hotpotato.NewGameMaster(logger, rooms, games, tx, potatoes, random, fuse, cooldowns)
+
+	bot, err := discord.NewBot(logger, gamemaster, random, &discord.BotConfig{
+		Port:            c.Port,
+		DiscordToken:    c.DiscordToken,
+		PublicKey:       c.DiscordPublicKey,
+		Gateway:         c.DiscordGateway,
+		GuildID:         c.DiscordGuildID,
+		CleanupCommands: c.DiscordCleanupCommands,
+	})
 	if err != nil {
 		exit(fmt.Errorf("error initialising bot server: %w", err))
 	}
@@ -58,8 +121,14 @@ func main() {
 		exit(fmt.Errorf("error initialising admin server: %w", err))
 	}
 	admin.RegisterHealthChecks(bot)
+	if migrator != nil {
+		admin.RegisterSchemaVersioner(migrator)
+	}
 
 	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return gamemaster.Start(ctx)
+	})
 	g.Go(func() error {
 		return bot.Start(ctx)
 	})
@@ -82,10 +151,23 @@ func main() {
 }
 
 type config struct {
-	Port         int
-	AdminPort    int
-	DiscordToken string
-	DatabaseURL  string
+	Port                   int
+	AdminPort              int
+	DiscordToken           string
+	DiscordPublicKey       string
+	DiscordGateway         bool
+	DiscordGuildID         string
+	DiscordCleanupCommands bool
+	Storage                string
+	DatabaseURL            string
+	FuseTimeout            time.Duration
+	TossCooldown           time.Duration
+	StealCooldown          time.Duration
+	CookCooldown           time.Duration
+	PotatoesFile           string
+	Seed                   int64
+	MigrateOnly            bool
+	SkipMigrations         bool
 }
 
 func parseCommand() *config {
@@ -93,8 +175,21 @@ func parseCommand() *config {
 
 	kingpin.Flag("port", "Target port number for the Hot Potato Bot server.").Envar("PORT").Default("8080").IntVar(&c.Port)
 	kingpin.Flag("admin-port", "Target port number for the admin server.").Envar("ADMIN_PORT").Default("9090").IntVar(&c.AdminPort)
-	kingpin.Flag("discord-token", "Token for authenticating with Discord.").Envar("DISCORD_TOKEN").Required().StringVar(&c.DiscordToken)
-	kingpin.Flag("database-url", "URL for connecting to the Hot Potato Bot database.").Envar("DATABASE_URL").Required().StringVar(&c.DatabaseURL)
+	kingpin.Flag("discord-token", "Token for authenticating with Discord.").Envar("DISCORD_TOKEN").StringVar(&c.DiscordToken)
+	kingpin.Flag("discord-public-key", "Hex-encoded public key of the Discord application, for verifying interactions received over HTTP.").Envar("DISCORD_PUBLIC_KEY").StringVar(&c.DiscordPublicKey)
+	kingpin.Flag("discord-gateway", "Receive interactions over the Discord gateway. Disable to rely on the HTTP interactions endpoint only.").Envar("DISCORD_GATEWAY").Default("true").BoolVar(&c.DiscordGateway)
+	kingpin.Flag("discord-guild-id", "ID of a Discord guild to register commands in, instead of registering them globally.").Envar("DISCORD_GUILD_ID").StringVar(&c.DiscordGuildID)
+	kingpin.Flag("discord-cleanup-commands", "Remove the registered Discord commands on shutdown.").Envar("DISCORD_CLEANUP_COMMANDS").BoolVar(&c.DiscordCleanupCommands)
+	kingpin.Flag("storage", "Storage backend for rooms and games, either database or memory.").Envar("STORAGE").Default("database").EnumVar(&c.Storage, "database", "memory")
+	kingpin.Flag("database-url", "URL for connecting to the Hot Potato Bot database, either postgres:// or sqlite://.").Envar("DATABASE_URL").StringVar(&c.DatabaseURL)
+	kingpin.Flag("fuse-timeout", "Duration a potato can be held before it explodes on its own, or 0 to disable.").Envar("FUSE_TIMEOUT").Default("0s").DurationVar(&c.FuseTimeout)
+	kingpin.Flag("toss-cooldown", "Duration a user must wait between tosses, or 0 to disable.").Envar("TOSS_COOLDOWN").Default("0s").DurationVar(&c.TossCooldown)
+	kingpin.Flag("steal-cooldown", "Duration a user must wait between steals, or 0 to disable.").Envar("STEAL_COOLDOWN").Default("0s").DurationVar(&c.StealCooldown)
+	kingpin.Flag("cook-cooldown", "Duration a user must wait between cooks, or 0 to disable.").Envar("COOK_COOLDOWN").Default("0s").DurationVar(&c.CookCooldown)
+	kingpin.Flag("potatoes-file", "Path to a YAML or JSON file defining the kinds of potatoes in play.").Envar("POTATOES_FILE").StringVar(&c.PotatoesFile)
+	kingpin.Flag("seed", "Seed for the randomness used in games, or 0 to seed from the current time.").Envar("SEED").Default("0").Int64Var(&c.Seed)
+	kingpin.Flag("migrate-only", "Apply database migrations and exit without starting the bot.").Envar("MIGRATE_ONLY").BoolVar(&c.MigrateOnly)
+	kingpin.Flag("skip-migrations", "Start without applying database migrations.").Envar("SKIP_MIGRATIONS").BoolVar(&c.SkipMigrations)
 	kingpin.Parse()
 
 	return &c